@@ -1,19 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	logGo "log"
 	"strconv"
 	"time"
 	"user-service/configs"
+	middlewares "user-service/configs/middleware"
 	addressHandler "user-service/internal/modules/address/handlers"
+	addressRepoCache "user-service/internal/modules/address/repositories/caches"
+	addressRepoCommand "user-service/internal/modules/address/repositories/commands"
 	addressRepoQuery "user-service/internal/modules/address/repositories/queries"
 	addressUsecase "user-service/internal/modules/address/usecases"
 	userHandler "user-service/internal/modules/user/handlers"
+	userRequest "user-service/internal/modules/user/models/request"
 	userRepoCommand "user-service/internal/modules/user/repositories/commands"
 	userRepoQuery "user-service/internal/modules/user/repositories/queries"
 	userUsecase "user-service/internal/modules/user/usecases"
 	"user-service/internal/pkg/apm"
+	"user-service/internal/pkg/blobstore"
 	"user-service/internal/pkg/databases/mongodb"
 	graceful "user-service/internal/pkg/gs"
 	"user-service/internal/pkg/helpers"
@@ -28,9 +34,13 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"go.elastic.co/apm/module/apmfiber"
+	"go.mongodb.org/mongo-driver/bson"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
+// datasetVersionInterval is how often the search index and the dataset check the address cache version
+const datasetVersionInterval = 10 * time.Second
+
 // @BasePath	/
 func main() {
 	// Init Config
@@ -53,7 +63,7 @@ func main() {
 	mongo.SetCollections(&mongo)
 	mongo.InitConnection(configs.GetConfig().MongoDB.MongoMasterDBUrl, configs.GetConfig().MongoDB.MongoSlaveDBUrl)
 	// Init Logger
-	logZap := log.SetupLogger(configs.GetConfig().ServiceName)
+	logZap := log.SetupLogger(configs.GetConfig().ServiceName, loggerOptions(configs.GetConfig()))
 	log.Init(logZap)
 
 	// Init BlacklistedEmail
@@ -125,16 +135,161 @@ func setHttp(app *fiber.App, gs *graceful.GracefulShutdown) {
 		kafkaProducer,
 	)
 
+	ensureIndexes(context.Background(), mongoMasterClient, logger)
+
 	userQueryMongodbRepo := userRepoQuery.NewQueryMongodbRepository(mongoSlaveClient, logger)
 	userCommandMongodbRepo := userRepoCommand.NewCommandMongodbRepository(mongoMasterClient, logger)
 
-	addressQueryMongodbRepo := addressRepoQuery.NewQueryMongodbRepository(mongoSlaveClient, logger)
+	// Init Blob Store
+	blobStoreConfig := configs.GetConfig().BlobStore
+	var blobStore blobstore.BlobStore
+	if blobStoreConfig.Driver == "s3" {
+		blobStore = blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:  blobStoreConfig.S3Endpoint,
+			Region:    blobStoreConfig.S3Region,
+			Bucket:    blobStoreConfig.S3Bucket,
+			AccessKey: blobStoreConfig.S3AccessKey,
+			SecretKey: blobStoreConfig.S3SecretKey,
+			PublicUrl: blobStoreConfig.PublicUrl,
+		})
+	} else {
+		localPath := helpers.CustomIfEmpty(blobStoreConfig.LocalPath, "./storage")
+		blobStore = blobstore.NewLocalStore(localPath, helpers.CustomIfEmpty(blobStoreConfig.PublicUrl, "/media"))
+		app.Static("/media", localPath)
+	}
+
+	addressCacheStore := addressRepoCache.NewStore(redisClient, logger)
+	addressQueryMongodbRepo := addressRepoCache.NewQueryCacheRepository(addressRepoQuery.NewQueryMongodbRepository(mongoSlaveClient, logger), addressCacheStore)
+	addressCommandMongodbRepo := addressRepoCache.NewCommandCacheRepository(addressRepoCommand.NewCommandMongodbRepository(mongoMasterClient, logger), addressCacheStore)
 	addressUsecaseQuery := addressUsecase.NewQueryUsecase(addressQueryMongodbRepo, logger)
+	addressUsecaseCommand := addressUsecase.NewCommandUsecase(addressCommandMongodbRepo, addressQueryMongodbRepo, logger)
 
 	userUsecaseQuery := userUsecase.NewQueryUsecase(userQueryMongodbRepo, userCommandMongodbRepo, logger)
-	userUsecaseCommand := userUsecase.NewCommandUsecase(userQueryMongodbRepo, userCommandMongodbRepo, logger, redisClient, kafkaProducer, helperImpl, addressQueryMongodbRepo)
+	userUsecaseCommand := userUsecase.NewCommandUsecase(userQueryMongodbRepo, userCommandMongodbRepo, logger, redisClient, kafkaProducer, helperImpl, addressUsecaseQuery, blobStore)
 	// set module
 	userHandler.InitUserHttpHandler(app, userUsecaseCommand, userUsecaseQuery, logger, redisClient)
-	addressHandler.InitAddressHttpHandler(app, addressUsecaseQuery, logger, redisClient)
+	addressHandler.InitAddressHttpHandler(app, addressUsecaseCommand, addressUsecaseQuery, addressCacheStore, logger, redisClient)
+	app.Get("/api/errors/v1/catalog", helpers.ErrorCatalog(logger))
+	loggerAdmin := app.Group("/api/logger/v1/admin", middlewares.NewMiddlewares(redisClient).VerifyBearer(), middlewares.AllowedRoles(userRequest.RoleAdmin))
+	loggerAdmin.Get("/level", helpers.GetLogLevel(logger, log.GetLeveler()))
+	loggerAdmin.Put("/level", helpers.SetLogLevel(logger, log.GetLeveler()))
 
+	// set worker
+	workerInterval, err := strconv.Atoi(configs.GetConfig().AccountDeletion.WorkerInterval)
+	if err != nil || workerInterval <= 0 {
+		workerInterval = 60
+	}
+	accountDeletionTicker := time.NewTicker(time.Duration(workerInterval) * time.Minute)
+	go func() {
+		for range accountDeletionTicker.C {
+			if _, err := userUsecaseCommand.ProcessAccountDeletion(context.Background()); err != nil {
+				logger.Error(context.Background(), "Error process account deletion", err.Error())
+			}
+		}
+	}()
+	gs.Register(graceful.Fn(accountDeletionTicker.Stop))
+
+	dataExportInterval, err := strconv.Atoi(configs.GetConfig().DataExport.WorkerInterval)
+	if err != nil || dataExportInterval <= 0 {
+		dataExportInterval = 1
+	}
+	dataExportTicker := time.NewTicker(time.Duration(dataExportInterval) * time.Minute)
+	go func() {
+		for range dataExportTicker.C {
+			if _, err := userUsecaseCommand.ProcessDataExport(context.Background()); err != nil {
+				logger.Error(context.Background(), "Error process data export", err.Error())
+			}
+		}
+	}()
+	gs.Register(graceful.Fn(dataExportTicker.Stop))
+
+	searchRefreshInterval, err := strconv.Atoi(configs.GetConfig().AddressSearch.RefreshInterval)
+	if err != nil || searchRefreshInterval <= 0 {
+		searchRefreshInterval = 60
+	}
+	searchRefreshTicker := time.NewTicker(time.Duration(searchRefreshInterval) * time.Minute)
+	// Admin changes of the regions bump the cache version on every instance, the index and the dataset are
+	// rebuilt once the replicas have settled on the new version instead of at the next refresh
+	datasetVersionTicker := time.NewTicker(datasetVersionInterval)
+	go func() {
+		builtVersion := ""
+		refresh := func() {
+			version := addressCacheStore.Version(context.Background())
+			if err := addressUsecaseQuery.BuildSearchIndex(context.Background()); err != nil {
+				logger.Error(context.Background(), "Error build address search index", err.Error())
+			}
+			if err := addressUsecaseQuery.BuildDataset(context.Background()); err != nil {
+				logger.Error(context.Background(), "Error build address dataset", err.Error())
+			}
+			// A build within the settle window may miss the change, it is built again once settled
+			if !addressCacheStore.Settling(version) {
+				builtVersion = version
+			}
+		}
+		refresh()
+		for {
+			select {
+			case <-searchRefreshTicker.C:
+				refresh()
+			case <-datasetVersionTicker.C:
+				version := addressCacheStore.Version(context.Background())
+				if version != builtVersion && !addressCacheStore.Settling(version) {
+					refresh()
+				}
+			}
+		}
+	}()
+	gs.Register(graceful.Fn(searchRefreshTicker.Stop))
+	gs.Register(graceful.Fn(datasetVersionTicker.Stop))
+}
+
+// loggerOptions logs at debug when verbose unless a level is set, and writes JSON outside development
+func loggerOptions(config *configs.Config) log.Options {
+	options := log.Options{
+		Level:  config.Logger.Level,
+		Format: config.Logger.Format,
+	}
+	if options.Level == "" {
+		options.Level = "info"
+		if config.Logger.IsVerbose == "true" {
+			options.Level = "debug"
+		}
+	}
+	if options.Format == "" {
+		options.Format = log.FormatJSON
+		if config.ServiceEnv == "development" || config.ServiceEnv == "local" {
+			options.Format = log.FormatConsole
+		}
+	}
+	return options
+}
+
+// ensureIndexes creates the indexes the geo and audience queries rely on
+func ensureIndexes(ctx context.Context, mongoClient mongodb.Collections, logger log.Logger) {
+	indexes := []mongodb.CreateIndex{
+		{CollectionName: "subdistrict", Keys: bson.D{{Key: "centroid", Value: "2dsphere"}}, Name: "centroid_2dsphere"},
+		{CollectionName: "users", Keys: bson.D{{Key: "country.location", Value: "2dsphere"}}, Name: "country_location_2dsphere"},
+		{CollectionName: "users-address", Keys: bson.D{{Key: "country.location", Value: "2dsphere"}}, Name: "country_location_2dsphere"},
+		{CollectionName: "users", Keys: bson.D{{Key: "subdistrict.cityId", Value: 1}, {Key: "status", Value: 1}}, Name: "subdistrict_cityId_status"},
+		{CollectionName: "users", Keys: bson.D{{Key: "subdistrict.provinceId", Value: 1}, {Key: "status", Value: 1}}, Name: "subdistrict_provinceId_status"},
+		{CollectionName: "users", Keys: bson.D{{Key: "country.id", Value: 1}, {Key: "status", Value: 1}}, Name: "country_id_status"},
+		{CollectionName: "users", Keys: bson.D{{Key: "country.code", Value: 1}, {Key: "status", Value: 1}}, Name: "country_code_status"},
+		{CollectionName: "users-consent", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "purpose", Value: 1}}, Name: "userId_purpose"},
+		// Address upserts are keyed on it, concurrent upserts of one entry must not insert twice
+		{CollectionName: "users-address", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "addressId", Value: 1}}, Name: "userId_addressId", Unique: true},
+		// Keyset pages sort on the list order then _id
+		{CollectionName: "province", Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Name: "name_id"},
+		{CollectionName: "city", Keys: bson.D{{Key: "provinceId", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Name: "provinceId_name_id"},
+		{CollectionName: "district", Keys: bson.D{{Key: "cityId", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Name: "cityId_name_id"},
+		{CollectionName: "subdistrict", Keys: bson.D{{Key: "districtId", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Name: "districtId_name_id"},
+		{CollectionName: "subdistrict", Keys: bson.D{{Key: "id", Value: 1}, {Key: "_id", Value: 1}}, Name: "id_id"},
+		{CollectionName: "country", Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Name: "name_id"},
+		{CollectionName: "country", Keys: bson.D{{Key: "displayOrder", Value: 1}, {Key: "_id", Value: 1}}, Name: "displayOrder_id"},
+		{CollectionName: "users", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "_id", Value: 1}}, Name: "userId_id"},
+	}
+	for _, index := range indexes {
+		if resp := <-mongoClient.CreateIndex(index, ctx); resp.Error != nil {
+			logger.Error(ctx, fmt.Sprintf("Error create index %s on %s", index.Name, index.CollectionName), resp.Error.Error())
+		}
+	}
 }
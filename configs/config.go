@@ -25,10 +25,20 @@ type Config struct {
 	Jwt               JwtConfig        `envconfig:"jwt"`
 	UsernameBasicAuth string           `envconfig:"username_basic_auth"`
 	PasswordBasicAuth string           `envconfig:"password_basic_auth"`
-	ShutDownDelay     string           `envconfig:"shutdown_delay"`
-	SecretHashPass    string           `envconfig:"secret_hash_pass"`
-	IdHash            string           `envconfig:"id_hash"`
-	AppsLimiter       bool             `envconfig:"apps_limiter"`
+	// InternalAuth is the credential of the /v1/internal routes, shared only with other services
+	UsernameInternalAuth string `envconfig:"username_internal_auth"`
+	PasswordInternalAuth string `envconfig:"password_internal_auth"`
+	ShutDownDelay        string `envconfig:"shutdown_delay"`
+	SecretHashPass       string `envconfig:"secret_hash_pass"`
+	// SecretSignature signs the expiring download links, kept apart from SecretHashPass so each can be rotated on its own
+	SecretSignature string                `envconfig:"secret_signature"`
+	IdHash          string                `envconfig:"id_hash"`
+	AppsLimiter     bool                  `envconfig:"apps_limiter"`
+	AccountDeletion AccountDeletionConfig `envconfig:"account_deletion"`
+	DataExport      DataExportConfig      `envconfig:"data_export"`
+	Consent         ConsentConfig         `envconfig:"consent"`
+	BlobStore       BlobStoreConfig       `envconfig:"blob_store"`
+	AddressSearch   AddressSearchConfig   `envconfig:"address_search"`
 }
 
 type HttpServerConfig struct {
@@ -39,6 +49,9 @@ type HttpServerConfig struct {
 type LoggerConfig struct {
 	IsVerbose       string `envconfig:"logger_is_verbose"`
 	LoggerCollector string `envconfig:"logger_logger_collector"`
+	// Level overrides IsVerbose, Format is json or console and defaults to console in development only
+	Level  string `envconfig:"logger_level"`
+	Format string `envconfig:"logger_format"`
 }
 
 type DatabaseConfig struct {
@@ -87,6 +100,36 @@ type KafkaConfig struct {
 	KafkaPassword string `envconfig:"kafka_password"`
 }
 
+type AccountDeletionConfig struct {
+	GracePeriodDays string `envconfig:"account_deletion_grace_period_days"`
+	WorkerInterval  string `envconfig:"account_deletion_worker_interval"`
+}
+
+type DataExportConfig struct {
+	WorkerInterval string `envconfig:"data_export_worker_interval"`
+	LinkTTL        string `envconfig:"data_export_link_ttl"`
+}
+
+type ConsentConfig struct {
+	TermsVersion         string `envconfig:"consent_terms_version"`
+	PrivacyPolicyVersion string `envconfig:"consent_privacy_policy_version"`
+}
+
+type BlobStoreConfig struct {
+	Driver      string `envconfig:"blob_store_driver"`
+	LocalPath   string `envconfig:"blob_store_local_path"`
+	PublicUrl   string `envconfig:"blob_store_public_url"`
+	S3Endpoint  string `envconfig:"blob_store_s3_endpoint"`
+	S3Region    string `envconfig:"blob_store_s3_region"`
+	S3Bucket    string `envconfig:"blob_store_s3_bucket"`
+	S3AccessKey string `envconfig:"blob_store_s3_access_key"`
+	S3SecretKey string `envconfig:"blob_store_s3_secret_key"`
+}
+
+type AddressSearchConfig struct {
+	RefreshInterval string `envconfig:"address_search_refresh_interval"`
+}
+
 type JwtConfig struct {
 	JwtPrivateKey        string `envconfig:"private_key"`
 	JwtPublicKey         string `envconfig:"public_key"`
@@ -38,6 +38,22 @@ func (m Middlewares) VerifyBasicAuth() fiber.Handler {
 	})
 }
 
+// VerifyInternalAuth guards the routes called by other services with their own credential, an unset
+// credential rejects every request instead of letting an empty username and password in
+func (m Middlewares) VerifyInternalAuth() fiber.Handler {
+	username, password := config.GetConfig().UsernameInternalAuth, config.GetConfig().PasswordInternalAuth
+	if username == "" || password == "" {
+		return func(c *fiber.Ctx) error {
+			return helpers.RespError(c, log.GetLogger(), errors.UnauthorizedError("Internal credential is not configured"))
+		}
+	}
+	return basicauth.New(basicauth.Config{
+		Users: map[string]string{
+			username: password,
+		},
+	})
+}
+
 func (m Middlewares) VerifyBearer() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		logger := log.GetLogger()
@@ -72,6 +88,9 @@ func (m Middlewares) VerifyBearer() fiber.Handler {
 			if !ok {
 				return helpers.RespError(c, logger, errors.UnauthorizedError("Access token expired!"))
 			}
+			if convert.Status == userEntity.StatusDeleted {
+				return helpers.RespError(c, logger, errors.UnauthorizedError("Access token expired!"))
+			}
 			dataUser, _ := json.Marshal(userDto.UserData{
 				Data: userDto.UserResp{
 					FullName:  convert.FullName,
@@ -2,6 +2,7 @@ package address
 
 import (
 	"context"
+	"user-service/internal/modules/address/models/entity"
 	"user-service/internal/modules/address/models/request"
 	"user-service/internal/modules/address/models/response"
 	wrapper "user-service/internal/pkg/helpers"
@@ -12,8 +13,26 @@ type UsecaseQuery interface {
 	FindCities(origCtx context.Context, payload request.City) (*response.CityResp, error)
 	FindDistricts(origCtx context.Context, payload request.District) (*response.DistrictResp, error)
 	FindSubDistricts(origCtx context.Context, payload request.SubDistrict) (*response.SubDistrictResp, error)
+	FindByPostalCode(origCtx context.Context, payload request.PostalCode) ([]response.SubDistrict, error)
 	FindCountries(origCtx context.Context, payload request.Country) (*response.CountryResp, error)
-	FindContinent(origCtx context.Context) (*response.ContinentResp, error)
+	FindCountryByCode(origCtx context.Context, payload request.CountryCode) (*response.Country, error)
+	FindContinent(origCtx context.Context, payload request.Continent) (*response.ContinentResp, error)
+	ValidateHierarchy(origCtx context.Context, payload request.Hierarchy) (*response.Hierarchy, error)
+	SearchAddresses(origCtx context.Context, payload request.Search) ([]response.SearchResult, error)
+	ReverseGeocode(origCtx context.Context, payload request.ReverseGeocode) (*response.ReverseGeocode, error)
+	BuildSearchIndex(origCtx context.Context) error
+	ExportDataset(origCtx context.Context, payload request.Dataset) (*response.Dataset, error)
+	BuildDataset(origCtx context.Context) error
+}
+
+type UsecaseCommand interface {
+	CreateRegion(origCtx context.Context, payload request.Region) (*response.Region, error)
+	UpdateRegion(origCtx context.Context, payload request.Region) (*response.Region, error)
+	DeactivateRegion(origCtx context.Context, payload request.RegionId) error
+	CreateCountry(origCtx context.Context, payload request.SaveCountry) (*response.Country, error)
+	UpdateCountry(origCtx context.Context, payload request.SaveCountry) (*response.Country, error)
+	DeactivateCountry(origCtx context.Context, id int) error
+	ImportRegions(origCtx context.Context, payload request.ImportRegions) (*response.ImportReport, error)
 }
 
 type MongodbRepositoryQuery interface {
@@ -21,8 +40,41 @@ type MongodbRepositoryQuery interface {
 	FindCitiesByParam(ctx context.Context, payload request.City) <-chan wrapper.Result
 	FindDistrictByParam(ctx context.Context, payload request.District) <-chan wrapper.Result
 	FindSubDistrictByParam(ctx context.Context, payload request.SubDistrict) <-chan wrapper.Result
+	FindOneProvince(ctx context.Context, id string) <-chan wrapper.Result
+	FindOneCity(ctx context.Context, id string) <-chan wrapper.Result
+	FindOneDistrict(ctx context.Context, id string) <-chan wrapper.Result
 	FindOneSubdistrict(ctx context.Context, id string) <-chan wrapper.Result
+	FindAllSubdistricts(ctx context.Context, cursor string, size int64) <-chan wrapper.Result
+	FindAllRegions(ctx context.Context, level string, cursor string, size int64) <-chan wrapper.Result
+	FindSubdistrictsByPostalCode(ctx context.Context, postalCode string) <-chan wrapper.Result
+	FindNearestSubdistrict(ctx context.Context, latitude float64, longitude float64, maxDistance float64) <-chan wrapper.Result
 	FindCountries(ctx context.Context, payload request.Country) <-chan wrapper.Result
 	FindOneCountry(ctx context.Context, id int) <-chan wrapper.Result
+	FindOneCountryByCode(ctx context.Context, code string) <-chan wrapper.Result
 	FindContinent(ctx context.Context) <-chan wrapper.Result
+	CountActiveChildren(ctx context.Context, level string, id string) <-chan wrapper.Result
+}
+
+type MongodbRepositoryCommand interface {
+	UpsertOneProvince(ctx context.Context, province entity.Province) <-chan wrapper.Result
+	UpsertOneCity(ctx context.Context, city entity.City) <-chan wrapper.Result
+	UpsertOneDistrict(ctx context.Context, district entity.District) <-chan wrapper.Result
+	UpsertOneSubdistrict(ctx context.Context, subdistrict entity.SubDistrict) <-chan wrapper.Result
+	UpsertOneCountry(ctx context.Context, country entity.Country) <-chan wrapper.Result
+	UpdateProvinceReferences(ctx context.Context, province entity.Province) <-chan wrapper.Result
+	UpdateCityReferences(ctx context.Context, city entity.City) <-chan wrapper.Result
+	UpdateDistrictReferences(ctx context.Context, district entity.District) <-chan wrapper.Result
+	UpsertManyProvinces(ctx context.Context, provinces []entity.Province) <-chan wrapper.Result
+	UpsertManyCities(ctx context.Context, cities []entity.City) <-chan wrapper.Result
+	UpsertManyDistricts(ctx context.Context, districts []entity.District) <-chan wrapper.Result
+	UpsertManySubdistricts(ctx context.Context, subdistricts []entity.SubDistrict) <-chan wrapper.Result
+	UpsertManyCountries(ctx context.Context, countries []entity.Country) <-chan wrapper.Result
+	// Batch runs several writes as one change of the master data, run writes through the given repository
+	Batch(ctx context.Context, run func(command MongodbRepositoryCommand) error) error
+}
+
+type DatasetVersion interface {
+	Version(ctx context.Context) string
+	// Settling is true right after an invalidation, while the replicas may still serve the data before it
+	Settling(version string) bool
 }
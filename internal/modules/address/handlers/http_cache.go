@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/pkg/helpers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// datasetCacheControl lets clients and CDNs store reference data but revalidate it on every use, the
+// revalidation is a cheap 304 until an admin changes the dataset
+const datasetCacheControl = "public, no-cache"
+
+// DatasetCache tags reference data responses with the dataset version and answers conditional requests
+// with 304 before the usecase is called. Names are localized, so the tag also carries the languages.
+// Responses read within the settle window of a new version are not tagged nor stored.
+func (a AddressHttpHandler) DatasetCache(c *fiber.Ctx) error {
+	version := a.DatasetVersion.Version(c.Context())
+	if a.DatasetVersion.Settling(version) {
+		// The read may still be the data before the change, tagged with the new version a client would
+		// keep it until the next change
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+	etag := `"` + strings.Join(append([]string{version}, helpers.Languages(c)...), ":") + `"`
+	var lastModified time.Time
+	if nanos, err := strconv.ParseInt(version, 10, 64); err == nil && nanos > 0 {
+		lastModified = time.Unix(0, nanos).UTC()
+	}
+
+	c.Set(fiber.HeaderCacheControl, datasetCacheControl)
+	c.Vary(fiber.HeaderAcceptLanguage)
+	c.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	}
+	if notModified(c, etag, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+	if c.Response().StatusCode() != fiber.StatusOK {
+		// Errors are not part of the dataset and must not be cached under its version
+		c.Response().Header.Del(fiber.HeaderETag)
+		c.Response().Header.Del(fiber.HeaderLastModified)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+	}
+	return nil
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only when no ETag was sent
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+	modifiedSince := c.Get(fiber.HeaderIfModifiedSince)
+	if modifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(modifiedSince)
+	return err == nil && !lastModified.Truncate(time.Second).After(since)
+}
@@ -1,38 +1,70 @@
 package handlers
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 	"user-service/internal/modules/address"
 	"user-service/internal/modules/address/models/request"
+	userRequest "user-service/internal/modules/user/models/request"
 	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
 	"user-service/internal/pkg/log"
 	"user-service/internal/pkg/redis"
 
+	middlewares "user-service/configs/middleware"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type AddressHttpHandler struct {
-	AddressUsecaseQuery address.UsecaseQuery
-	Logger              log.Logger
-	Validator           *validator.Validate
+	AddressUsecaseCommand address.UsecaseCommand
+	AddressUsecaseQuery   address.UsecaseQuery
+	DatasetVersion        address.DatasetVersion
+	Logger                log.Logger
+	Validator             *validator.Validate
 }
 
-func InitAddressHttpHandler(app *fiber.App, auq address.UsecaseQuery, log log.Logger, redisClient redis.Collections) {
+func InitAddressHttpHandler(app *fiber.App, auc address.UsecaseCommand, auq address.UsecaseQuery, dv address.DatasetVersion, log log.Logger, redisClient redis.Collections) {
 	handler := &AddressHttpHandler{
-		AddressUsecaseQuery: auq,
-		Logger:              log,
-		Validator:           validator.New(),
+		AddressUsecaseCommand: auc,
+		AddressUsecaseQuery:   auq,
+		DatasetVersion:        dv,
+		Logger:                log,
+		Validator:             helpers.NewValidator(),
 	}
-	// middlewares := middlewares.NewMiddlewares(redisClient)
+	adminOnly := middlewares.AllowedRoles(userRequest.RoleAdmin)
+	middlewares := middlewares.NewMiddlewares(redisClient)
 	route := app.Group("/api/users/address")
 
-	route.Get("/v1/provinces", handler.GetProvinces)
-	route.Get("/v1/cities", handler.GetCities)
-	route.Get("/v1/districts", handler.GetDistricts)
-	route.Get("/v1/subdistricts", handler.GetSubDistricts)
-	route.Get("/v1/countries", handler.GetCountries)
-	route.Get("/v1/continent", handler.GetContinent)
+	route.Get("/v1/provinces", handler.DatasetCache, handler.GetProvinces)
+	route.Get("/v1/cities", handler.DatasetCache, handler.GetCities)
+	route.Get("/v1/districts", handler.DatasetCache, handler.GetDistricts)
+	route.Get("/v1/subdistricts", handler.DatasetCache, handler.GetSubDistricts)
+	route.Get("/v1/postal-codes/:code", handler.DatasetCache, handler.GetByPostalCode)
+	route.Get("/v1/countries", handler.DatasetCache, handler.GetCountries)
+	route.Get("/v1/countries/:code", handler.DatasetCache, handler.GetCountryByCode)
+	route.Get("/v1/continent", handler.DatasetCache, handler.GetContinent)
+	route.Get("/v1/search", handler.SearchAddresses)
+	route.Get("/v1/reverse-geocode", handler.DatasetCache, handler.ReverseGeocode)
+	route.Get("/v1/dataset", handler.ExportDataset)
+
+	admin := route.Group("/v1/admin", middlewares.VerifyBearer(), adminOnly)
+	admin.Post("/regions/:level", handler.CreateRegion)
+	admin.Put("/regions/:level/:id", handler.UpdateRegion)
+	admin.Delete("/regions/:level/:id", handler.DeactivateRegion)
+	admin.Post("/countries", handler.CreateCountry)
+	admin.Put("/countries/:id", handler.UpdateCountry)
+	admin.Delete("/countries/:id", handler.DeactivateCountry)
+	admin.Post("/import/:level", handler.ImportRegions)
 }
 
 func (a AddressHttpHandler) GetProvinces(c *fiber.Ctx) error {
@@ -42,8 +74,9 @@ func (a AddressHttpHandler) GetProvinces(c *fiber.Ctx) error {
 	}
 
 	if err := a.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, a.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
 	}
+	req.Languages = helpers.Languages(c)
 	resp, err := a.AddressUsecaseQuery.FindProvinces(c.Context(), *req)
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
@@ -58,8 +91,9 @@ func (a AddressHttpHandler) GetCities(c *fiber.Ctx) error {
 	}
 
 	if err := a.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, a.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
 	}
+	req.Languages = helpers.Languages(c)
 	resp, err := a.AddressUsecaseQuery.FindCities(c.Context(), *req)
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
@@ -74,8 +108,9 @@ func (a AddressHttpHandler) GetDistricts(c *fiber.Ctx) error {
 	}
 
 	if err := a.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, a.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
 	}
+	req.Languages = helpers.Languages(c)
 	resp, err := a.AddressUsecaseQuery.FindDistricts(c.Context(), *req)
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
@@ -90,8 +125,9 @@ func (a AddressHttpHandler) GetSubDistricts(c *fiber.Ctx) error {
 	}
 
 	if err := a.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, a.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
 	}
+	req.Languages = helpers.Languages(c)
 	resp, err := a.AddressUsecaseQuery.FindSubDistricts(c.Context(), *req)
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
@@ -99,6 +135,21 @@ func (a AddressHttpHandler) GetSubDistricts(c *fiber.Ctx) error {
 	return helpers.RespPagination(c, a.Logger, resp.CollectionData, resp.MetaData, "Get subdistrict success")
 }
 
+func (a AddressHttpHandler) GetByPostalCode(c *fiber.Ctx) error {
+	req := request.PostalCode{
+		Code:      c.Params("code"),
+		Languages: helpers.Languages(c),
+	}
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseQuery.FindByPostalCode(c.Context(), req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Get postal code success")
+}
+
 func (a AddressHttpHandler) GetCountries(c *fiber.Ctx) error {
 	req := new(request.Country)
 	if err := c.QueryParser(req); err != nil {
@@ -106,8 +157,9 @@ func (a AddressHttpHandler) GetCountries(c *fiber.Ctx) error {
 	}
 
 	if err := a.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, a.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
 	}
+	req.Languages = helpers.Languages(c)
 	resp, err := a.AddressUsecaseQuery.FindCountries(c.Context(), *req)
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
@@ -115,10 +167,238 @@ func (a AddressHttpHandler) GetCountries(c *fiber.Ctx) error {
 	return helpers.RespPagination(c, a.Logger, resp.CollectionData, resp.MetaData, "Get country success")
 }
 
+func (a AddressHttpHandler) GetCountryByCode(c *fiber.Ctx) error {
+	req := request.CountryCode{
+		Code:      c.Params("code"),
+		Languages: helpers.Languages(c),
+	}
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseQuery.FindCountryByCode(c.Context(), req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Get country success")
+}
+
 func (a AddressHttpHandler) GetContinent(c *fiber.Ctx) error {
-	resp, err := a.AddressUsecaseQuery.FindContinent(c.Context())
+	resp, err := a.AddressUsecaseQuery.FindContinent(c.Context(), request.Continent{Languages: helpers.Languages(c)})
 	if err != nil {
 		return helpers.RespCustomError(c, a.Logger, err)
 	}
 	return helpers.RespPagination(c, a.Logger, resp.CollectionData, resp.MetaData, "Get continent success")
 }
+
+func (a AddressHttpHandler) SearchAddresses(c *fiber.Ctx) error {
+	req := new(request.Search)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	req.Languages = helpers.Languages(c)
+	resp, err := a.AddressUsecaseQuery.SearchAddresses(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Search address success")
+}
+
+func (a AddressHttpHandler) CreateRegion(c *fiber.Ctx) error {
+	req := new(request.Region)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+	req.Level = c.Params("level")
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseCommand.CreateRegion(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Create region success")
+}
+
+func (a AddressHttpHandler) UpdateRegion(c *fiber.Ctx) error {
+	req := new(request.Region)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+	req.Level = c.Params("level")
+	req.Id = c.Params("id")
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseCommand.UpdateRegion(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Update region success")
+}
+
+func (a AddressHttpHandler) DeactivateRegion(c *fiber.Ctx) error {
+	req := request.RegionId{
+		Level: c.Params("level"),
+		Id:    c.Params("id"),
+	}
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	if err := a.AddressUsecaseCommand.DeactivateRegion(c.Context(), req); err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, nil, "Deactivate region success")
+}
+
+func (a AddressHttpHandler) CreateCountry(c *fiber.Ctx) error {
+	req := new(request.SaveCountry)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseCommand.CreateCountry(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Create country success")
+}
+
+func (a AddressHttpHandler) UpdateCountry(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("country id must integer"))
+	}
+	req := new(request.SaveCountry)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+	req.Id = id
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseCommand.UpdateCountry(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Update country success")
+}
+
+func (a AddressHttpHandler) DeactivateCountry(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("country id must integer"))
+	}
+	if err := a.AddressUsecaseCommand.DeactivateCountry(c.Context(), id); err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, nil, "Deactivate country success")
+}
+
+// ImportRegions takes the rows as a multipart "file" or as the raw body, the format defaults to the file extension or content type
+func (a AddressHttpHandler) ImportRegions(c *fiber.Ctx) error {
+	req := new(request.ImportRegions)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+	req.Level = c.Params("level")
+
+	if file, err := c.FormFile("file"); err == nil {
+		if req.Format == "" {
+			req.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		}
+		f, err := file.Open()
+		if err != nil {
+			return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+		}
+		defer f.Close()
+		if req.Content, err = io.ReadAll(f); err != nil {
+			return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+		}
+	} else {
+		contentType := c.Get(fiber.HeaderContentType)
+		if req.Format == "" && strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+			req.Format = "json"
+		} else if req.Format == "" && strings.HasPrefix(contentType, "text/csv") {
+			req.Format = "csv"
+		}
+		req.Content = c.Body()
+	}
+
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseCommand.ImportRegions(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Import regions success")
+}
+
+func (a AddressHttpHandler) ReverseGeocode(c *fiber.Ctx) error {
+	req := new(request.ReverseGeocode)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	req.Languages = helpers.Languages(c)
+	resp, err := a.AddressUsecaseQuery.ReverseGeocode(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+	return helpers.RespSuccess(c, a.Logger, resp, "Reverse geocode success")
+}
+
+// ExportDataset serves the offline address bundle, the gzip format downloads it as a file. The bundle is
+// tagged with its version and hash so a client holding it revalidates with a 304.
+func (a AddressHttpHandler) ExportDataset(c *fiber.Ctx) error {
+	req := new(request.Dataset)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, a.Logger, errors.BadRequest("bad request"))
+	}
+
+	if err := a.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, a.Logger, helpers.ValidationError(err))
+	}
+	resp, err := a.AddressUsecaseQuery.ExportDataset(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, a.Logger, err)
+	}
+
+	etag := `"` + resp.Version + ":" + resp.Hash + `"`
+	var lastModified time.Time
+	if millis, err := strconv.ParseInt(resp.Version, 10, 64); err == nil && millis > 0 {
+		lastModified = time.UnixMilli(millis).UTC()
+	}
+	c.Set(fiber.HeaderCacheControl, datasetCacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	}
+	if notModified(c, etag, lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	if req.Format != "gzip" {
+		return helpers.RespSuccess(c, a.Logger, resp, "Export dataset success")
+	}
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	if err := json.NewEncoder(writer).Encode(resp); err != nil {
+		return helpers.RespError(c, a.Logger, errors.InternalServerError("cannot encode dataset"))
+	}
+	if err := writer.Close(); err != nil {
+		return helpers.RespError(c, a.Logger, errors.InternalServerError("cannot encode dataset"))
+	}
+	c.Set(fiber.HeaderContentType, "application/gzip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"address-dataset-%s.json.gz\"", resp.Version))
+	return c.Send(body.Bytes())
+}
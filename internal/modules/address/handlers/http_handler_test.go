@@ -3,12 +3,19 @@
 package handlers_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"mime/multipart"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"user-service/internal/modules/address/handlers"
+	"user-service/internal/modules/address/models/request"
 	"user-service/internal/modules/address/models/response"
 	"user-service/internal/pkg/constants"
 	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
 	mockcert "user-service/mocks/modules/address"
 	mocklog "user-service/mocks/pkg/log"
 	mockredis "user-service/mocks/pkg/redis"
@@ -24,7 +31,9 @@ import (
 type AddressHttpHandlerTestSuite struct {
 	suite.Suite
 
+	cUC       *mockcert.UsecaseCommand
 	cUQ       *mockcert.UsecaseQuery
+	cDV       *mockcert.DatasetVersion
 	cLog      *mocklog.Logger
 	validator *validator.Validate
 	handler   *handlers.AddressHttpHandler
@@ -33,17 +42,21 @@ type AddressHttpHandlerTestSuite struct {
 }
 
 func (suite *AddressHttpHandlerTestSuite) SetupTest() {
+	suite.cUC = new(mockcert.UsecaseCommand)
 	suite.cUQ = new(mockcert.UsecaseQuery)
+	suite.cDV = new(mockcert.DatasetVersion)
 	suite.cLog = new(mocklog.Logger)
-	suite.validator = validator.New()
+	suite.validator = helpers.NewValidator()
 	suite.cRedis = new(mockredis.Collections)
 	suite.handler = &handlers.AddressHttpHandler{
-		AddressUsecaseQuery: suite.cUQ,
-		Logger:              suite.cLog,
-		Validator:           suite.validator,
+		AddressUsecaseCommand: suite.cUC,
+		AddressUsecaseQuery:   suite.cUQ,
+		DatasetVersion:        suite.cDV,
+		Logger:                suite.cLog,
+		Validator:             suite.validator,
 	}
 	suite.app = fiber.New()
-	handlers.InitAddressHttpHandler(suite.app, suite.cUQ, suite.cLog, suite.cRedis)
+	handlers.InitAddressHttpHandler(suite.app, suite.cUC, suite.cUQ, suite.cDV, suite.cLog, suite.cRedis)
 }
 
 func TestUserHttpHandlerTestSuite(t *testing.T) {
@@ -125,6 +138,20 @@ func (suite *AddressHttpHandlerTestSuite) TestGetProvincesErrValidator() {
 	assert.Nil(suite.T(), err)
 }
 
+func (suite *AddressHttpHandlerTestSuite) TestGetProvincesErrSizeBelowOne() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.cLog.On("Error", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/provinces", suite.handler.GetProvinces)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/provinces?size=-1", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "FindProvinces", mock.Anything, mock.Anything)
+}
+
 func (suite *AddressHttpHandlerTestSuite) TestGetProvincesErr() {
 	suite.cUQ.On("FindProvinces", mock.Anything, mock.Anything).Return(nil, errors.BadRequest("error"))
 	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -553,3 +580,479 @@ func (suite *AddressHttpHandlerTestSuite) TestGetContinentErr() {
 	err := suite.handler.GetContinent(ctx)
 	assert.Nil(suite.T(), err)
 }
+
+func (suite *AddressHttpHandlerTestSuite) TestCreateRegion() {
+	suite.cUC.On("CreateRegion", mock.Anything, request.Region{Level: "city", Id: "9271", Name: "Kota Sorong", ParentId: "96"}).Return(&response.Region{
+		Level:        "city",
+		Id:           "9271",
+		Name:         "Kota Sorong",
+		ProvinceId:   "96",
+		ProvinceName: "Papua Barat Daya",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/admin/regions/:level", suite.handler.CreateRegion)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/admin/regions/city", strings.NewReader(`{"id":"9271","name":"Kota Sorong","parentId":"96"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestCreateRegionErrLevel() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/admin/regions/:level", suite.handler.CreateRegion)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/admin/regions/village", strings.NewReader(`{"id":"1","name":"Desa"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUC.AssertNotCalled(suite.T(), "CreateRegion", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestUpdateRegion() {
+	suite.cUC.On("UpdateRegion", mock.Anything, request.Region{Level: "city", Id: "9271", Name: "Kota Sorong", ParentId: "96"}).Return(&response.Region{
+		Level: "city",
+		Id:    "9271",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/v1/admin/regions/:level/:id", suite.handler.UpdateRegion)
+
+	req := httptest.NewRequest(fiber.MethodPut, "/v1/admin/regions/city/9271", strings.NewReader(`{"id":"ignored","name":"Kota Sorong","parentId":"96"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDeactivateRegionErr() {
+	suite.cUC.On("DeactivateRegion", mock.Anything, request.RegionId{Level: "province", Id: "91"}).Return(errors.UnprocessableEntity("Region still has active children, move or deactivate them first"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Delete("/v1/admin/regions/:level/:id", suite.handler.DeactivateRegion)
+
+	req := httptest.NewRequest(fiber.MethodDelete, "/v1/admin/regions/province/91", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestUpdateCountryErrId() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/v1/admin/countries/:id", suite.handler.UpdateCountry)
+
+	req := httptest.NewRequest(fiber.MethodPut, "/v1/admin/countries/abc", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestCreateCountry() {
+	suite.cUC.On("CreateCountry", mock.Anything, mock.Anything).Return(&response.Country{Id: 1, Code: "ID"}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/admin/countries", suite.handler.CreateCountry)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/admin/countries", strings.NewReader(`{"id":1,"code":"ID","name":"Indonesia","continentCode":"AS"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestImportRegionsMultipart() {
+	content := "id,name,parentId\n9271,Kota Sorong,96\n"
+	suite.cUC.On("ImportRegions", mock.Anything, request.ImportRegions{
+		Level:   "city",
+		Format:  "csv",
+		DryRun:  true,
+		Content: []byte(content),
+	}).Return(&response.ImportReport{Level: "city", DryRun: true, Total: 1, Created: 1}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/admin/import/:level", suite.handler.ImportRegions)
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "cities.csv")
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/admin/import/city?dryRun=true", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestImportRegionsErrRows() {
+	suite.cUC.On("ImportRegions", mock.Anything, mock.Anything).Return(nil, errors.WithFields(errors.UnprocessableEntity("Import has invalid rows"), map[string]string{
+		"rows[1].parentId": "province not found",
+	}))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/admin/import/:level", suite.handler.ImportRegions)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/admin/import/city", strings.NewReader(`[{"id":"9271","name":"Kota Sorong","parentId":"99"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusUnprocessableEntity, resp.StatusCode)
+	suite.cUC.AssertCalled(suite.T(), "ImportRegions", mock.Anything, mock.MatchedBy(func(req request.ImportRegions) bool {
+		return req.Format == "json" && !req.DryRun
+	}))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestSearchAddresses() {
+	suite.cUQ.On("SearchAddresses", mock.Anything, request.Search{Query: "kebayoran baru", Limit: 5}).Return([]response.SearchResult{
+		{SubDistrict: response.SubDistrict{Id: "3174071001", Name: "SELONG"}, Score: 5.5},
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/search", suite.handler.SearchAddresses)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/search?q=kebayoran+baru&limit=5", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestSearchAddressesErrValidator() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/search", suite.handler.SearchAddresses)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/search?q=ke", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "SearchAddresses", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheHeaders() {
+	suite.cDV.On("Version", mock.Anything).Return("1700000000000000000")
+	suite.cDV.On("Settling", "1700000000000000000").Return(false)
+	suite.cUQ.On("FindCountries", mock.Anything, mock.Anything).Return(&response.CountryResp{}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries", suite.handler.DatasetCache, suite.handler.GetCountries)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), `"1700000000000000000"`, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(suite.T(), "public, no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+	assert.Equal(suite.T(), "Tue, 14 Nov 2023 22:13:20 GMT", resp.Header.Get(fiber.HeaderLastModified))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheLanguages() {
+	suite.cDV.On("Version", mock.Anything).Return("1700000000000000000")
+	suite.cDV.On("Settling", "1700000000000000000").Return(false)
+	suite.cUQ.On("FindCountries", mock.Anything, request.Country{
+		Page:      1,
+		Size:      10,
+		Languages: []string{"fr", "en-gb", "en"},
+	}).Return(&response.CountryResp{}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries", suite.handler.DatasetCache, suite.handler.GetCountries)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10&lang=FR", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "en;q=0.8, *;q=0.5, en-GB, de;q=0")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), `"1700000000000000000:fr:en-gb:en"`, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(suite.T(), fiber.HeaderAcceptLanguage, resp.Header.Get(fiber.HeaderVary))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheNotModified() {
+	suite.cDV.On("Version", mock.Anything).Return("1700000000000000000")
+	suite.cDV.On("Settling", "1700000000000000000").Return(false)
+
+	app := fiber.New()
+	app.Get("/v1/countries", suite.handler.DatasetCache, suite.handler.GetCountries)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, `W/"1", "1700000000000000000"`)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusNotModified, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "FindCountries", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheModifiedSince() {
+	suite.cDV.On("Version", mock.Anything).Return("1700000000000000000")
+	suite.cDV.On("Settling", "1700000000000000000").Return(false)
+	suite.cUQ.On("FindCountries", mock.Anything, mock.Anything).Return(&response.CountryResp{}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries", suite.handler.DatasetCache, suite.handler.GetCountries)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, "Tue, 14 Nov 2023 22:13:20 GMT")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusNotModified, resp.StatusCode)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10", nil)
+	req.Header.Set(fiber.HeaderIfModifiedSince, "Mon, 13 Nov 2023 00:00:00 GMT")
+	resp, err = app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheSettlingNotTagged() {
+	suite.cDV.On("Version", mock.Anything).Return("1700000000000000000")
+	suite.cDV.On("Settling", "1700000000000000000").Return(true)
+	suite.cUQ.On("FindCountries", mock.Anything, mock.Anything).Return(&response.CountryResp{}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries", suite.handler.DatasetCache, suite.handler.GetCountries)
+
+	// A client holding the new tag still gets the data again while the replicas settle
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/countries?page=1&size=10", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, `"1700000000000000000"`)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Empty(suite.T(), resp.Header.Get(fiber.HeaderETag))
+	assert.Empty(suite.T(), resp.Header.Get(fiber.HeaderLastModified))
+	assert.Equal(suite.T(), "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestDatasetCacheErrNotTagged() {
+	suite.cDV.On("Version", mock.Anything).Return("0")
+	suite.cDV.On("Settling", "0").Return(false)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/provinces", suite.handler.DatasetCache, suite.handler.GetProvinces)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/provinces", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	assert.Empty(suite.T(), resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(suite.T(), "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetByPostalCode() {
+	suite.cUQ.On("FindByPostalCode", mock.Anything, request.PostalCode{Code: "98414"}).Return([]response.SubDistrict{
+		{Id: "9171011001", Name: "Klademak", PostalCode: "98414"},
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/postal-codes/:code", suite.handler.GetByPostalCode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/postal-codes/98414", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetByPostalCodeErrValidator() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/postal-codes/:code", suite.handler.GetByPostalCode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/postal-codes/9841A", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "FindByPostalCode", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestReverseGeocode() {
+	latitude, longitude := -0.88, 131.28
+	suite.cUQ.On("ReverseGeocode", mock.Anything, request.ReverseGeocode{Latitude: &latitude, Longitude: &longitude}).Return(&response.ReverseGeocode{
+		SubDistrict: response.SubDistrict{Id: "9171011001", Name: "Klademak"},
+		Distance:    120,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/reverse-geocode", suite.handler.ReverseGeocode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/reverse-geocode?lat=-0.88&lng=131.28", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestReverseGeocodeErrValidator() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/reverse-geocode", suite.handler.ReverseGeocode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/reverse-geocode?lat=-95&lng=131.28", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/reverse-geocode?lng=131.28", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "ReverseGeocode", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetCountryByCode() {
+	suite.cUQ.On("FindCountryByCode", mock.Anything, request.CountryCode{Code: "IDN"}).Return(&response.Country{
+		Id: 100, Name: "Indonesia", Code: "ID", Iso3: "IDN", Number: 360,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries/:code", suite.handler.GetCountryByCode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/countries/IDN", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetCountryByCodeErrValidator() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/countries/:code", suite.handler.GetCountryByCode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/countries/INDO", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "FindCountryByCode", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetProvincesByCursor() {
+	suite.cUQ.On("FindProvinces", mock.Anything, request.Province{Size: 10, Cursor: "abc", Count: true}).Return(&response.ProvinceResp{
+		CollectionData: []response.Province{{Id: "11", Name: "ACEH"}},
+		MetaData:       constants.MetaData{Count: 1, NextCursor: "def"},
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/provinces", suite.handler.GetProvinces)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/provinces?size=10&cursor=abc&count=true", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestGetProvincesErrPageWithCursor() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/provinces", suite.handler.GetProvinces)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/provinces?page=2&size=10&cursor=abc", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "FindProvinces", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestExportDataset() {
+	suite.cUQ.On("ExportDataset", mock.Anything, request.Dataset{}).Return(&response.Dataset{
+		Version: "1700000000000", Hash: "abc", Full: true,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/dataset", suite.handler.ExportDataset)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/dataset", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), `"1700000000000:abc"`, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(suite.T(), "Tue, 14 Nov 2023 22:13:20 GMT", resp.Header.Get(fiber.HeaderLastModified))
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestExportDatasetGzip() {
+	suite.cUQ.On("ExportDataset", mock.Anything, request.Dataset{Since: "1690000000000", Format: "gzip"}).Return(&response.Dataset{
+		Version: "1700000000000", Hash: "abc", Since: "1690000000000",
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/v1/dataset", suite.handler.ExportDataset)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/dataset?since=1690000000000&format=gzip", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "application/gzip", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(suite.T(), `attachment; filename="address-dataset-1700000000000.json.gz"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+	reader, err := gzip.NewReader(resp.Body)
+	assert.Nil(suite.T(), err)
+	var dataset response.Dataset
+	assert.Nil(suite.T(), json.NewDecoder(reader).Decode(&dataset))
+	assert.Equal(suite.T(), "1690000000000", dataset.Since)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestExportDatasetNotModified() {
+	suite.cUQ.On("ExportDataset", mock.Anything, request.Dataset{}).Return(&response.Dataset{
+		Version: "1700000000000", Hash: "abc", Full: true,
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/v1/dataset", suite.handler.ExportDataset)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/dataset", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, `"1700000000000:abc"`)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusNotModified, resp.StatusCode)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestExportDatasetErrValidator() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/dataset", suite.handler.ExportDataset)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/dataset?format=xml", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUQ.AssertNotCalled(suite.T(), "ExportDataset", mock.Anything, mock.Anything)
+}
+
+func (suite *AddressHttpHandlerTestSuite) TestExportDatasetErrValidatorProblem() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/dataset", suite.handler.ExportDataset)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/dataset?format=xml&since=abc", nil)
+	req.Header.Set(fiber.HeaderAccept, helpers.ProblemContentType)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+
+	var body struct {
+		Instance string              `json:"instance"`
+		Errors   []errors.FieldError `json:"errors"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "/v1/dataset?format=xml&since=abc", body.Instance)
+	assert.Equal(suite.T(), []errors.FieldError{
+		{Field: "since", Rule: "numeric", Message: "must be numeric"},
+		{Field: "format", Rule: "oneof", Message: "must be one of json, gzip"},
+	}, body.Errors)
+}
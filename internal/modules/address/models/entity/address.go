@@ -1,50 +1,101 @@
 package entity
 
+import "time"
+
+// Names maps a lowercase BCP 47 language tag to the name in that language, Name stays the default
+// shown when no requested language has one. Parent names are copied with their localized names.
+// UpdatedAt is the last time the region itself was saved, it is zero on regions never saved since seeding.
 type Province struct {
-	Id   string `json:"id" bson:"id"`
-	Name string `json:"name" bson:"name"`
+	Id          string            `json:"id" bson:"id"`
+	Name        string            `json:"name" bson:"name"`
+	Names       map[string]string `json:"names,omitempty" bson:"names"`
+	Deactivated bool              `json:"deactivated" bson:"deactivated"`
+	UpdatedAt   time.Time         `json:"updatedAt" bson:"updatedAt"`
 }
 
 type City struct {
-	Id           string `json:"id" bson:"id"`
-	Name         string `json:"name" bson:"name"`
-	ProvinceId   string `json:"provinceId" bson:"provinceId"`
-	ProvinceName string `json:"provinceName" bson:"provinceName"`
+	Id            string            `json:"id" bson:"id"`
+	Name          string            `json:"name" bson:"name"`
+	Names         map[string]string `json:"names,omitempty" bson:"names"`
+	ProvinceId    string            `json:"provinceId" bson:"provinceId"`
+	ProvinceName  string            `json:"provinceName" bson:"provinceName"`
+	ProvinceNames map[string]string `json:"provinceNames,omitempty" bson:"provinceNames"`
+	Deactivated   bool              `json:"deactivated" bson:"deactivated"`
+	UpdatedAt     time.Time         `json:"updatedAt" bson:"updatedAt"`
 }
 
 type District struct {
-	Id           string `json:"id" bson:"id"`
-	Name         string `json:"name" bson:"name"`
-	CityId       string `json:"cityId" bson:"cityId"`
-	CityName     string `json:"cityName" bson:"cityName"`
-	ProvinceId   string `json:"provinceId" bson:"provinceId"`
-	ProvinceName string `json:"provinceName" bson:"provinceName"`
+	Id            string            `json:"id" bson:"id"`
+	Name          string            `json:"name" bson:"name"`
+	Names         map[string]string `json:"names,omitempty" bson:"names"`
+	CityId        string            `json:"cityId" bson:"cityId"`
+	CityName      string            `json:"cityName" bson:"cityName"`
+	CityNames     map[string]string `json:"cityNames,omitempty" bson:"cityNames"`
+	ProvinceId    string            `json:"provinceId" bson:"provinceId"`
+	ProvinceName  string            `json:"provinceName" bson:"provinceName"`
+	ProvinceNames map[string]string `json:"provinceNames,omitempty" bson:"provinceNames"`
+	Deactivated   bool              `json:"deactivated" bson:"deactivated"`
+	UpdatedAt     time.Time         `json:"updatedAt" bson:"updatedAt"`
 }
 
 type SubDistrict struct {
-	Id           string `json:"id" bson:"id"`
-	Name         string `json:"name" bson:"name"`
-	DistrictId   string `json:"districtId" bson:"districtId"`
-	DistrictName string `json:"districtName" bson:"districtName"`
-	CityId       string `json:"cityId" bson:"cityId"`
-	CityName     string `json:"cityName" bson:"cityName"`
-	ProvinceId   string `json:"provinceId" bson:"provinceId"`
-	ProvinceName string `json:"provinceName" bson:"provinceName"`
+	Id            string            `json:"id" bson:"id"`
+	Name          string            `json:"name" bson:"name"`
+	Names         map[string]string `json:"names,omitempty" bson:"names"`
+	DistrictId    string            `json:"districtId" bson:"districtId"`
+	DistrictName  string            `json:"districtName" bson:"districtName"`
+	DistrictNames map[string]string `json:"districtNames,omitempty" bson:"districtNames"`
+	CityId        string            `json:"cityId" bson:"cityId"`
+	CityName      string            `json:"cityName" bson:"cityName"`
+	CityNames     map[string]string `json:"cityNames,omitempty" bson:"cityNames"`
+	ProvinceId    string            `json:"provinceId" bson:"provinceId"`
+	ProvinceName  string            `json:"provinceName" bson:"provinceName"`
+	ProvinceNames map[string]string `json:"provinceNames,omitempty" bson:"provinceNames"`
+	PostalCode    string            `json:"postalCode" bson:"postalCode"`
+	// Centroid backs the reverse geocoding, subdistricts without one are never matched
+	Centroid    *GeoPoint `json:"centroid,omitempty" bson:"centroid,omitempty"`
+	Deactivated bool      `json:"deactivated" bson:"deactivated"`
+	UpdatedAt   time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// SubDistrictDistance is a subdistrict found by a geo query with its distance in meters
+type SubDistrictDistance struct {
+	SubDistrict `bson:",inline"`
+	Distance    float64 `json:"distance" bson:"distance"`
+}
+
+// GeoPoint is a GeoJSON point, Coordinates holds the longitude then the latitude
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// Bounds is the bounding box of a country, MinLongitude is greater than MaxLongitude when it crosses the antimeridian
+type Bounds struct {
+	MinLatitude  float64 `json:"minLatitude" bson:"minLatitude"`
+	MinLongitude float64 `json:"minLongitude" bson:"minLongitude"`
+	MaxLatitude  float64 `json:"maxLatitude" bson:"maxLatitude"`
+	MaxLongitude float64 `json:"maxLongitude" bson:"maxLongitude"`
 }
 
 type Country struct {
-	Id            int    `json:"id" bson:"id"`
-	Code          string `json:"code" bson:"code"`
-	Name          string `json:"name" bson:"name"`
-	Iso3          string `json:"iso3" bson:"iso3"`
-	Number        int    `json:"number" bson:"number"`
-	ContinentCode string `json:"continentCode" bson:"continentCode"`
-	ContinentName string `json:"continentName" bson:"continentName"`
-	DisplayOrder  int    `json:"displayOrder" bson:"displayOrder"`
-	FullName      string `json:"fullName" bson:"fullName"`
+	Id             int               `json:"id" bson:"id"`
+	Code           string            `json:"code" bson:"code"`
+	Name           string            `json:"name" bson:"name"`
+	Names          map[string]string `json:"names,omitempty" bson:"names"`
+	Iso3           string            `json:"iso3" bson:"iso3"`
+	Number         int               `json:"number" bson:"number"`
+	ContinentCode  string            `json:"continentCode" bson:"continentCode"`
+	ContinentName  string            `json:"continentName" bson:"continentName"`
+	ContinentNames map[string]string `json:"continentNames,omitempty" bson:"continentNames"`
+	DisplayOrder   int               `json:"displayOrder" bson:"displayOrder"`
+	FullName       string            `json:"fullName" bson:"fullName"`
+	Bounds         *Bounds           `json:"bounds,omitempty" bson:"bounds,omitempty"`
+	Deactivated    bool              `json:"deactivated" bson:"deactivated"`
 }
 
 type Continent struct {
-	Code string `json:"code" bson:"code"`
-	Name string `json:"name" bson:"name"`
+	Code  string            `json:"code" bson:"code"`
+	Name  string            `json:"name" bson:"name"`
+	Names map[string]string `json:"names,omitempty" bson:"names"`
 }
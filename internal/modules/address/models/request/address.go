@@ -1,37 +1,163 @@
 package request
 
+const (
+	LevelProvince    = `province`
+	LevelCity        = `city`
+	LevelDistrict    = `district`
+	LevelSubdistrict = `subdistrict`
+	LevelCountry     = `country`
+)
+
+// Province pages by page and size, or by cursor when no page is given. The first cursor page has no
+// cursor, the next ones pass the nextCursor of the previous page and Count adds the totals.
 type Province struct {
-	Page   int64  `query:"page" validate:"required"`
-	Size   int64  `query:"size" validate:"required"`
+	Page   int64  `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size   int64  `query:"size" validate:"required,min=1,max=1000"`
+	Cursor string `query:"cursor" validate:"omitempty,max=512"`
+	Count  bool   `query:"count"`
 	Search string `query:"search"`
+	// Languages are the languages of the caller, most preferred first. They pick the localized names
+	// of the response and are left out of the cache key.
+	Languages []string `query:"-" json:"-"`
 }
 
 type City struct {
-	ProvinceId string `query:"provinceId" validate:"required"`
-	Page       int64  `query:"page" validate:"required"`
-	Size       int64  `query:"size" validate:"required"`
-	Search     string `query:"search"`
+	ProvinceId string   `query:"provinceId" validate:"required"`
+	Page       int64    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size       int64    `query:"size" validate:"required,min=1,max=1000"`
+	Cursor     string   `query:"cursor" validate:"omitempty,max=512"`
+	Count      bool     `query:"count"`
+	Search     string   `query:"search"`
+	Languages  []string `query:"-" json:"-"`
 }
 
 type District struct {
-	ProvinceId string `query:"provinceId" validate:"required"`
-	CityId     string `query:"cityId" validate:"required"`
-	Page       int64  `query:"page" validate:"required"`
-	Size       int64  `query:"size" validate:"required"`
-	Search     string `query:"search"`
+	ProvinceId string   `query:"provinceId" validate:"required"`
+	CityId     string   `query:"cityId" validate:"required"`
+	Page       int64    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size       int64    `query:"size" validate:"required,min=1,max=1000"`
+	Cursor     string   `query:"cursor" validate:"omitempty,max=512"`
+	Count      bool     `query:"count"`
+	Search     string   `query:"search"`
+	Languages  []string `query:"-" json:"-"`
 }
 
 type SubDistrict struct {
-	ProvinceId string `query:"provinceId" validate:"required"`
-	CityId     string `query:"cityId" validate:"required"`
-	DistrictId string `query:"districtId" validate:"required"`
-	Page       int64  `query:"page" validate:"required"`
-	Size       int64  `query:"size" validate:"required"`
-	Search     string `query:"search"`
+	ProvinceId string   `query:"provinceId" validate:"required"`
+	CityId     string   `query:"cityId" validate:"required"`
+	DistrictId string   `query:"districtId" validate:"required"`
+	Page       int64    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size       int64    `query:"size" validate:"required,min=1,max=1000"`
+	Cursor     string   `query:"cursor" validate:"omitempty,max=512"`
+	Count      bool     `query:"count"`
+	Search     string   `query:"search"`
+	Languages  []string `query:"-" json:"-"`
 }
 
+// Country lists the active countries by name, or by DisplayOrder when Sort is displayOrder
 type Country struct {
-	Page   int64  `query:"page" validate:"required"`
-	Size   int64  `query:"size" validate:"required"`
-	Search string `query:"search"`
+	Page          int64    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size          int64    `query:"size" validate:"required,min=1,max=1000"`
+	Cursor        string   `query:"cursor" validate:"omitempty,max=512"`
+	Count         bool     `query:"count"`
+	Search        string   `query:"search"`
+	ContinentCode string   `query:"continentCode" validate:"omitempty,alpha,max=10"`
+	Sort          string   `query:"sort" validate:"omitempty,oneof=name displayOrder"`
+	Languages     []string `query:"-" json:"-"`
+}
+
+// CountryCode is an ISO 3166-1 alpha-2, alpha-3 or numeric code
+type CountryCode struct {
+	Code      string `validate:"required,alphanum,min=2,max=3"`
+	Languages []string
+}
+
+type Continent struct {
+	Languages []string
+}
+
+// Search is free text matched against the subdistrict, district, city and province names in every language
+type Search struct {
+	Query     string   `query:"q" validate:"required,min=3,max=100"`
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=50"`
+	Languages []string `query:"-"`
+}
+
+// Hierarchy is an address to validate, the parent ids and postal code are checked against the subdistrict.
+// CountryId is the numeric id or the ISO 3166-1 alpha-2 or alpha-3 code of the country.
+type Hierarchy struct {
+	CountryId     string `json:"countryId"`
+	ProvinceId    string `json:"provinceId"`
+	CityId        string `json:"cityId"`
+	DistrictId    string `json:"districtId"`
+	SubdistrictId string `json:"subdistrictId"`
+	PostalCode    string `json:"postalCode"`
+	Latitude      string `json:"latitude"`
+	Longitude     string `json:"longitude"`
+}
+
+// Region is a province, city, district or subdistrict maintained by an admin, ParentId is empty for provinces
+// and PostalCode and the centroid coordinates are only set on subdistricts. Names omitted on an update are kept.
+type Region struct {
+	Level      string            `json:"-" validate:"required,oneof=province city district subdistrict"`
+	Id         string            `json:"id" validate:"required,max=20"`
+	Name       string            `json:"name" validate:"required,max=100"`
+	Names      map[string]string `json:"names" validate:"omitempty,max=20,dive,keys,bcp47_language_tag,endkeys,required,max=100"`
+	ParentId   string            `json:"parentId" validate:"max=20"`
+	PostalCode string            `json:"postalCode" validate:"omitempty,numeric,len=5"`
+	Latitude   *float64          `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude  *float64          `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+}
+
+type PostalCode struct {
+	Code      string `validate:"required,numeric,len=5"`
+	Languages []string
+}
+
+type RegionId struct {
+	Level string `validate:"required,oneof=province city district subdistrict"`
+	Id    string `validate:"required"`
+}
+
+type SaveCountry struct {
+	Id            int               `json:"id" validate:"required,min=1"`
+	Code          string            `json:"code" validate:"required,len=2"`
+	Name          string            `json:"name" validate:"required,max=100"`
+	Names         map[string]string `json:"names" validate:"omitempty,max=20,dive,keys,bcp47_language_tag,endkeys,required,max=100"`
+	Iso3          string            `json:"iso3" validate:"omitempty,len=3"`
+	Number        int               `json:"number"`
+	ContinentCode string            `json:"continentCode" validate:"required"`
+	DisplayOrder  int               `json:"displayOrder"`
+	FullName      string            `json:"fullName" validate:"max=255"`
+	Bounds        *Bounds           `json:"bounds"`
+}
+
+// Bounds is a country bounding box, MinLongitude above MaxLongitude crosses the antimeridian
+type Bounds struct {
+	MinLatitude  float64 `json:"minLatitude" validate:"latitude"`
+	MinLongitude float64 `json:"minLongitude" validate:"longitude"`
+	MaxLatitude  float64 `json:"maxLatitude" validate:"latitude,gtefield=MinLatitude"`
+	MaxLongitude float64 `json:"maxLongitude" validate:"longitude"`
+}
+
+// ReverseGeocode is a GPS position to resolve to the nearest subdistrict
+type ReverseGeocode struct {
+	Latitude  *float64 `query:"lat" validate:"required,latitude"`
+	Longitude *float64 `query:"lng" validate:"required,longitude"`
+	Languages []string `query:"-"`
+}
+
+// ImportRegions is a csv or json file of one level, a dry run validates every row without saving
+type ImportRegions struct {
+	Level   string `query:"-" validate:"required,oneof=province city district subdistrict country"`
+	Format  string `query:"format" validate:"required,oneof=csv json"`
+	DryRun  bool   `query:"dryRun"`
+	Content []byte `query:"-"`
+}
+
+// Dataset asks for the offline address bundle, Since is the dataset version a client already holds and
+// turns the bundle into the changes made after it
+type Dataset struct {
+	Since  string `query:"since" validate:"omitempty,numeric,max=20"`
+	Format string `query:"format" validate:"omitempty,oneof=json gzip"`
 }
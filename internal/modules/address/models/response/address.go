@@ -49,6 +49,7 @@ type SubDistrict struct {
 	CityName     string `json:"cityName"`
 	ProvinceId   string `json:"provinceId"`
 	ProvinceName string `json:"provinceName"`
+	PostalCode   string `json:"postalCode"`
 }
 
 type SubDistrictResp struct {
@@ -59,6 +60,8 @@ type SubDistrictResp struct {
 type Country struct {
 	Id            int    `json:"id"`
 	Code          string `json:"code"`
+	Iso3          string `json:"iso3"`
+	Number        int    `json:"number"`
 	Name          string `json:"name"`
 	ContinentCode string `json:"continentCode"`
 	ContinentName string `json:"continentName"`
@@ -79,3 +82,87 @@ type ContinentResp struct {
 	CollectionData []Continent
 	MetaData       constants.MetaData
 }
+
+// Hierarchy is a validated address, SubDistrict is nil for countries without administrative levels
+// and Location is nil when no coordinates were given
+type Hierarchy struct {
+	Country     Country      `json:"country"`
+	SubDistrict *SubDistrict `json:"subDistrict"`
+	Location    *Location    `json:"location"`
+}
+
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ReverseGeocode is the subdistrict whose centroid is nearest to a position, Distance is in meters
+type ReverseGeocode struct {
+	SubDistrict
+	Distance float64 `json:"distance"`
+}
+
+// Region is a province, city, district or subdistrict, the parent fields above its level are empty.
+// The localized parent names are kept to be copied onto the region and are not returned.
+type Region struct {
+	Level         string            `json:"level"`
+	Id            string            `json:"id"`
+	Name          string            `json:"name"`
+	Names         map[string]string `json:"names,omitempty"`
+	DistrictId    string            `json:"districtId,omitempty"`
+	DistrictName  string            `json:"districtName,omitempty"`
+	DistrictNames map[string]string `json:"-"`
+	CityId        string            `json:"cityId,omitempty"`
+	CityName      string            `json:"cityName,omitempty"`
+	CityNames     map[string]string `json:"-"`
+	ProvinceId    string            `json:"provinceId,omitempty"`
+	ProvinceName  string            `json:"provinceName,omitempty"`
+	ProvinceNames map[string]string `json:"-"`
+	PostalCode    string            `json:"postalCode,omitempty"`
+	Centroid      *Location         `json:"centroid,omitempty"`
+	Deactivated   bool              `json:"deactivated"`
+}
+
+type ImportReport struct {
+	Level   string `json:"level"`
+	DryRun  bool   `json:"dryRun"`
+	Total   int    `json:"total"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+}
+
+// SearchResult is a subdistrict matching a search with its full hierarchy, a higher score ranks first
+type SearchResult struct {
+	SubDistrict
+	Score float64 `json:"score"`
+}
+
+// Dataset is the province to subdistrict hierarchy for offline address entry, every region only links its
+// parent. A full bundle replaces what the client holds. A delta (Full false) holds the regions saved after
+// Since, to upsert, and the ids of the regions deactivated meanwhile. Hash identifies the full dataset at Version.
+type Dataset struct {
+	Version      string          `json:"version"`
+	Hash         string          `json:"hash"`
+	Since        string          `json:"since,omitempty"`
+	Full         bool            `json:"full"`
+	Provinces    []DatasetRegion `json:"provinces"`
+	Cities       []DatasetRegion `json:"cities"`
+	Districts    []DatasetRegion `json:"districts"`
+	Subdistricts []DatasetRegion `json:"subdistricts"`
+	Removed      *DatasetRemoved `json:"removed,omitempty"`
+}
+
+type DatasetRegion struct {
+	Id         string            `json:"id"`
+	ParentId   string            `json:"parentId,omitempty"`
+	Name       string            `json:"name"`
+	Names      map[string]string `json:"names,omitempty"`
+	PostalCode string            `json:"postalCode,omitempty"`
+}
+
+type DatasetRemoved struct {
+	Provinces    []string `json:"provinces"`
+	Cities       []string `json:"cities"`
+	Districts    []string `json:"districts"`
+	Subdistricts []string `json:"subdistricts"`
+}
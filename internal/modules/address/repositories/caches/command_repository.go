@@ -0,0 +1,96 @@
+package caches
+
+import (
+	"context"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	wrapper "user-service/internal/pkg/helpers"
+)
+
+type commandCacheRepository struct {
+	next  address.MongodbRepositoryCommand
+	store *Store
+}
+
+// NewCommandCacheRepository invalidates the address cache after every successful write of next
+func NewCommandCacheRepository(next address.MongodbRepositoryCommand, store *Store) address.MongodbRepositoryCommand {
+	return &commandCacheRepository{
+		next:  next,
+		store: store,
+	}
+}
+
+func (c commandCacheRepository) invalidateAfter(ctx context.Context, write <-chan wrapper.Result) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-write
+		if resp.Error == nil {
+			c.store.Invalidate(ctx)
+		}
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandCacheRepository) UpsertOneProvince(ctx context.Context, province entity.Province) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertOneProvince(ctx, province))
+}
+
+func (c commandCacheRepository) UpsertOneCity(ctx context.Context, city entity.City) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertOneCity(ctx, city))
+}
+
+func (c commandCacheRepository) UpsertOneDistrict(ctx context.Context, district entity.District) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertOneDistrict(ctx, district))
+}
+
+func (c commandCacheRepository) UpsertOneSubdistrict(ctx context.Context, subdistrict entity.SubDistrict) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertOneSubdistrict(ctx, subdistrict))
+}
+
+func (c commandCacheRepository) UpsertOneCountry(ctx context.Context, country entity.Country) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertOneCountry(ctx, country))
+}
+
+func (c commandCacheRepository) UpdateProvinceReferences(ctx context.Context, province entity.Province) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpdateProvinceReferences(ctx, province))
+}
+
+func (c commandCacheRepository) UpdateCityReferences(ctx context.Context, city entity.City) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpdateCityReferences(ctx, city))
+}
+
+func (c commandCacheRepository) UpdateDistrictReferences(ctx context.Context, district entity.District) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpdateDistrictReferences(ctx, district))
+}
+
+func (c commandCacheRepository) UpsertManyProvinces(ctx context.Context, provinces []entity.Province) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertManyProvinces(ctx, provinces))
+}
+
+func (c commandCacheRepository) UpsertManyCities(ctx context.Context, cities []entity.City) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertManyCities(ctx, cities))
+}
+
+func (c commandCacheRepository) UpsertManyDistricts(ctx context.Context, districts []entity.District) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertManyDistricts(ctx, districts))
+}
+
+func (c commandCacheRepository) UpsertManySubdistricts(ctx context.Context, subdistricts []entity.SubDistrict) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertManySubdistricts(ctx, subdistricts))
+}
+
+func (c commandCacheRepository) UpsertManyCountries(ctx context.Context, countries []entity.Country) <-chan wrapper.Result {
+	return c.invalidateAfter(ctx, c.next.UpsertManyCountries(ctx, countries))
+}
+
+// Batch gives run the repository below the cache so its writes invalidate once, after run. The cache
+// is invalidated even when run fails, the writes made before the failure are saved.
+func (c commandCacheRepository) Batch(ctx context.Context, run func(command address.MongodbRepositoryCommand) error) error {
+	err := c.next.Batch(ctx, run)
+	c.store.Invalidate(ctx)
+	return err
+}
@@ -0,0 +1,117 @@
+package caches_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/repositories/caches"
+	"user-service/internal/pkg/constants"
+	"user-service/internal/pkg/helpers"
+	mockaddress "user-service/mocks/modules/address"
+	mocklog "user-service/mocks/pkg/log"
+	mockredis "user-service/mocks/pkg/redis"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommandCacheTestSuite struct {
+	suite.Suite
+	mockRedis   *mockredis.Collections
+	mockQuery   *mockaddress.MongodbRepositoryQuery
+	mockCommand *mockaddress.MongodbRepositoryCommand
+	mockLogger  *mocklog.Logger
+	query       address.MongodbRepositoryQuery
+	command     address.MongodbRepositoryCommand
+	ctx         context.Context
+}
+
+func (suite *CommandCacheTestSuite) SetupTest() {
+	suite.mockRedis = &mockredis.Collections{}
+	suite.mockQuery = &mockaddress.MongodbRepositoryQuery{}
+	suite.mockCommand = &mockaddress.MongodbRepositoryCommand{}
+	suite.mockLogger = &mocklog.Logger{}
+	store := caches.NewStore(suite.mockRedis, suite.mockLogger)
+	suite.query = caches.NewQueryCacheRepository(suite.mockQuery, store)
+	suite.command = caches.NewCommandCacheRepository(suite.mockCommand, store)
+	suite.ctx = context.Background()
+}
+
+func TestCommandCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandCacheTestSuite))
+}
+
+func (suite *CommandCacheTestSuite) mockUpsertOneProvince(result helpers.Result) {
+	expected := make(chan helpers.Result)
+	suite.mockCommand.On("UpsertOneProvince", suite.ctx, mock.Anything).Return((<-chan helpers.Result)(expected))
+	go func() {
+		expected <- result
+		close(expected)
+	}()
+}
+
+func (suite *CommandCacheTestSuite) TestUpsertInvalidatesCache() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockRedis.On("Get", suite.ctx, constants.RedisKeyAddressCacheVersion).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Get", suite.ctx, mock.Anything).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Set", suite.ctx, mock.Anything, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil))
+	for i := 0; i < 2; i++ {
+		expected := make(chan helpers.Result, 1)
+		expected <- helpers.Result{Data: &provinces, Count: 1}
+		close(expected)
+		suite.mockQuery.On("FindProvinces", suite.ctx, mock.Anything).Return((<-chan helpers.Result)(expected)).Once()
+	}
+	suite.mockUpsertOneProvince(helpers.Result{})
+
+	<-suite.query.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+	result := <-suite.command.UpsertOneProvince(suite.ctx, entity.Province{Id: "31", Name: "Jakarta"})
+	assert.Nil(suite.T(), result.Error)
+	<-suite.query.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	suite.mockQuery.AssertNumberOfCalls(suite.T(), "FindProvinces", 2)
+	suite.mockRedis.AssertCalled(suite.T(), "Set", suite.ctx, constants.RedisKeyAddressCacheVersion, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandCacheTestSuite) TestUpsertErrorKeepsCache() {
+	suite.mockUpsertOneProvince(helpers.Result{Error: errors.New("error")})
+
+	result := <-suite.command.UpsertOneProvince(suite.ctx, entity.Province{Id: "31", Name: "Jakarta"})
+
+	assert.NotNil(suite.T(), result.Error)
+	suite.mockRedis.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandCacheTestSuite) TestBatchInvalidatesOnce() {
+	suite.mockRedis.On("Set", suite.ctx, constants.RedisKeyAddressCacheVersion, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil))
+	suite.mockCommand.On("Batch", suite.ctx, mock.Anything).Return(func(ctx context.Context, run func(command address.MongodbRepositoryCommand) error) error {
+		return run(suite.mockCommand)
+	})
+	suite.mockUpsertOneProvince(helpers.Result{})
+
+	err := suite.command.Batch(suite.ctx, func(command address.MongodbRepositoryCommand) error {
+		<-command.UpsertOneProvince(suite.ctx, entity.Province{Id: "31", Name: "Jakarta"})
+		<-command.UpsertOneProvince(suite.ctx, entity.Province{Id: "32", Name: "Jawa Barat"})
+		return nil
+	})
+
+	assert.Nil(suite.T(), err)
+	suite.mockCommand.AssertNumberOfCalls(suite.T(), "UpsertOneProvince", 2)
+	suite.mockRedis.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
+
+func (suite *CommandCacheTestSuite) TestBatchErrorInvalidates() {
+	suite.mockRedis.On("Set", suite.ctx, constants.RedisKeyAddressCacheVersion, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil))
+	suite.mockCommand.On("Batch", suite.ctx, mock.Anything).Return(errors.New("error"))
+
+	err := suite.command.Batch(suite.ctx, func(command address.MongodbRepositoryCommand) error {
+		return nil
+	})
+
+	assert.NotNil(suite.T(), err)
+	suite.mockRedis.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
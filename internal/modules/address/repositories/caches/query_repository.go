@@ -0,0 +1,124 @@
+package caches
+
+import (
+	"context"
+	"strings"
+	"time"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	wrapper "user-service/internal/pkg/helpers"
+)
+
+// TTLs per entity type, lower levels are maintained more often. They also bound how long a result read
+// from a replica lagging past the settle window is served.
+const (
+	ttlProvince    = 12 * time.Hour
+	ttlCity        = 12 * time.Hour
+	ttlDistrict    = 6 * time.Hour
+	ttlSubdistrict = 3 * time.Hour
+	ttlCountry     = 12 * time.Hour
+	ttlContinent   = 24 * time.Hour
+)
+
+type queryCacheRepository struct {
+	next  address.MongodbRepositoryQuery
+	store *Store
+}
+
+// NewQueryCacheRepository serves the reference lists and lookups of next from the cache, the admin lookups,
+// counts, geo queries and the full region scans go straight to next
+func NewQueryCacheRepository(next address.MongodbRepositoryQuery, store *Store) address.MongodbRepositoryQuery {
+	return &queryCacheRepository{
+		next:  next,
+		store: store,
+	}
+}
+
+func (q queryCacheRepository) FindProvinces(ctx context.Context, payload request.Province) <-chan wrapper.Result {
+	return readThrough[[]entity.Province](ctx, q.store, "provinces", ttlProvince, payload, func() <-chan wrapper.Result {
+		return q.next.FindProvinces(ctx, payload)
+	})
+}
+
+func (q queryCacheRepository) FindCitiesByParam(ctx context.Context, payload request.City) <-chan wrapper.Result {
+	return readThrough[[]entity.City](ctx, q.store, "cities", ttlCity, payload, func() <-chan wrapper.Result {
+		return q.next.FindCitiesByParam(ctx, payload)
+	})
+}
+
+func (q queryCacheRepository) FindDistrictByParam(ctx context.Context, payload request.District) <-chan wrapper.Result {
+	return readThrough[[]entity.District](ctx, q.store, "districts", ttlDistrict, payload, func() <-chan wrapper.Result {
+		return q.next.FindDistrictByParam(ctx, payload)
+	})
+}
+
+func (q queryCacheRepository) FindSubDistrictByParam(ctx context.Context, payload request.SubDistrict) <-chan wrapper.Result {
+	return readThrough[[]entity.SubDistrict](ctx, q.store, "subdistricts", ttlSubdistrict, payload, func() <-chan wrapper.Result {
+		return q.next.FindSubDistrictByParam(ctx, payload)
+	})
+}
+
+func (q queryCacheRepository) FindOneProvince(ctx context.Context, id string) <-chan wrapper.Result {
+	return q.next.FindOneProvince(ctx, id)
+}
+
+func (q queryCacheRepository) FindOneCity(ctx context.Context, id string) <-chan wrapper.Result {
+	return q.next.FindOneCity(ctx, id)
+}
+
+func (q queryCacheRepository) FindOneDistrict(ctx context.Context, id string) <-chan wrapper.Result {
+	return q.next.FindOneDistrict(ctx, id)
+}
+
+func (q queryCacheRepository) FindOneSubdistrict(ctx context.Context, id string) <-chan wrapper.Result {
+	return readThrough[entity.SubDistrict](ctx, q.store, "subdistrict", ttlSubdistrict, id, func() <-chan wrapper.Result {
+		return q.next.FindOneSubdistrict(ctx, id)
+	})
+}
+
+func (q queryCacheRepository) FindAllSubdistricts(ctx context.Context, cursor string, size int64) <-chan wrapper.Result {
+	return q.next.FindAllSubdistricts(ctx, cursor, size)
+}
+
+func (q queryCacheRepository) FindAllRegions(ctx context.Context, level string, cursor string, size int64) <-chan wrapper.Result {
+	return q.next.FindAllRegions(ctx, level, cursor, size)
+}
+
+func (q queryCacheRepository) FindSubdistrictsByPostalCode(ctx context.Context, postalCode string) <-chan wrapper.Result {
+	return readThrough[[]entity.SubDistrict](ctx, q.store, "postalcode", ttlSubdistrict, postalCode, func() <-chan wrapper.Result {
+		return q.next.FindSubdistrictsByPostalCode(ctx, postalCode)
+	})
+}
+
+func (q queryCacheRepository) FindNearestSubdistrict(ctx context.Context, latitude float64, longitude float64, maxDistance float64) <-chan wrapper.Result {
+	return q.next.FindNearestSubdistrict(ctx, latitude, longitude, maxDistance)
+}
+
+func (q queryCacheRepository) FindCountries(ctx context.Context, payload request.Country) <-chan wrapper.Result {
+	return readThrough[[]entity.Country](ctx, q.store, "countries", ttlCountry, payload, func() <-chan wrapper.Result {
+		return q.next.FindCountries(ctx, payload)
+	})
+}
+
+func (q queryCacheRepository) FindOneCountry(ctx context.Context, id int) <-chan wrapper.Result {
+	return readThrough[entity.Country](ctx, q.store, "country", ttlCountry, id, func() <-chan wrapper.Result {
+		return q.next.FindOneCountry(ctx, id)
+	})
+}
+
+func (q queryCacheRepository) FindOneCountryByCode(ctx context.Context, code string) <-chan wrapper.Result {
+	return readThrough[entity.Country](ctx, q.store, "countryByCode", ttlCountry, strings.ToUpper(code), func() <-chan wrapper.Result {
+		return q.next.FindOneCountryByCode(ctx, code)
+	})
+}
+
+func (q queryCacheRepository) FindContinent(ctx context.Context) <-chan wrapper.Result {
+	return readThrough[[]entity.Continent](ctx, q.store, "continents", ttlContinent, nil, func() <-chan wrapper.Result {
+		return q.next.FindContinent(ctx)
+	})
+}
+
+func (q queryCacheRepository) CountActiveChildren(ctx context.Context, level string, id string) <-chan wrapper.Result {
+	return q.next.CountActiveChildren(ctx, level, id)
+}
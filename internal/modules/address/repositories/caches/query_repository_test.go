@@ -0,0 +1,199 @@
+package caches_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/repositories/caches"
+	"user-service/internal/pkg/constants"
+	"user-service/internal/pkg/helpers"
+	mockaddress "user-service/mocks/modules/address"
+	mocklog "user-service/mocks/pkg/log"
+	mockredis "user-service/mocks/pkg/redis"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryCacheTestSuite struct {
+	suite.Suite
+	mockRedis   *mockredis.Collections
+	mockQuery   *mockaddress.MongodbRepositoryQuery
+	mockLogger  *mocklog.Logger
+	repository  address.MongodbRepositoryQuery
+	ctx         context.Context
+	cacheKeyArg interface{}
+}
+
+func (suite *QueryCacheTestSuite) SetupTest() {
+	suite.mockRedis = &mockredis.Collections{}
+	suite.mockQuery = &mockaddress.MongodbRepositoryQuery{}
+	suite.mockLogger = &mocklog.Logger{}
+	suite.repository = caches.NewQueryCacheRepository(suite.mockQuery, caches.NewStore(suite.mockRedis, suite.mockLogger))
+	suite.ctx = context.Background()
+	suite.cacheKeyArg = mock.MatchedBy(func(key string) bool {
+		return key != constants.RedisKeyAddressCacheVersion
+	})
+	suite.mockRedis.On("Get", suite.ctx, constants.RedisKeyAddressCacheVersion).Return(redis.NewStringResult("", redis.Nil))
+}
+
+func TestQueryCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryCacheTestSuite))
+}
+
+func (suite *QueryCacheTestSuite) mockFindProvinces(result helpers.Result) {
+	expected := make(chan helpers.Result)
+	suite.mockQuery.On("FindProvinces", suite.ctx, mock.Anything).Return((<-chan helpers.Result)(expected)).Once()
+	go func() {
+		expected <- result
+		close(expected)
+	}()
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesFromRedis() {
+	cached, _ := json.Marshal(map[string]interface{}{
+		"data":  []entity.Province{{Id: "31", Name: "DKI Jakarta"}},
+		"count": 1,
+	})
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult(string(cached), nil))
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	assert.Nil(suite.T(), result.Error)
+	assert.Equal(suite.T(), &[]entity.Province{{Id: "31", Name: "DKI Jakarta"}}, result.Data)
+	assert.Equal(suite.T(), int64(1), result.Count)
+	suite.mockQuery.AssertNotCalled(suite.T(), "FindProvinces", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesMissLoadsAndStores() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil)).Once()
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+	assert.Equal(suite.T(), &provinces, result.Data)
+
+	// The second call is served by the in-memory tier without touching Redis or Mongo
+	result = <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+	assert.Equal(suite.T(), &provinces, result.Data)
+	assert.Equal(suite.T(), int64(1), result.Count)
+	suite.mockQuery.AssertNumberOfCalls(suite.T(), "FindProvinces", 1)
+	suite.mockRedis.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
+
+// mockCacheVersion replaces the version of SetupTest, an invalidation stores its time as the version
+func (suite *QueryCacheTestSuite) mockCacheVersion(invalidatedAt time.Time) {
+	suite.mockRedis.ExpectedCalls = nil
+	version := strconv.FormatInt(invalidatedAt.UnixNano(), 10)
+	suite.mockRedis.On("Get", suite.ctx, constants.RedisKeyAddressCacheVersion).Return(redis.NewStringResult(version, nil))
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesAfterInvalidationCachedUntilSettled() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockCacheVersion(time.Now().Add(-10 * time.Second))
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	// The replica may still miss the write, the result is kept for the rest of the settle window only
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, mock.MatchedBy(func(ttl time.Duration) bool {
+		return ttl > 0 && ttl <= 20*time.Second
+	})).Return(redis.NewStatusResult("OK", nil)).Once()
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	assert.Equal(suite.T(), &provinces, result.Data)
+	suite.mockRedis.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesAfterSettleWindowFullTTL() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockCacheVersion(time.Now().Add(-time.Minute))
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, 12*time.Hour).Return(redis.NewStatusResult("OK", nil)).Once()
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	assert.Equal(suite.T(), &provinces, result.Data)
+	suite.mockRedis.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
+
+func (suite *QueryCacheTestSuite) TestSettling() {
+	store := caches.NewStore(suite.mockRedis, suite.mockLogger)
+
+	assert.True(suite.T(), store.Settling(strconv.FormatInt(time.Now().Add(-10*time.Second).UnixNano(), 10)))
+	assert.False(suite.T(), store.Settling(strconv.FormatInt(time.Now().Add(-time.Minute).UnixNano(), 10)))
+	assert.False(suite.T(), store.Settling("0"))
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesKeyDependsOnRequest() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil))
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+
+	<-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+	<-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 2, Size: 10})
+
+	suite.mockQuery.AssertNumberOfCalls(suite.T(), "FindProvinces", 2)
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesCursorPageKeepsNextCursor() {
+	provinces := []entity.Province{{Id: "11", Name: "Aceh"}}
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, mock.Anything).Return(redis.NewStatusResult("OK", nil)).Once()
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, MetaData: "after-aceh"})
+
+	<-suite.repository.FindProvinces(suite.ctx, request.Province{Size: 1})
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Size: 1})
+
+	assert.Equal(suite.T(), &provinces, result.Data)
+	assert.Equal(suite.T(), "after-aceh", result.MetaData)
+	suite.mockQuery.AssertNumberOfCalls(suite.T(), "FindProvinces", 1)
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesErrorNotCached() {
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", redis.Nil))
+	suite.mockFindProvinces(helpers.Result{Error: errors.New("error")})
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	assert.NotNil(suite.T(), result.Error)
+	suite.mockRedis.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *QueryCacheTestSuite) TestFindProvincesRedisDown() {
+	provinces := []entity.Province{{Id: "31", Name: "DKI Jakarta"}}
+	suite.mockRedis.On("Get", suite.ctx, suite.cacheKeyArg).Return(redis.NewStringResult("", errors.New("connection refused")))
+	suite.mockRedis.On("Set", suite.ctx, suite.cacheKeyArg, mock.Anything, mock.Anything).Return(redis.NewStatusResult("", errors.New("connection refused")))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockFindProvinces(helpers.Result{Data: &provinces, Count: 1})
+
+	result := <-suite.repository.FindProvinces(suite.ctx, request.Province{Page: 1, Size: 10})
+
+	assert.Nil(suite.T(), result.Error)
+	assert.Equal(suite.T(), &provinces, result.Data)
+}
+
+func (suite *QueryCacheTestSuite) TestFindOneProvinceNotCached() {
+	expected := make(chan helpers.Result)
+	suite.mockQuery.On("FindOneProvince", suite.ctx, "31").Return((<-chan helpers.Result)(expected))
+	go func() {
+		expected <- helpers.Result{Data: &entity.Province{Id: "31"}}
+		close(expected)
+	}()
+
+	result := <-suite.repository.FindOneProvince(suite.ctx, "31")
+
+	assert.Equal(suite.T(), &entity.Province{Id: "31"}, result.Data)
+	suite.mockRedis.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,255 @@
+package caches
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"user-service/internal/pkg/constants"
+	wrapper "user-service/internal/pkg/helpers"
+	"user-service/internal/pkg/log"
+	"user-service/internal/pkg/redis"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// lruCapacity bounds the in-process tier, the Redis tier holds everything else
+const lruCapacity = 2048
+
+// versionRefresh is how long an instance trusts its copy of the cache version, so an invalidation made by
+// another instance is seen within this delay
+const versionRefresh = 5 * time.Second
+
+// settleWindow is how long after an invalidation the replicas the queries read from may still miss the
+// write. Results loaded meanwhile are only cached until the window ends, so a stale read cannot be
+// served for the whole TTL of its entity.
+const settleWindow = 30 * time.Second
+
+// Store is the two tier cache shared by the query and command decorators. Every key embeds the cache
+// version, invalidating bumps the version instead of deleting keys and old entries expire with their TTL.
+type Store struct {
+	redis  redis.Collections
+	logger log.Logger
+	lru    *lru
+
+	mu        sync.Mutex
+	version   string
+	versionAt time.Time
+}
+
+func NewStore(redis redis.Collections, log log.Logger) *Store {
+	return &Store{
+		redis:  redis,
+		logger: log,
+		lru:    newLru(lruCapacity),
+	}
+}
+
+// Invalidate drops every cached entry of this instance and makes the other instances miss on their next refresh
+func (s *Store) Invalidate(ctx context.Context) {
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := s.redis.Set(ctx, constants.RedisKeyAddressCacheVersion, version, 0).Err(); err != nil {
+		s.logger.Error(ctx, "Error invalidate address cache", err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+	s.versionAt = time.Now()
+	s.lru.purge()
+}
+
+// Version is the current revision of the address master data, it changes on every invalidation
+func (s *Store) Version(ctx context.Context) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.version != "" && time.Since(s.versionAt) < versionRefresh {
+		return s.version
+	}
+
+	version, err := s.redis.Get(ctx, constants.RedisKeyAddressCacheVersion).Result()
+	switch {
+	case err == goredis.Nil:
+		version = "0"
+	case err != nil:
+		s.logger.Error(ctx, "Error get address cache version", err.Error())
+		if s.version != "" {
+			return s.version
+		}
+		version = "0"
+	}
+	if version != s.version {
+		s.lru.purge()
+	}
+	s.version = version
+	s.versionAt = time.Now()
+	return version
+}
+
+// key derives the cache key from the method name and its arguments, request structs are hashed as json
+func (s *Store) key(version string, name string, args interface{}) string {
+	encoded, _ := json.Marshal(args)
+	sum := sha1.Sum(encoded)
+	return fmt.Sprintf("%s:%s:%s:%s", constants.RedisKeyAddressCache, version, name, hex.EncodeToString(sum[:]))
+}
+
+// entryTTL is ttl, shortened to the rest of the settle window while the version is younger than it. The
+// version is the time of the invalidation in nanoseconds, so every instance derives the same window.
+func entryTTL(version string, ttl time.Duration, now time.Time) time.Duration {
+	left := settleLeft(version, now)
+	if left <= 0 || left >= ttl {
+		return ttl
+	}
+	return left
+}
+
+// Settling tells whether reads of version may still miss the write that created it
+func (s *Store) Settling(version string) bool {
+	return settleLeft(version, time.Now()) > 0
+}
+
+// settleLeft is the rest of the settle window of version, zero or less once it has passed
+func settleLeft(version string, now time.Time) time.Duration {
+	invalidatedAt, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || invalidatedAt == 0 {
+		return 0
+	}
+	return settleWindow - now.Sub(time.Unix(0, invalidatedAt))
+}
+
+func (s *Store) lookup(ctx context.Context, key string, ttl time.Duration) ([]byte, bool) {
+	if value, ok := s.lru.get(key); ok {
+		return value, true
+	}
+	value, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != goredis.Nil {
+			s.logger.Error(ctx, "Error get address cache", err.Error())
+		}
+		return nil, false
+	}
+	s.lru.set(key, value, time.Now().Add(ttl))
+	return value, true
+}
+
+func (s *Store) store(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	s.lru.set(key, value, time.Now().Add(ttl))
+	if err := s.redis.Set(ctx, key, value, ttl).Err(); err != nil {
+		s.logger.Error(ctx, "Error set address cache", err.Error())
+	}
+}
+
+// cacheEntry is what both tiers hold for one query result, Cursor is the next cursor of a keyset page
+type cacheEntry[T any] struct {
+	Data   T      `json:"data"`
+	Count  int64  `json:"count"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// readThrough serves the query from the cache or loads it and caches a successful result. Data of a
+// hit is a fresh *T decoded for each caller, empty results and errors are never cached.
+func readThrough[T any](ctx context.Context, s *Store, name string, ttl time.Duration, args interface{}, load func() <-chan wrapper.Result) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		version := s.Version(ctx)
+		key := s.key(version, name, args)
+		ttl = entryTTL(version, ttl, time.Now())
+		if value, ok := s.lookup(ctx, key, ttl); ok {
+			var entry cacheEntry[T]
+			if err := json.Unmarshal(value, &entry); err == nil {
+				result := wrapper.Result{
+					Data:  &entry.Data,
+					Count: entry.Count,
+				}
+				if entry.Cursor != "" {
+					result.MetaData = entry.Cursor
+				}
+				output <- result
+				return
+			}
+		}
+
+		resp := <-load()
+		if resp.Error == nil && resp.Data != nil {
+			if data, ok := resp.Data.(*T); ok {
+				cursor, _ := resp.MetaData.(string)
+				value, err := json.Marshal(cacheEntry[T]{Data: *data, Count: resp.Count, Cursor: cursor})
+				if err == nil {
+					s.store(ctx, key, value, ttl)
+				}
+			}
+		}
+		output <- resp
+	}()
+
+	return output
+}
+
+type lruItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// lru is a size bounded map evicting the least recently used key, expired keys are dropped on read
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+func newLru(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (l *lru) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := element.Value.(*lruItem)
+	if time.Now().After(item.expiresAt) {
+		l.order.Remove(element)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return item.value, true
+}
+
+func (l *lru) set(key string, value []byte, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.items[key]; ok {
+		element.Value = &lruItem{key: key, value: value, expiresAt: expiresAt}
+		l.order.MoveToFront(element)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruItem{key: key, value: value, expiresAt: expiresAt})
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (l *lru) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = map[string]*list.Element{}
+	l.order.Init()
+}
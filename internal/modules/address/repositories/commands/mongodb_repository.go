@@ -0,0 +1,326 @@
+package commands
+
+import (
+	"context"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/pkg/databases/mongodb"
+	wrapper "user-service/internal/pkg/helpers"
+	"user-service/internal/pkg/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type commandMongodbRepository struct {
+	mongoDb mongodb.Collections
+	logger  log.Logger
+}
+
+func NewCommandMongodbRepository(mongodb mongodb.Collections, log log.Logger) address.MongodbRepositoryCommand {
+	return &commandMongodbRepository{
+		mongoDb: mongodb,
+		logger:  log,
+	}
+}
+
+func (c commandMongodbRepository) UpsertOneProvince(ctx context.Context, province entity.Province) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "province",
+			Document:       province,
+			Filter: bson.M{
+				"id": province.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneCity(ctx context.Context, city entity.City) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "city",
+			Document:       city,
+			Filter: bson.M{
+				"id": city.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneDistrict(ctx context.Context, district entity.District) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "district",
+			Document:       district,
+			Filter: bson.M{
+				"id": district.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneSubdistrict(ctx context.Context, subdistrict entity.SubDistrict) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "subdistrict",
+			Document:       subdistrict,
+			Filter: bson.M{
+				"id": subdistrict.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneCountry(ctx context.Context, country entity.Country) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "country",
+			Document:       country,
+			Filter: bson.M{
+				"id": country.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertManyProvinces(ctx context.Context, provinces []entity.Province) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		upserts := make([]mongodb.Upsert, 0, len(provinces))
+		for _, province := range provinces {
+			upserts = append(upserts, mongodb.Upsert{
+				Filter: bson.M{
+					"id": province.Id,
+				},
+				Document: province,
+			})
+		}
+		resp := <-c.mongoDb.UpsertMany(mongodb.UpsertMany{
+			CollectionName: "province",
+			Upserts:        upserts,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertManyCities(ctx context.Context, cities []entity.City) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		upserts := make([]mongodb.Upsert, 0, len(cities))
+		for _, city := range cities {
+			upserts = append(upserts, mongodb.Upsert{
+				Filter: bson.M{
+					"id": city.Id,
+				},
+				Document: city,
+			})
+		}
+		resp := <-c.mongoDb.UpsertMany(mongodb.UpsertMany{
+			CollectionName: "city",
+			Upserts:        upserts,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertManyDistricts(ctx context.Context, districts []entity.District) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		upserts := make([]mongodb.Upsert, 0, len(districts))
+		for _, district := range districts {
+			upserts = append(upserts, mongodb.Upsert{
+				Filter: bson.M{
+					"id": district.Id,
+				},
+				Document: district,
+			})
+		}
+		resp := <-c.mongoDb.UpsertMany(mongodb.UpsertMany{
+			CollectionName: "district",
+			Upserts:        upserts,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertManySubdistricts(ctx context.Context, subdistricts []entity.SubDistrict) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		upserts := make([]mongodb.Upsert, 0, len(subdistricts))
+		for _, subdistrict := range subdistricts {
+			upserts = append(upserts, mongodb.Upsert{
+				Filter: bson.M{
+					"id": subdistrict.Id,
+				},
+				Document: subdistrict,
+			})
+		}
+		resp := <-c.mongoDb.UpsertMany(mongodb.UpsertMany{
+			CollectionName: "subdistrict",
+			Upserts:        upserts,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertManyCountries(ctx context.Context, countries []entity.Country) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		upserts := make([]mongodb.Upsert, 0, len(countries))
+		for _, country := range countries {
+			upserts = append(upserts, mongodb.Upsert{
+				Filter: bson.M{
+					"id": country.Id,
+				},
+				Document: country,
+			})
+		}
+		resp := <-c.mongoDb.UpsertMany(mongodb.UpsertMany{
+			CollectionName: "country",
+			Upserts:        upserts,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// Batch runs the writes of run one after the other, the repository has nothing to defer
+func (c commandMongodbRepository) Batch(ctx context.Context, run func(command address.MongodbRepositoryCommand) error) error {
+	return run(c)
+}
+
+// UpdateProvinceReferences copies the province names onto the cities, districts and subdistricts below it
+func (c commandMongodbRepository) UpdateProvinceReferences(ctx context.Context, province entity.Province) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		for _, collection := range []string{"city", "district", "subdistrict"} {
+			resp := <-c.mongoDb.UpdateMany(mongodb.UpdateOne{
+				CollectionName: collection,
+				Document: bson.M{
+					"provinceName":  province.Name,
+					"provinceNames": province.Names,
+				},
+				Filter: bson.M{
+					"provinceId": province.Id,
+				},
+			}, ctx)
+			if resp.Error != nil {
+				output <- resp
+				return
+			}
+		}
+		output <- wrapper.Result{Data: "Success update data"}
+	}()
+
+	return output
+}
+
+// UpdateCityReferences copies the city names and its province onto the districts and subdistricts below it
+func (c commandMongodbRepository) UpdateCityReferences(ctx context.Context, city entity.City) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		for _, collection := range []string{"district", "subdistrict"} {
+			resp := <-c.mongoDb.UpdateMany(mongodb.UpdateOne{
+				CollectionName: collection,
+				Document: bson.M{
+					"cityName":      city.Name,
+					"cityNames":     city.Names,
+					"provinceId":    city.ProvinceId,
+					"provinceName":  city.ProvinceName,
+					"provinceNames": city.ProvinceNames,
+				},
+				Filter: bson.M{
+					"cityId": city.Id,
+				},
+			}, ctx)
+			if resp.Error != nil {
+				output <- resp
+				return
+			}
+		}
+		output <- wrapper.Result{Data: "Success update data"}
+	}()
+
+	return output
+}
+
+// UpdateDistrictReferences copies the district names and its parents onto the subdistricts below it
+func (c commandMongodbRepository) UpdateDistrictReferences(ctx context.Context, district entity.District) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpdateMany(mongodb.UpdateOne{
+			CollectionName: "subdistrict",
+			Document: bson.M{
+				"districtName":  district.Name,
+				"districtNames": district.Names,
+				"cityId":        district.CityId,
+				"cityName":      district.CityName,
+				"cityNames":     district.CityNames,
+				"provinceId":    district.ProvinceId,
+				"provinceName":  district.ProvinceName,
+				"provinceNames": district.ProvinceNames,
+			},
+			Filter: bson.M{
+				"districtId": district.Id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
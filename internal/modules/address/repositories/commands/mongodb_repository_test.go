@@ -0,0 +1,172 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	mongoRC "user-service/internal/modules/address/repositories/commands"
+	"user-service/internal/pkg/databases/mongodb"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
+	mocks "user-service/mocks/pkg/databases/mongodb"
+	mocklog "user-service/mocks/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type CommandTestSuite struct {
+	suite.Suite
+	mockMongodb *mocks.Collections
+	mockLogger  *mocklog.Logger
+	repository  address.MongodbRepositoryCommand
+	ctx         context.Context
+}
+
+func (suite *CommandTestSuite) SetupTest() {
+	suite.mockMongodb = new(mocks.Collections)
+	suite.mockLogger = &mocklog.Logger{}
+	suite.repository = mongoRC.NewCommandMongodbRepository(
+		suite.mockMongodb,
+		suite.mockLogger,
+	)
+	suite.ctx = context.Background()
+}
+
+func TestCommandTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandTestSuite))
+}
+
+func (suite *CommandTestSuite) mockUpsertOne(collection string) {
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpsertOne", mock.MatchedBy(func(payload mongodb.UpdateOne) bool {
+		return payload.CollectionName == collection
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil"}
+		close(expectedResult)
+	}()
+}
+
+func (suite *CommandTestSuite) TestUpsertOneProvince() {
+	suite.mockUpsertOne("province")
+
+	result := <-suite.repository.UpsertOneProvince(suite.ctx, entity.Province{Id: "96", Name: "Papua Barat Daya"})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneCity() {
+	suite.mockUpsertOne("city")
+
+	result := <-suite.repository.UpsertOneCity(suite.ctx, entity.City{Id: "9671", ProvinceId: "96"})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneDistrict() {
+	suite.mockUpsertOne("district")
+
+	result := <-suite.repository.UpsertOneDistrict(suite.ctx, entity.District{Id: "967101", CityId: "9671"})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneSubdistrict() {
+	suite.mockUpsertOne("subdistrict")
+
+	result := <-suite.repository.UpsertOneSubdistrict(suite.ctx, entity.SubDistrict{Id: "9671011001", DistrictId: "967101"})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneCountry() {
+	suite.mockUpsertOne("country")
+
+	result := <-suite.repository.UpsertOneCountry(suite.ctx, entity.Country{Id: 1, Code: "ID"})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) mockUpsertMany(collection string, ids ...string) {
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpsertMany", mock.MatchedBy(func(payload mongodb.UpsertMany) bool {
+		if payload.CollectionName != collection || len(payload.Upserts) != len(ids) {
+			return false
+		}
+		for i, upsert := range payload.Upserts {
+			if upsert.Filter.(bson.M)["id"] != ids[i] {
+				return false
+			}
+		}
+		return true
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Count: int64(len(ids))}
+		close(expectedResult)
+	}()
+}
+
+func (suite *CommandTestSuite) TestUpsertManyCities() {
+	suite.mockUpsertMany("city", "9671", "9672")
+
+	result := <-suite.repository.UpsertManyCities(suite.ctx, []entity.City{{Id: "9671", ProvinceId: "96"}, {Id: "9672", ProvinceId: "96"}})
+
+	assert.Nil(suite.T(), result.Error)
+	assert.Equal(suite.T(), int64(2), result.Count)
+}
+
+func (suite *CommandTestSuite) TestUpsertManySubdistricts() {
+	suite.mockUpsertMany("subdistrict", "9671010001")
+
+	result := <-suite.repository.UpsertManySubdistricts(suite.ctx, []entity.SubDistrict{{Id: "9671010001", DistrictId: "967101"}})
+
+	assert.Nil(suite.T(), result.Error)
+}
+
+func (suite *CommandTestSuite) TestUpdateProvinceReferences() {
+	suite.mockMongodb.On("UpdateMany", mock.Anything, mock.Anything).Return(func(payload mongodb.UpdateOne, ctx context.Context) <-chan helpers.Result {
+		output := make(chan helpers.Result, 1)
+		output <- helpers.Result{Data: "Success update data"}
+		close(output)
+		return output
+	})
+
+	result := <-suite.repository.UpdateProvinceReferences(suite.ctx, entity.Province{Id: "96", Name: "Papua Barat Daya"})
+
+	assert.Nil(suite.T(), result.Error)
+	for _, collection := range []string{"city", "district", "subdistrict"} {
+		suite.mockMongodb.AssertCalled(suite.T(), "UpdateMany", mock.MatchedBy(func(payload mongodb.UpdateOne) bool {
+			return payload.CollectionName == collection
+		}), mock.Anything)
+	}
+}
+
+func (suite *CommandTestSuite) TestUpdateCityReferencesErr() {
+	expectedResult := make(chan helpers.Result, 1)
+	expectedResult <- helpers.Result{Error: errors.InternalServerError("Error mongodb connection")}
+	close(expectedResult)
+	suite.mockMongodb.On("UpdateMany", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult)).Once()
+
+	result := <-suite.repository.UpdateCityReferences(suite.ctx, entity.City{Id: "9671", Name: "Kota Sorong", ProvinceId: "96"})
+
+	assert.NotNil(suite.T(), result.Error)
+	suite.mockMongodb.AssertNumberOfCalls(suite.T(), "UpdateMany", 1)
+}
+
+func (suite *CommandTestSuite) TestUpdateDistrictReferences() {
+	expectedResult := make(chan helpers.Result, 1)
+	expectedResult <- helpers.Result{Data: "Success update data", Count: 12}
+	close(expectedResult)
+	suite.mockMongodb.On("UpdateMany", mock.MatchedBy(func(payload mongodb.UpdateOne) bool {
+		return payload.CollectionName == "subdistrict"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	result := <-suite.repository.UpdateDistrictReferences(suite.ctx, entity.District{Id: "967101", Name: "Sorong"})
+
+	assert.Nil(suite.T(), result.Error)
+	assert.Equal(suite.T(), int64(12), result.Count)
+}
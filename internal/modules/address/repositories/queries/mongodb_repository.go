@@ -2,11 +2,13 @@ package queries
 
 import (
 	"context"
-	"fmt"
+	"strconv"
+	"strings"
 	"user-service/internal/modules/address"
 	"user-service/internal/modules/address/models/entity"
 	"user-service/internal/modules/address/models/request"
 	"user-service/internal/pkg/databases/mongodb"
+	"user-service/internal/pkg/errors"
 	wrapper "user-service/internal/pkg/helpers"
 	"user-service/internal/pkg/log"
 
@@ -26,25 +28,58 @@ func NewQueryMongodbRepository(mongodb mongodb.Collections, log log.Logger) addr
 	}
 }
 
+// nameFilter matches the search in the default name or in any localized name
+func nameFilter(search string) bson.A {
+	pattern := ".*" + search + ".*"
+	return bson.A{
+		bson.M{"name": primitive.Regex{Pattern: pattern, Options: "i"}},
+		bson.M{"$expr": bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+			"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$names", bson.M{}}}},
+			"in":    bson.M{"$regexMatch": bson.M{"input": "$$this.v", "regex": pattern, "options": "i"}},
+		}}}}},
+	}
+}
+
+// find runs a page and size query, or reads a keyset page from cursor when no page is given. A keyset
+// page is only counted on request.
+func (q queryMongodbRepository) find(ctx context.Context, payload mongodb.FindAllData, cursor string, count bool) <-chan wrapper.Result {
+	if payload.Page > 0 {
+		return q.mongoDb.FindAllData(payload, ctx)
+	}
+	countData := payload.CountData
+	if !count {
+		countData = nil
+	}
+	return q.mongoDb.FindByCursor(mongodb.FindByCursor{
+		Result:         payload.Result,
+		CountData:      countData,
+		CollectionName: payload.CollectionName,
+		Filter:         payload.Filter,
+		Sort:           *payload.Sort,
+		Cursor:         cursor,
+		Size:           payload.Size,
+	}, ctx)
+}
+
 func (q queryMongodbRepository) FindProvinces(ctx context.Context, payload request.Province) <-chan wrapper.Result {
 	var province []entity.Province
 	var countData int64
 	output := make(chan wrapper.Result)
 
 	go func() {
-		// var filter interface{}
-		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+		resp := <-q.find(ctx, mongodb.FindAllData{
 			Result:         &province,
 			CountData:      &countData,
 			CollectionName: "province",
-			Filter:         bson.M{"name": primitive.Regex{Pattern: ".*" + payload.Search + ".*", Options: "i"}},
+			Filter: bson.M{"$or": nameFilter(payload.Search),
+				"deactivated": bson.M{"$ne": true}},
 			Sort: &mongodb.Sort{
 				FieldName: "name",
 				By:        mongodb.SortAscending,
 			},
 			Page: payload.Page,
 			Size: payload.Size,
-		}, ctx)
+		}, payload.Cursor, payload.Count)
 		output <- resp
 		close(output)
 	}()
@@ -58,20 +93,20 @@ func (q queryMongodbRepository) FindCitiesByParam(ctx context.Context, payload r
 	output := make(chan wrapper.Result)
 
 	go func() {
-		// var filter interface{}
-		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+		resp := <-q.find(ctx, mongodb.FindAllData{
 			Result:         &city,
 			CountData:      &countData,
 			CollectionName: "city",
 			Filter: bson.M{"provinceId": payload.ProvinceId,
-				"name": primitive.Regex{Pattern: ".*" + payload.Search + ".*", Options: "i"}},
+				"$or":         nameFilter(payload.Search),
+				"deactivated": bson.M{"$ne": true}},
 			Sort: &mongodb.Sort{
 				FieldName: "name",
 				By:        mongodb.SortAscending,
 			},
 			Page: payload.Page,
 			Size: payload.Size,
-		}, ctx)
+		}, payload.Cursor, payload.Count)
 		output <- resp
 		close(output)
 	}()
@@ -85,20 +120,20 @@ func (q queryMongodbRepository) FindDistrictByParam(ctx context.Context, payload
 	output := make(chan wrapper.Result)
 
 	go func() {
-		// var filter interface{}
-		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+		resp := <-q.find(ctx, mongodb.FindAllData{
 			Result:         &district,
 			CountData:      &countData,
 			CollectionName: "district",
 			Filter: bson.M{"cityId": payload.CityId, "provinceId": payload.ProvinceId,
-				"name": primitive.Regex{Pattern: ".*" + payload.Search + ".*", Options: "i"}},
+				"$or":         nameFilter(payload.Search),
+				"deactivated": bson.M{"$ne": true}},
 			Sort: &mongodb.Sort{
 				FieldName: "name",
 				By:        mongodb.SortAscending,
 			},
 			Page: payload.Page,
 			Size: payload.Size,
-		}, ctx)
+		}, payload.Cursor, payload.Count)
 		output <- resp
 		close(output)
 	}()
@@ -112,19 +147,191 @@ func (q queryMongodbRepository) FindSubDistrictByParam(ctx context.Context, payl
 	output := make(chan wrapper.Result)
 
 	go func() {
-		// var filter interface{}
-		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+		resp := <-q.find(ctx, mongodb.FindAllData{
 			Result:         &subDistrict,
 			CountData:      &countData,
 			CollectionName: "subdistrict",
 			Filter: bson.M{"districtId": payload.DistrictId, "cityId": payload.CityId,
-				"provinceId": payload.ProvinceId, "name": bson.M{"$regex": fmt.Sprintf(".*%s.*", payload.Search), "$options": "i"}},
+				"provinceId": payload.ProvinceId, "$or": nameFilter(payload.Search),
+				"deactivated": bson.M{"$ne": true}},
 			Sort: &mongodb.Sort{
 				FieldName: "name",
 				By:        mongodb.SortAscending,
 			},
 			Page: payload.Page,
 			Size: payload.Size,
+		}, payload.Cursor, payload.Count)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneProvince(ctx context.Context, id string) <-chan wrapper.Result {
+	var province entity.Province
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &province,
+			CollectionName: "province",
+			Filter: bson.M{
+				"id": id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneCity(ctx context.Context, id string) <-chan wrapper.Result {
+	var city entity.City
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &city,
+			CollectionName: "city",
+			Filter: bson.M{
+				"id": id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneDistrict(ctx context.Context, id string) <-chan wrapper.Result {
+	var district entity.District
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &district,
+			CollectionName: "district",
+			Filter: bson.M{
+				"id": id,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// FindAllSubdistricts reads every active subdistrict in id order one keyset page at a time, it feeds the search index
+func (q queryMongodbRepository) FindAllSubdistricts(ctx context.Context, cursor string, size int64) <-chan wrapper.Result {
+	var subDistrict []entity.SubDistrict
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindByCursor(mongodb.FindByCursor{
+			Result:         &subDistrict,
+			CollectionName: "subdistrict",
+			Filter:         bson.M{"deactivated": bson.M{"$ne": true}},
+			Sort: mongodb.Sort{
+				FieldName: "id",
+				By:        mongodb.SortAscending,
+			},
+			Cursor: cursor,
+			Size:   size,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// FindAllRegions reads every region of a level in id order one keyset page at a time, deactivated regions
+// included. It feeds the offline dataset.
+func (q queryMongodbRepository) FindAllRegions(ctx context.Context, level string, cursor string, size int64) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+
+		var result interface{}
+		switch level {
+		case request.LevelProvince:
+			result = &[]entity.Province{}
+		case request.LevelCity:
+			result = &[]entity.City{}
+		case request.LevelDistrict:
+			result = &[]entity.District{}
+		case request.LevelSubdistrict:
+			result = &[]entity.SubDistrict{}
+		default:
+			output <- wrapper.Result{Error: errors.BadRequest("unknown region level")}
+			return
+		}
+		output <- <-q.mongoDb.FindByCursor(mongodb.FindByCursor{
+			Result:         result,
+			CollectionName: level,
+			Filter:         bson.M{},
+			Sort: mongodb.Sort{
+				FieldName: "id",
+				By:        mongodb.SortAscending,
+			},
+			Cursor: cursor,
+			Size:   size,
+		}, ctx)
+	}()
+
+	return output
+}
+
+// postalCodeLimit bounds a postal code lookup, one code covers a handful of subdistricts at most
+const postalCodeLimit = 50
+
+func (q queryMongodbRepository) FindSubdistrictsByPostalCode(ctx context.Context, postalCode string) <-chan wrapper.Result {
+	var subDistrict []entity.SubDistrict
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &subDistrict,
+			CollectionName: "subdistrict",
+			Filter:         bson.M{"postalCode": postalCode, "deactivated": bson.M{"$ne": true}},
+			Sort: &mongodb.Sort{
+				FieldName: "name",
+				By:        mongodb.SortAscending,
+			},
+			Page: 1,
+			Size: postalCodeLimit,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// FindNearestSubdistrict needs the 2dsphere index on the subdistrict centroid
+func (q queryMongodbRepository) FindNearestSubdistrict(ctx context.Context, latitude float64, longitude float64, maxDistance float64) <-chan wrapper.Result {
+	var subDistrict []entity.SubDistrictDistance
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.Aggregate(mongodb.Aggregate{
+			Result:         &subDistrict,
+			CollectionName: "subdistrict",
+			Filter: bson.A{
+				bson.M{"$geoNear": bson.M{
+					"near":          bson.M{"type": "Point", "coordinates": bson.A{longitude, latitude}},
+					"distanceField": "distance",
+					"maxDistance":   maxDistance,
+					"query":         bson.M{"deactivated": bson.M{"$ne": true}},
+					"spherical":     true,
+				}},
+				bson.M{"$limit": 1},
+			},
 		}, ctx)
 		output <- resp
 		close(output)
@@ -158,18 +365,27 @@ func (q queryMongodbRepository) FindCountries(ctx context.Context, payload reque
 	output := make(chan wrapper.Result)
 
 	go func() {
-		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+		filter := bson.M{"$or": nameFilter(payload.Search),
+			"deactivated": bson.M{"$ne": true}}
+		if payload.ContinentCode != "" {
+			filter["continentCode"] = payload.ContinentCode
+		}
+		sortField := "name"
+		if payload.Sort == "displayOrder" {
+			sortField = "displayOrder"
+		}
+		resp := <-q.find(ctx, mongodb.FindAllData{
 			Result:         &country,
 			CountData:      &countData,
 			CollectionName: "country",
-			Filter:         bson.M{"name": primitive.Regex{Pattern: ".*" + payload.Search + ".*", Options: "i"}},
+			Filter:         filter,
 			Sort: &mongodb.Sort{
-				FieldName: "name",
+				FieldName: sortField,
 				By:        mongodb.SortAscending,
 			},
 			Page: payload.Page,
 			Size: payload.Size,
-		}, ctx)
+		}, payload.Cursor, payload.Count)
 		output <- resp
 		close(output)
 	}()
@@ -196,6 +412,30 @@ func (q queryMongodbRepository) FindOneCountry(ctx context.Context, id int) <-ch
 	return output
 }
 
+// FindOneCountryByCode finds a country by its ISO 3166-1 alpha-2, alpha-3 or numeric code, deactivated countries are returned
+func (q queryMongodbRepository) FindOneCountryByCode(ctx context.Context, code string) <-chan wrapper.Result {
+	var country entity.Country
+	output := make(chan wrapper.Result)
+
+	go func() {
+		filter := bson.M{"code": strings.ToUpper(code)}
+		if number, err := strconv.Atoi(code); err == nil {
+			filter = bson.M{"number": number}
+		} else if len(code) == 3 {
+			filter = bson.M{"iso3": strings.ToUpper(code)}
+		}
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &country,
+			CollectionName: "country",
+			Filter:         filter,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
 func (q queryMongodbRepository) FindContinent(ctx context.Context) <-chan wrapper.Result {
 	var continent []entity.Continent
 	var countData int64
@@ -220,3 +460,36 @@ func (q queryMongodbRepository) FindContinent(ctx context.Context) <-chan wrappe
 
 	return output
 }
+
+// childCollections maps a region level to the collection and parent field of the level below it
+var childCollections = map[string][2]string{
+	request.LevelProvince: {"city", "provinceId"},
+	request.LevelCity:     {"district", "cityId"},
+	request.LevelDistrict: {"subdistrict", "districtId"},
+}
+
+func (q queryMongodbRepository) CountActiveChildren(ctx context.Context, level string, id string) <-chan wrapper.Result {
+	var countData int64
+	output := make(chan wrapper.Result)
+
+	go func() {
+		child, ok := childCollections[level]
+		if !ok {
+			output <- wrapper.Result{}
+			close(output)
+			return
+		}
+		resp := <-q.mongoDb.CountData(mongodb.CountData{
+			Result:         &countData,
+			CollectionName: child[0],
+			Filter: bson.M{
+				child[1]:      id,
+				"deactivated": bson.M{"$ne": true},
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
@@ -2,10 +2,14 @@ package queries_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
 	"user-service/internal/modules/address/models/request"
 	mongoRQ "user-service/internal/modules/address/repositories/queries"
+	"user-service/internal/pkg/databases/mongodb"
+	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
 	mocks "user-service/mocks/pkg/databases/mongodb"
 	mocklog "user-service/mocks/pkg/log"
@@ -13,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type CommandTestSuite struct {
@@ -39,7 +44,7 @@ func TestCommandTestSuite(t *testing.T) {
 
 func (suite *CommandTestSuite) TestFindProvinces() {
 
-	request := request.Province{}
+	request := request.Province{Page: 1, Size: 10}
 	// Mock FindOne
 	expectedResult := make(chan helpers.Result)
 	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
@@ -64,7 +69,7 @@ func (suite *CommandTestSuite) TestFindProvinces() {
 
 func (suite *CommandTestSuite) TestCitiesByParam() {
 
-	request := request.City{}
+	request := request.City{Page: 1, Size: 10}
 	// Mock FindOne
 	expectedResult := make(chan helpers.Result)
 	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
@@ -89,7 +94,7 @@ func (suite *CommandTestSuite) TestCitiesByParam() {
 
 func (suite *CommandTestSuite) TestDistrictByParam() {
 
-	request := request.District{}
+	request := request.District{Page: 1, Size: 10}
 	// Mock FindOne
 	expectedResult := make(chan helpers.Result)
 	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
@@ -114,7 +119,7 @@ func (suite *CommandTestSuite) TestDistrictByParam() {
 
 func (suite *CommandTestSuite) TestSubdistrictByParam() {
 
-	request := request.SubDistrict{}
+	request := request.SubDistrict{Page: 1, Size: 10}
 	// Mock FindOne
 	expectedResult := make(chan helpers.Result)
 	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
@@ -163,7 +168,7 @@ func (suite *CommandTestSuite) TestFindOneSubdistrict() {
 
 func (suite *CommandTestSuite) TestFindCountries() {
 
-	request := request.Country{}
+	request := request.Country{Page: 1, Size: 10}
 	// Mock FindOne
 	expectedResult := make(chan helpers.Result)
 	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
@@ -232,3 +237,275 @@ func (suite *CommandTestSuite) TestFindContinent() {
 	// Assert FindOne
 	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
 }
+
+func (suite *CommandTestSuite) TestFindOneProvince() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneProvince(suite.ctx, "32")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindOneCity() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneCity(suite.ctx, "3273")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindOneDistrict() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneDistrict(suite.ctx, "327301")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestCountActiveChildren() {
+
+	// Mock CountData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("CountData", mock.MatchedBy(func(payload mongodb.CountData) bool {
+		return payload.CollectionName == "city"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.CountActiveChildren(suite.ctx, request.LevelProvince, "32")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Count: 2}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), int64(2), (<-result).Count)
+}
+
+func (suite *CommandTestSuite) TestCountActiveChildrenSubdistrict() {
+	result := <-suite.repository.CountActiveChildren(suite.ctx, request.LevelSubdistrict, "3273011001")
+
+	assert.Equal(suite.T(), int64(0), result.Count)
+	suite.mockMongodb.AssertNotCalled(suite.T(), "CountData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindAllSubdistricts() {
+
+	// Mock FindByCursor
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindByCursor", mock.MatchedBy(func(payload mongodb.FindByCursor) bool {
+		return payload.CollectionName == "subdistrict" && payload.Sort.FieldName == "id" && payload.Cursor == "next" && payload.Size == 5000
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindAllSubdistricts(suite.ctx, "next", 5000)
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindAllRegions() {
+
+	// Mock FindByCursor
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindByCursor", mock.MatchedBy(func(payload mongodb.FindByCursor) bool {
+		_, ok := payload.Result.(*[]entity.City)
+		return ok && payload.CollectionName == "city" && payload.Sort.FieldName == "id" && payload.Cursor == "next" && payload.Size == 5000
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindAllRegions(suite.ctx, request.LevelCity, "next", 5000)
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindAllRegionsUnknownLevel() {
+	result := <-suite.repository.FindAllRegions(suite.ctx, request.LevelCountry, "", 5000)
+
+	assert.Equal(suite.T(), errors.BadRequest("unknown region level"), result.Error)
+	suite.mockMongodb.AssertNotCalled(suite.T(), "FindByCursor", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindSubdistrictsByPostalCode() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.MatchedBy(func(payload mongodb.FindAllData) bool {
+		filter := payload.Filter.(bson.M)
+		return payload.CollectionName == "subdistrict" && filter["postalCode"] == "98414"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindSubdistrictsByPostalCode(suite.ctx, "98414")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindNearestSubdistrict() {
+
+	// Mock Aggregate
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("Aggregate", mock.MatchedBy(func(payload mongodb.Aggregate) bool {
+		return payload.CollectionName == "subdistrict"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindNearestSubdistrict(suite.ctx, -0.88, 131.28, 25000)
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindOneCountryByCode() {
+	for code, filter := range map[string]bson.M{
+		"id":  {"code": "ID"},
+		"idn": {"iso3": "IDN"},
+		"360": {"number": 360},
+	} {
+		// Mock FindOne
+		expectedResult := make(chan helpers.Result)
+		suite.mockMongodb.On("FindOne", mock.MatchedBy(func(payload mongodb.FindOne) bool {
+			return reflect.DeepEqual(payload.Filter, filter)
+		}), mock.Anything).Return((<-chan helpers.Result)(expectedResult)).Once()
+
+		// Act
+		result := suite.repository.FindOneCountryByCode(suite.ctx, code)
+
+		// Simulate receiving a result from the channel
+		go func() {
+			expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+			close(expectedResult)
+		}()
+
+		assert.Equal(suite.T(), "result not nil", (<-result).Data)
+	}
+}
+
+func (suite *CommandTestSuite) TestFindCountriesByContinent() {
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.MatchedBy(func(payload mongodb.FindAllData) bool {
+		return payload.Filter.(bson.M)["continentCode"] == "AS" && payload.Sort.FieldName == "displayOrder"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindCountries(suite.ctx, request.Country{Page: 1, Size: 10, ContinentCode: "AS", Sort: "displayOrder"})
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindProvincesByCursor() {
+	// Mock FindByCursor
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindByCursor", mock.MatchedBy(func(payload mongodb.FindByCursor) bool {
+		return payload.CollectionName == "province" && payload.Sort.FieldName == "name" && payload.Cursor == "next" &&
+			payload.Size == 10 && payload.CountData == nil
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindProvinces(suite.ctx, request.Province{Size: 10, Cursor: "next"})
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", MetaData: "after"}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "after", (<-result).MetaData)
+	suite.mockMongodb.AssertNotCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindCountriesByCursorWithCount() {
+	// Mock FindByCursor
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindByCursor", mock.MatchedBy(func(payload mongodb.FindByCursor) bool {
+		return payload.CollectionName == "country" && payload.Sort.FieldName == "displayOrder" && payload.CountData != nil
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindCountries(suite.ctx, request.Country{Size: 10, Count: true, Sort: "displayOrder"})
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Count: 250}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), int64(250), (<-result).Count)
+}
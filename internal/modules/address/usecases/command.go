@@ -0,0 +1,945 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
+	"user-service/internal/pkg/log"
+
+	"github.com/go-playground/validator/v10"
+	"go.elastic.co/apm"
+)
+
+type commandUsecase struct {
+	addressRepositoryCommand address.MongodbRepositoryCommand
+	addressRepositoryQuery   address.MongodbRepositoryQuery
+	logger                   log.Logger
+}
+
+func NewCommandUsecase(amc address.MongodbRepositoryCommand, amq address.MongodbRepositoryQuery, log log.Logger) address.UsecaseCommand {
+	return commandUsecase{
+		addressRepositoryCommand: amc,
+		addressRepositoryQuery:   amq,
+		logger:                   log,
+	}
+}
+
+// maxImportRows bounds one import file, bigger datasets are imported per level or per province
+const maxImportRows = 10000
+
+// parentLevels is the level each region level hangs below
+var parentLevels = map[string]string{
+	request.LevelCity:        request.LevelProvince,
+	request.LevelDistrict:    request.LevelCity,
+	request.LevelSubdistrict: request.LevelDistrict,
+}
+
+// rowValidator names the failing fields of an import row after their json keys
+var rowValidator = newRowValidator()
+
+func newRowValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+func (c commandUsecase) CreateRegion(origCtx context.Context, payload request.Region) (*response.Region, error) {
+	domain := "addressUsecase-CreateRegion"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findRegion(ctx, payload.Level, payload.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		msg := "Region already exists"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.Conflict(msg), map[string]string{"id": "already exists"})
+	}
+
+	region, fields, err := c.buildRegion(ctx, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		msg := "Region is not valid"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	if err := c.saveRegion(ctx, *region, nil); err != nil {
+		return nil, err
+	}
+	return region, nil
+}
+
+func (c commandUsecase) UpdateRegion(origCtx context.Context, payload request.Region) (*response.Region, error) {
+	domain := "addressUsecase-UpdateRegion"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findRegion(ctx, payload.Level, payload.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		msg := "Region not found"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.NotFound(msg)
+	}
+
+	region, fields, err := c.buildRegion(ctx, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		msg := "Region is not valid"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	region.Deactivated = existing.Deactivated
+	if region.Centroid == nil {
+		region.Centroid = existing.Centroid
+	}
+	if region.Names == nil {
+		region.Names = existing.Names
+	}
+	if err := c.saveRegion(ctx, *region, existing); err != nil {
+		return nil, err
+	}
+	return region, nil
+}
+
+func (c commandUsecase) DeactivateRegion(origCtx context.Context, payload request.RegionId) error {
+	domain := "addressUsecase-DeactivateRegion"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findRegion(ctx, payload.Level, payload.Id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		msg := "Region not found"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return errors.NotFound(msg)
+	}
+	if existing.Deactivated {
+		return nil
+	}
+
+	respCount := <-c.addressRepositoryQuery.CountActiveChildren(ctx, payload.Level, payload.Id)
+	if respCount.Error != nil {
+		c.logger.Error(ctx, "Error count region children", fmt.Sprintf("%+v", respCount.Error))
+		return respCount.Error
+	}
+	if respCount.Count > 0 {
+		msg := "Region still has active children, move or deactivate them first"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return errors.UnprocessableEntity(msg)
+	}
+
+	existing.Deactivated = true
+	return c.saveRegion(ctx, *existing, nil)
+}
+
+func (c commandUsecase) CreateCountry(origCtx context.Context, payload request.SaveCountry) (*response.Country, error) {
+	domain := "addressUsecase-CreateCountry"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findCountry(ctx, payload.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		msg := "Country already exists"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.Conflict(msg), map[string]string{"id": "already exists"})
+	}
+
+	continents, err := c.loadContinents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	country, fields := buildCountry(payload, continents)
+	if len(fields) > 0 {
+		msg := "Country is not valid"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	if err := c.saveCountry(ctx, country); err != nil {
+		return nil, err
+	}
+	return countryResponse(country, nil), nil
+}
+
+func (c commandUsecase) UpdateCountry(origCtx context.Context, payload request.SaveCountry) (*response.Country, error) {
+	domain := "addressUsecase-UpdateCountry"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findCountry(ctx, payload.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		msg := "Country not found"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.NotFound(msg)
+	}
+
+	continents, err := c.loadContinents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	country, fields := buildCountry(payload, continents)
+	if len(fields) > 0 {
+		msg := "Country is not valid"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	country.Deactivated = existing.Deactivated
+	if country.Names == nil {
+		country.Names = existing.Names
+	}
+	if err := c.saveCountry(ctx, country); err != nil {
+		return nil, err
+	}
+	return countryResponse(country, nil), nil
+}
+
+func (c commandUsecase) DeactivateCountry(origCtx context.Context, id int) error {
+	domain := "addressUsecase-DeactivateCountry"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	existing, err := c.findCountry(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		msg := "Country not found"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", id))
+		return errors.NotFound(msg)
+	}
+	if existing.Deactivated {
+		return nil
+	}
+	existing.Deactivated = true
+	return c.saveCountry(ctx, *existing)
+}
+
+// ImportRegions validates every row before saving any of them, invalid rows are reported by
+// their 1-based position in the file (header excluded) and nothing is written
+func (c commandUsecase) ImportRegions(origCtx context.Context, payload request.ImportRegions) (*response.ImportReport, error) {
+	domain := "addressUsecase-ImportRegions"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	rows, err := parseImportRows(payload.Format, payload.Content)
+	if err != nil {
+		msg := "Import file cannot be parsed"
+		c.logger.Error(ctx, msg, err.Error())
+		return nil, errors.BadRequest(fmt.Sprintf("%s: %s", msg, err.Error()))
+	}
+	if len(rows) == 0 {
+		return nil, errors.BadRequest("Import file has no rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, errors.UnprocessableEntity(fmt.Sprintf("Import is limited to %d rows", maxImportRows))
+	}
+
+	report := response.ImportReport{
+		Level:  payload.Level,
+		DryRun: payload.DryRun,
+		Total:  len(rows),
+	}
+	var fields map[string]string
+	var apply func() error
+	if payload.Level == request.LevelCountry {
+		fields, apply, err = c.planCountries(ctx, rows, &report)
+	} else {
+		fields, apply, err = c.planRegions(ctx, payload.Level, rows, &report)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		msg := "Import has invalid rows"
+		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", fields))
+		return nil, errors.WithFields(errors.UnprocessableEntity(msg), fields)
+	}
+	if payload.DryRun {
+		return &report, nil
+	}
+	if err := apply(); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// regionChange is one validated import row, existing is nil for a new region
+type regionChange struct {
+	region   response.Region
+	existing *response.Region
+}
+
+func (c commandUsecase) planRegions(ctx context.Context, level string, rows []map[string]string, report *response.ImportReport) (map[string]string, func() error, error) {
+	fields := map[string]string{}
+	parents := map[string]*response.Region{}
+	seen := map[string]int{}
+	changes := make([]regionChange, 0, len(rows))
+
+	for i, row := range rows {
+		payload := request.Region{
+			Level:      level,
+			Id:         row["id"],
+			Name:       row["name"],
+			Names:      rowNames(row),
+			ParentId:   row["parentId"],
+			PostalCode: row["postalCode"],
+		}
+		rowErrors := map[string]string{}
+		for key, target := range map[string]**float64{"latitude": &payload.Latitude, "longitude": &payload.Longitude} {
+			if row[key] == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(row[key], 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				rowErrors[key] = "must be a number"
+				continue
+			}
+			*target = &value
+		}
+		for key, value := range rowFields(payload) {
+			if _, ok := rowErrors[key]; !ok {
+				rowErrors[key] = value
+			}
+		}
+		if first, ok := seen[payload.Id]; ok && payload.Id != "" {
+			rowErrors["id"] = fmt.Sprintf("is duplicated, first seen in row %d", first)
+		} else {
+			seen[payload.Id] = i + 1
+		}
+		if len(rowErrors) == 0 {
+			region, parentErrors, err := c.buildRegion(ctx, payload, parents)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(parentErrors) > 0 {
+				rowErrors = parentErrors
+			} else {
+				existing, err := c.findRegion(ctx, level, payload.Id)
+				if err != nil {
+					return nil, nil, err
+				}
+				if existing != nil {
+					region.Deactivated = existing.Deactivated
+					if region.Centroid == nil {
+						region.Centroid = existing.Centroid
+					}
+					if region.Names == nil {
+						region.Names = existing.Names
+					}
+					report.Updated++
+				} else {
+					report.Created++
+				}
+				changes = append(changes, regionChange{region: *region, existing: existing})
+			}
+		}
+		for key, value := range rowErrors {
+			fields[fmt.Sprintf("rows[%d].%s", i+1, key)] = value
+		}
+	}
+
+	apply := func() error {
+		return c.addressRepositoryCommand.Batch(ctx, func(command address.MongodbRepositoryCommand) error {
+			return c.saveRegions(ctx, command, level, changes)
+		})
+	}
+	return fields, apply, nil
+}
+
+func (c commandUsecase) planCountries(ctx context.Context, rows []map[string]string, report *response.ImportReport) (map[string]string, func() error, error) {
+	continents, err := c.loadContinents(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]string{}
+	seen := map[int]int{}
+	changes := make([]entity.Country, 0, len(rows))
+
+	for i, row := range rows {
+		payload := request.SaveCountry{
+			Code:          row["code"],
+			Name:          row["name"],
+			Names:         rowNames(row),
+			Iso3:          row["iso3"],
+			ContinentCode: row["continentCode"],
+			FullName:      row["fullName"],
+		}
+		rowErrors := map[string]string{}
+		for key, target := range map[string]*int{"id": &payload.Id, "number": &payload.Number, "displayOrder": &payload.DisplayOrder} {
+			if row[key] == "" {
+				continue
+			}
+			value, err := strconv.Atoi(row[key])
+			if err != nil {
+				rowErrors[key] = "must be an integer"
+				continue
+			}
+			*target = value
+		}
+		for key, value := range rowFields(payload) {
+			if _, ok := rowErrors[key]; !ok {
+				rowErrors[key] = value
+			}
+		}
+		if first, ok := seen[payload.Id]; ok && payload.Id != 0 {
+			rowErrors["id"] = fmt.Sprintf("is duplicated, first seen in row %d", first)
+		} else {
+			seen[payload.Id] = i + 1
+		}
+		if len(rowErrors) == 0 {
+			country, continentErrors := buildCountry(payload, continents)
+			if len(continentErrors) > 0 {
+				rowErrors = continentErrors
+			} else {
+				existing, err := c.findCountry(ctx, payload.Id)
+				if err != nil {
+					return nil, nil, err
+				}
+				if existing != nil {
+					country.Deactivated = existing.Deactivated
+					if country.Names == nil {
+						country.Names = existing.Names
+					}
+					report.Updated++
+				} else {
+					report.Created++
+				}
+				changes = append(changes, country)
+			}
+		}
+		for key, value := range rowErrors {
+			fields[fmt.Sprintf("rows[%d].%s", i+1, key)] = value
+		}
+	}
+
+	apply := func() error {
+		resp := <-c.addressRepositoryCommand.UpsertManyCountries(ctx, changes)
+		if resp.Error != nil {
+			c.logger.Error(ctx, "Error save countries", fmt.Sprintf("%+v", resp.Error))
+			return resp.Error
+		}
+		return nil
+	}
+	return fields, apply, nil
+}
+
+// findRegion is nil when the region does not exist, deactivated regions are returned
+func (c commandUsecase) findRegion(ctx context.Context, level string, id string) (*response.Region, error) {
+	var resp helpers.Result
+	switch level {
+	case request.LevelProvince:
+		resp = <-c.addressRepositoryQuery.FindOneProvince(ctx, id)
+	case request.LevelCity:
+		resp = <-c.addressRepositoryQuery.FindOneCity(ctx, id)
+	case request.LevelDistrict:
+		resp = <-c.addressRepositoryQuery.FindOneDistrict(ctx, id)
+	case request.LevelSubdistrict:
+		resp = <-c.addressRepositoryQuery.FindOneSubdistrict(ctx, id)
+	default:
+		return nil, errors.BadRequest("unknown region level")
+	}
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error query region", fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+
+	switch data := resp.Data.(type) {
+	case *entity.Province:
+		return &response.Region{
+			Level:       request.LevelProvince,
+			Id:          data.Id,
+			Name:        data.Name,
+			Names:       data.Names,
+			Deactivated: data.Deactivated,
+		}, nil
+	case *entity.City:
+		return &response.Region{
+			Level:         request.LevelCity,
+			Id:            data.Id,
+			Name:          data.Name,
+			Names:         data.Names,
+			ProvinceId:    data.ProvinceId,
+			ProvinceName:  data.ProvinceName,
+			ProvinceNames: data.ProvinceNames,
+			Deactivated:   data.Deactivated,
+		}, nil
+	case *entity.District:
+		return &response.Region{
+			Level:         request.LevelDistrict,
+			Id:            data.Id,
+			Name:          data.Name,
+			Names:         data.Names,
+			CityId:        data.CityId,
+			CityName:      data.CityName,
+			CityNames:     data.CityNames,
+			ProvinceId:    data.ProvinceId,
+			ProvinceName:  data.ProvinceName,
+			ProvinceNames: data.ProvinceNames,
+			Deactivated:   data.Deactivated,
+		}, nil
+	case *entity.SubDistrict:
+		return &response.Region{
+			Level:         request.LevelSubdistrict,
+			Id:            data.Id,
+			Name:          data.Name,
+			Names:         data.Names,
+			DistrictId:    data.DistrictId,
+			DistrictName:  data.DistrictName,
+			DistrictNames: data.DistrictNames,
+			CityId:        data.CityId,
+			CityName:      data.CityName,
+			CityNames:     data.CityNames,
+			ProvinceId:    data.ProvinceId,
+			ProvinceName:  data.ProvinceName,
+			ProvinceNames: data.ProvinceNames,
+			PostalCode:    data.PostalCode,
+			Centroid:      locationOf(data.Centroid),
+			Deactivated:   data.Deactivated,
+		}, nil
+	}
+	return nil, errors.InternalServerError("cannot parsing data region")
+}
+
+// buildRegion places the payload below its parent, the returned fields describe a missing or inactive parent.
+// Parents found are kept in cache when it is not nil.
+func (c commandUsecase) buildRegion(ctx context.Context, payload request.Region, cache map[string]*response.Region) (*response.Region, map[string]string, error) {
+	if payload.Level != request.LevelSubdistrict {
+		if payload.PostalCode != "" {
+			return nil, map[string]string{"postalCode": "is only set on subdistricts"}, nil
+		}
+		if payload.Latitude != nil {
+			return nil, map[string]string{"latitude": "is only set on subdistricts"}, nil
+		}
+	}
+	region := response.Region{
+		Level:      payload.Level,
+		Id:         payload.Id,
+		Name:       payload.Name,
+		Names:      normalizeNames(payload.Names),
+		PostalCode: payload.PostalCode,
+	}
+	if payload.Latitude != nil && payload.Longitude != nil {
+		region.Centroid = &response.Location{Latitude: *payload.Latitude, Longitude: *payload.Longitude}
+	}
+	parentLevel, ok := parentLevels[payload.Level]
+	if !ok {
+		return &region, nil, nil
+	}
+	if payload.ParentId == "" {
+		return nil, map[string]string{"parentId": "is required"}, nil
+	}
+
+	parent, cached := cache[payload.ParentId]
+	if !cached {
+		var err error
+		parent, err = c.findRegion(ctx, parentLevel, payload.ParentId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cache != nil {
+			cache[payload.ParentId] = parent
+		}
+	}
+	if parent == nil {
+		return nil, map[string]string{"parentId": fmt.Sprintf("%s not found", parentLevel)}, nil
+	}
+	if parent.Deactivated {
+		return nil, map[string]string{"parentId": fmt.Sprintf("%s is deactivated", parentLevel)}, nil
+	}
+
+	switch payload.Level {
+	case request.LevelCity:
+		region.ProvinceId, region.ProvinceName, region.ProvinceNames = parent.Id, parent.Name, parent.Names
+	case request.LevelDistrict:
+		region.CityId, region.CityName, region.CityNames = parent.Id, parent.Name, parent.Names
+		region.ProvinceId, region.ProvinceName, region.ProvinceNames = parent.ProvinceId, parent.ProvinceName, parent.ProvinceNames
+	case request.LevelSubdistrict:
+		region.DistrictId, region.DistrictName, region.DistrictNames = parent.Id, parent.Name, parent.Names
+		region.CityId, region.CityName, region.CityNames = parent.CityId, parent.CityName, parent.CityNames
+		region.ProvinceId, region.ProvinceName, region.ProvinceNames = parent.ProvinceId, parent.ProvinceName, parent.ProvinceNames
+	}
+	return &region, nil, nil
+}
+
+// saveRegion upserts the region, a renamed or moved region refreshes the names copied onto the levels below it
+func (c commandUsecase) saveRegion(ctx context.Context, region response.Region, existing *response.Region) error {
+	changed := existing != nil && !reflect.DeepEqual(*existing, region)
+	// The offline dataset sends the regions saved after the version a client holds
+	updatedAt := time.Now().UTC()
+
+	var resp helpers.Result
+	switch region.Level {
+	case request.LevelProvince:
+		province := provinceEntity(region, updatedAt)
+		resp = <-c.addressRepositoryCommand.UpsertOneProvince(ctx, province)
+		if resp.Error == nil && changed {
+			resp = <-c.addressRepositoryCommand.UpdateProvinceReferences(ctx, province)
+		}
+	case request.LevelCity:
+		city := cityEntity(region, updatedAt)
+		resp = <-c.addressRepositoryCommand.UpsertOneCity(ctx, city)
+		if resp.Error == nil && changed {
+			resp = <-c.addressRepositoryCommand.UpdateCityReferences(ctx, city)
+		}
+	case request.LevelDistrict:
+		district := districtEntity(region, updatedAt)
+		resp = <-c.addressRepositoryCommand.UpsertOneDistrict(ctx, district)
+		if resp.Error == nil && changed {
+			resp = <-c.addressRepositoryCommand.UpdateDistrictReferences(ctx, district)
+		}
+	case request.LevelSubdistrict:
+		resp = <-c.addressRepositoryCommand.UpsertOneSubdistrict(ctx, subdistrictEntity(region, updatedAt))
+	}
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error save region", fmt.Sprintf("%+v", resp.Error))
+		return resp.Error
+	}
+	return nil
+}
+
+// saveRegions upserts the regions of one level in a single write, then refreshes the names copied below
+// the renamed or moved ones. command is the repository of the batch the regions are saved in.
+func (c commandUsecase) saveRegions(ctx context.Context, command address.MongodbRepositoryCommand, level string, changes []regionChange) error {
+	updatedAt := time.Now().UTC()
+	var references []func() helpers.Result
+
+	var resp helpers.Result
+	switch level {
+	case request.LevelProvince:
+		provinces := make([]entity.Province, 0, len(changes))
+		for _, change := range changes {
+			province := provinceEntity(change.region, updatedAt)
+			provinces = append(provinces, province)
+			if change.existing != nil && !reflect.DeepEqual(*change.existing, change.region) {
+				references = append(references, func() helpers.Result {
+					return <-command.UpdateProvinceReferences(ctx, province)
+				})
+			}
+		}
+		resp = <-command.UpsertManyProvinces(ctx, provinces)
+	case request.LevelCity:
+		cities := make([]entity.City, 0, len(changes))
+		for _, change := range changes {
+			city := cityEntity(change.region, updatedAt)
+			cities = append(cities, city)
+			if change.existing != nil && !reflect.DeepEqual(*change.existing, change.region) {
+				references = append(references, func() helpers.Result {
+					return <-command.UpdateCityReferences(ctx, city)
+				})
+			}
+		}
+		resp = <-command.UpsertManyCities(ctx, cities)
+	case request.LevelDistrict:
+		districts := make([]entity.District, 0, len(changes))
+		for _, change := range changes {
+			district := districtEntity(change.region, updatedAt)
+			districts = append(districts, district)
+			if change.existing != nil && !reflect.DeepEqual(*change.existing, change.region) {
+				references = append(references, func() helpers.Result {
+					return <-command.UpdateDistrictReferences(ctx, district)
+				})
+			}
+		}
+		resp = <-command.UpsertManyDistricts(ctx, districts)
+	case request.LevelSubdistrict:
+		subdistricts := make([]entity.SubDistrict, 0, len(changes))
+		for _, change := range changes {
+			subdistricts = append(subdistricts, subdistrictEntity(change.region, updatedAt))
+		}
+		resp = <-command.UpsertManySubdistricts(ctx, subdistricts)
+	}
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error save regions", fmt.Sprintf("%+v", resp.Error))
+		return resp.Error
+	}
+	for _, updateReferences := range references {
+		if resp := updateReferences(); resp.Error != nil {
+			c.logger.Error(ctx, "Error save region references", fmt.Sprintf("%+v", resp.Error))
+			return resp.Error
+		}
+	}
+	return nil
+}
+
+func provinceEntity(region response.Region, updatedAt time.Time) entity.Province {
+	return entity.Province{
+		Id:          region.Id,
+		Name:        region.Name,
+		Names:       region.Names,
+		Deactivated: region.Deactivated,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func cityEntity(region response.Region, updatedAt time.Time) entity.City {
+	return entity.City{
+		Id:            region.Id,
+		Name:          region.Name,
+		Names:         region.Names,
+		ProvinceId:    region.ProvinceId,
+		ProvinceName:  region.ProvinceName,
+		ProvinceNames: region.ProvinceNames,
+		Deactivated:   region.Deactivated,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func districtEntity(region response.Region, updatedAt time.Time) entity.District {
+	return entity.District{
+		Id:            region.Id,
+		Name:          region.Name,
+		Names:         region.Names,
+		CityId:        region.CityId,
+		CityName:      region.CityName,
+		CityNames:     region.CityNames,
+		ProvinceId:    region.ProvinceId,
+		ProvinceName:  region.ProvinceName,
+		ProvinceNames: region.ProvinceNames,
+		Deactivated:   region.Deactivated,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func subdistrictEntity(region response.Region, updatedAt time.Time) entity.SubDistrict {
+	return entity.SubDistrict{
+		Id:            region.Id,
+		Name:          region.Name,
+		Names:         region.Names,
+		DistrictId:    region.DistrictId,
+		DistrictName:  region.DistrictName,
+		DistrictNames: region.DistrictNames,
+		CityId:        region.CityId,
+		CityName:      region.CityName,
+		CityNames:     region.CityNames,
+		ProvinceId:    region.ProvinceId,
+		ProvinceName:  region.ProvinceName,
+		ProvinceNames: region.ProvinceNames,
+		PostalCode:    region.PostalCode,
+		Centroid:      geoPoint(region.Centroid),
+		Deactivated:   region.Deactivated,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+// findCountry is nil when the country does not exist, deactivated countries are returned
+func (c commandUsecase) findCountry(ctx context.Context, id int) (*entity.Country, error) {
+	resp := <-c.addressRepositoryQuery.FindOneCountry(ctx, id)
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error query country", fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+	country, ok := resp.Data.(*entity.Country)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data country")
+	}
+	return country, nil
+}
+
+func (c commandUsecase) saveCountry(ctx context.Context, country entity.Country) error {
+	resp := <-c.addressRepositoryCommand.UpsertOneCountry(ctx, country)
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error save country", fmt.Sprintf("%+v", resp.Error))
+		return resp.Error
+	}
+	return nil
+}
+
+// loadContinents maps continent codes to the continents
+func (c commandUsecase) loadContinents(ctx context.Context) (map[string]entity.Continent, error) {
+	resp := <-c.addressRepositoryQuery.FindContinent(ctx)
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error query continent", fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	continents := map[string]entity.Continent{}
+	if resp.Data == nil {
+		return continents, nil
+	}
+	data, ok := resp.Data.(*[]entity.Continent)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data continent")
+	}
+	for _, continent := range *data {
+		continents[continent.Code] = continent
+	}
+	return continents, nil
+}
+
+func buildCountry(payload request.SaveCountry, continents map[string]entity.Continent) (entity.Country, map[string]string) {
+	continent, ok := continents[payload.ContinentCode]
+	if !ok {
+		return entity.Country{}, map[string]string{"continentCode": "not found"}
+	}
+	var bounds *entity.Bounds
+	if payload.Bounds != nil {
+		bounds = &entity.Bounds{
+			MinLatitude:  payload.Bounds.MinLatitude,
+			MinLongitude: payload.Bounds.MinLongitude,
+			MaxLatitude:  payload.Bounds.MaxLatitude,
+			MaxLongitude: payload.Bounds.MaxLongitude,
+		}
+	}
+	return entity.Country{
+		Id:             payload.Id,
+		Code:           strings.ToUpper(payload.Code),
+		Name:           payload.Name,
+		Names:          normalizeNames(payload.Names),
+		Iso3:           strings.ToUpper(payload.Iso3),
+		Number:         payload.Number,
+		ContinentCode:  payload.ContinentCode,
+		ContinentName:  continent.Name,
+		ContinentNames: continent.Names,
+		DisplayOrder:   payload.DisplayOrder,
+		FullName:       payload.FullName,
+		Bounds:         bounds,
+	}, nil
+}
+
+// rowFields lists the failing validation tags of an import row by json key
+func rowFields(row interface{}) map[string]string {
+	fields := map[string]string{}
+	validationErrors, ok := rowValidator.Struct(row).(validator.ValidationErrors)
+	if !ok {
+		return fields
+	}
+	for _, fieldError := range validationErrors {
+		// A localized name is reported under its column, names[en] becomes names.en
+		field := strings.TrimSuffix(strings.Replace(fieldError.Field(), "[", ".", 1), "]")
+		if strings.HasPrefix(fieldError.Tag(), "required") {
+			fields[field] = "is required"
+			continue
+		}
+		fields[field] = strings.TrimSpace(fmt.Sprintf("must satisfy %s %s", fieldError.Tag(), fieldError.Param()))
+	}
+	return fields
+}
+
+// rowNames collects the localized names of an import row from its names.<language> columns, empty cells are skipped
+func rowNames(row map[string]string) map[string]string {
+	var names map[string]string
+	for key, value := range row {
+		tag, ok := strings.CutPrefix(key, "names.")
+		if !ok || value == "" {
+			continue
+		}
+		if names == nil {
+			names = map[string]string{}
+		}
+		names[tag] = value
+	}
+	return names
+}
+
+// parseImportRows reads a csv file with a header line or a json array of objects into rows keyed by column
+func parseImportRows(format string, content []byte) ([]map[string]string, error) {
+	if format == "json" {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var records []map[string]interface{}
+		if err := decoder.Decode(&records); err != nil {
+			return nil, err
+		}
+		rows := make([]map[string]string, 0, len(records))
+		for i, record := range records {
+			row := make(map[string]string, len(record))
+			for key, value := range record {
+				switch v := value.(type) {
+				case nil:
+				case string:
+					row[key] = strings.TrimSpace(v)
+				case json.Number:
+					row[key] = v.String()
+				default:
+					return nil, fmt.Errorf("row %d: %s must be a string or a number", i+1, key)
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, key := range header {
+			row[key] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
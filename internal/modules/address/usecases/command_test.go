@@ -0,0 +1,513 @@
+package usecases_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
+	uc "user-service/internal/modules/address/usecases"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
+	mockcertAddress "user-service/mocks/modules/address"
+	mocklog "user-service/mocks/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommandUsecaseTestSuite struct {
+	suite.Suite
+	mockAddressRepositoryCommand *mockcertAddress.MongodbRepositoryCommand
+	mockAddressRepositoryQuery   *mockcertAddress.MongodbRepositoryQuery
+	mockLogger                   *mocklog.Logger
+	usecase                      address.UsecaseCommand
+	ctx                          context.Context
+}
+
+func (suite *CommandUsecaseTestSuite) SetupTest() {
+	suite.mockAddressRepositoryCommand = &mockcertAddress.MongodbRepositoryCommand{}
+	suite.mockAddressRepositoryQuery = &mockcertAddress.MongodbRepositoryQuery{}
+	suite.mockLogger = &mocklog.Logger{}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	suite.ctx = context.Background()
+	suite.usecase = uc.NewCommandUsecase(
+		suite.mockAddressRepositoryCommand,
+		suite.mockAddressRepositoryQuery,
+		suite.mockLogger,
+	)
+}
+
+func TestCommandUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandUsecaseTestSuite))
+}
+
+// savedRegion matches a region as the usecase saves it, UpdatedAt is the save time so it is only checked to be set
+func savedRegion[T entity.Province | entity.City | entity.District | entity.SubDistrict](expected T) interface{} {
+	return mock.MatchedBy(func(actual T) bool {
+		updatedAt := reflect.ValueOf(&actual).Elem().FieldByName("UpdatedAt")
+		if updatedAt.Interface().(time.Time).IsZero() {
+			return false
+		}
+		updatedAt.Set(reflect.Zero(updatedAt.Type()))
+		return reflect.DeepEqual(expected, actual)
+	})
+}
+
+func mockProvince() helpers.Result {
+	return helpers.Result{
+		Data: &entity.Province{Id: "96", Name: "Papua Barat Daya"},
+	}
+}
+
+func mockCity() helpers.Result {
+	return helpers.Result{
+		Data: &entity.City{Id: "9171", Name: "Kota Sorong", ProvinceId: "91", ProvinceName: "Papua Barat"},
+	}
+}
+
+func mockContinents() helpers.Result {
+	return helpers.Result{
+		Data: &[]entity.Continent{{Code: "AS", Name: "Asia"}},
+	}
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionSuccess() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9671", Name: "Kota Sorong", ParentId: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9671").Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	suite.mockAddressRepositoryCommand.On("UpsertOneCity", mock.Anything, savedRegion(entity.City{
+		Id:           "9671",
+		Name:         "Kota Sorong",
+		ProvinceId:   "96",
+		ProvinceName: "Papua Barat Daya",
+	})).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Papua Barat Daya", result.ProvinceName)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpdateCityReferences", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionConflict() {
+	// Arrange
+	payload := request.Region{Level: request.LevelProvince, Id: "96", Name: "Papua Barat Daya"}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+
+	// Act
+	_, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.WithFields(errors.Conflict("Region already exists"), map[string]string{"id": "already exists"}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionParentDeactivated() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9671", Name: "Kota Sorong", ParentId: "96"}
+	province := mockProvince()
+	province.Data.(*entity.Province).Deactivated = true
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9671").Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(province))
+
+	// Act
+	_, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Region is not valid", map[string]string{"parentId": "province is deactivated"}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionSubdistrictPostalCode() {
+	// Arrange
+	payload := request.Region{Level: request.LevelSubdistrict, Id: "9171011001", Name: "Klademak", ParentId: "9171011", PostalCode: "98414"}
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "9171011001").Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryQuery.On("FindOneDistrict", mock.Anything, "9171011").Return(mockChannel(helpers.Result{
+		Data: &entity.District{Id: "9171011", Name: "Sorong Timur", CityId: "9171", CityName: "Kota Sorong", ProvinceId: "96", ProvinceName: "Papua Barat Daya"},
+	}))
+	suite.mockAddressRepositoryCommand.On("UpsertOneSubdistrict", mock.Anything, savedRegion(entity.SubDistrict{
+		Id:           "9171011001",
+		Name:         "Klademak",
+		DistrictId:   "9171011",
+		DistrictName: "Sorong Timur",
+		CityId:       "9171",
+		CityName:     "Kota Sorong",
+		ProvinceId:   "96",
+		ProvinceName: "Papua Barat Daya",
+		PostalCode:   "98414",
+	})).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "98414", result.PostalCode)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionPostalCodeAboveSubdistrict() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9671", Name: "Kota Sorong", ParentId: "96", PostalCode: "98414"}
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9671").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	_, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Region is not valid", map[string]string{"postalCode": "is only set on subdistricts"}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateRegionKeepsCentroid() {
+	// Arrange
+	payload := request.Region{Level: request.LevelSubdistrict, Id: "9171011001", Name: "Klademak", ParentId: "9171011"}
+	district := &entity.District{Id: "9171011", Name: "Sorong Timur", CityId: "9171", CityName: "Kota Sorong", ProvinceId: "96", ProvinceName: "Papua Barat Daya"}
+	centroid := &entity.GeoPoint{Type: "Point", Coordinates: []float64{131.28, -0.88}}
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "9171011001").Return(mockChannel(helpers.Result{
+		Data: &entity.SubDistrict{Id: "9171011001", Name: "Klademak 1", DistrictId: "9171011", Centroid: centroid},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneDistrict", mock.Anything, "9171011").Return(mockChannel(helpers.Result{Data: district}))
+	suite.mockAddressRepositoryCommand.On("UpsertOneSubdistrict", mock.Anything, mock.MatchedBy(func(subdistrict entity.SubDistrict) bool {
+		return subdistrict.Name == "Klademak" && subdistrict.Centroid != nil && subdistrict.Centroid.Coordinates[0] == 131.28
+	})).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.UpdateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &response.Location{Latitude: -0.88, Longitude: 131.28}, result.Centroid)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateRegionLocalizedNames() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9171", Name: "Kota Sorong", ParentId: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(helpers.Result{
+		Data: &entity.City{Id: "9171", Name: "Kota Sorong", Names: map[string]string{"en": "Sorong City"}, ProvinceId: "96", ProvinceName: "Papua Barat Daya"},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(helpers.Result{
+		Data: &entity.Province{Id: "96", Name: "Papua Barat Daya", Names: map[string]string{"en": "Southwest Papua"}},
+	}))
+	saved := entity.City{
+		Id:            "9171",
+		Name:          "Kota Sorong",
+		Names:         map[string]string{"en": "Sorong City"},
+		ProvinceId:    "96",
+		ProvinceName:  "Papua Barat Daya",
+		ProvinceNames: map[string]string{"en": "Southwest Papua"},
+	}
+	suite.mockAddressRepositoryCommand.On("UpsertOneCity", mock.Anything, savedRegion(saved)).Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryCommand.On("UpdateCityReferences", mock.Anything, savedRegion(saved)).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.UpdateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]string{"en": "Sorong City"}, result.Names)
+	suite.mockAddressRepositoryCommand.AssertCalled(suite.T(), "UpdateCityReferences", mock.Anything, savedRegion(saved))
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateRegionCentroidAboveSubdistrict() {
+	// Arrange
+	latitude, longitude := -0.88, 131.28
+	payload := request.Region{Level: request.LevelDistrict, Id: "9171011", Name: "Sorong Timur", ParentId: "9171", Latitude: &latitude, Longitude: &longitude}
+	suite.mockAddressRepositoryQuery.On("FindOneDistrict", mock.Anything, "9171011").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	_, err := suite.usecase.CreateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Region is not valid", map[string]string{"latitude": "is only set on subdistricts"}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateRegionMovesChildren() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9171", Name: "Kota Sorong", ParentId: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(mockCity()))
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	moved := entity.City{Id: "9171", Name: "Kota Sorong", ProvinceId: "96", ProvinceName: "Papua Barat Daya"}
+	suite.mockAddressRepositoryCommand.On("UpsertOneCity", mock.Anything, savedRegion(moved)).Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryCommand.On("UpdateCityReferences", mock.Anything, savedRegion(moved)).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.UpdateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "96", result.ProvinceId)
+	suite.mockAddressRepositoryCommand.AssertCalled(suite.T(), "UpdateCityReferences", mock.Anything, savedRegion(moved))
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateRegionNotFound() {
+	// Arrange
+	payload := request.Region{Level: request.LevelCity, Id: "9999", Name: "Kota", ParentId: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9999").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	_, err := suite.usecase.UpdateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.NotFound("Region not found"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeactivateRegionWithChildren() {
+	// Arrange
+	payload := request.RegionId{Level: request.LevelProvince, Id: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	suite.mockAddressRepositoryQuery.On("CountActiveChildren", mock.Anything, request.LevelProvince, "96").Return(mockChannel(helpers.Result{Count: 6}))
+
+	// Act
+	err := suite.usecase.DeactivateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.UnprocessableEntity("Region still has active children, move or deactivate them first"), err)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneProvince", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeactivateRegionSuccess() {
+	// Arrange
+	payload := request.RegionId{Level: request.LevelProvince, Id: "96"}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	suite.mockAddressRepositoryQuery.On("CountActiveChildren", mock.Anything, request.LevelProvince, "96").Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryCommand.On("UpsertOneProvince", mock.Anything, savedRegion(entity.Province{Id: "96", Name: "Papua Barat Daya", Deactivated: true})).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	err := suite.usecase.DeactivateRegion(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpdateProvinceReferences", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateCountryContinentNotFound() {
+	// Arrange
+	payload := request.SaveCountry{Id: 250, Code: "tl", Name: "Timor-Leste", ContinentCode: "XX"}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 250).Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryQuery.On("FindContinent", mock.Anything).Return(mockChannel(mockContinents()))
+
+	// Act
+	_, err := suite.usecase.CreateCountry(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Country is not valid", map[string]string{"continentCode": "not found"}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateCountryKeepsDeactivated() {
+	// Arrange
+	payload := request.SaveCountry{Id: 250, Code: "tl", Name: "Timor-Leste", ContinentCode: "AS"}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 250).Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 250, Code: "TP", Deactivated: true},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindContinent", mock.Anything).Return(mockChannel(mockContinents()))
+	suite.mockAddressRepositoryCommand.On("UpsertOneCountry", mock.Anything, entity.Country{
+		Id:            250,
+		Code:          "TL",
+		Name:          "Timor-Leste",
+		ContinentCode: "AS",
+		ContinentName: "Asia",
+		Deactivated:   true,
+	}).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.UpdateCountry(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TL", result.Code)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsDryRun() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCity,
+		Format:  "csv",
+		DryRun:  true,
+		Content: []byte("id,name,parentId\n9171,Kota Sorong,96\n9672,Kabupaten Sorong,96\n"),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince())).Once()
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(mockCity()))
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9672").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, result.Total)
+	assert.Equal(suite.T(), 1, result.Created)
+	assert.Equal(suite.T(), 1, result.Updated)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneCity", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsApply() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCity,
+		Format:  "json",
+		Content: []byte(`[{"id":"9171","name":"Kota Sorong","parentId":96}]`),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(mockCity()))
+	suite.mockAddressRepositoryCommand.On("Batch", mock.Anything, mock.Anything).Return(suite.runBatch)
+	suite.mockAddressRepositoryCommand.On("UpsertManyCities", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryCommand.On("UpdateCityReferences", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, result.Updated)
+	suite.mockAddressRepositoryCommand.AssertCalled(suite.T(), "UpdateCityReferences", mock.Anything, mock.Anything)
+}
+
+// runBatch runs the writes of a batch on the mocked repository
+func (suite *CommandUsecaseTestSuite) runBatch(ctx context.Context, run func(command address.MongodbRepositoryCommand) error) error {
+	return run(suite.mockAddressRepositoryCommand)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsApplyInOneWrite() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCity,
+		Format:  "json",
+		Content: []byte(`[{"id":"9171","name":"Kota Sorong","parentId":96},{"id":"9672","name":"Kabupaten Sorong","parentId":96}]`),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince())).Once()
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(mockCity()))
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9672").Return(mockChannel(helpers.Result{}))
+	suite.mockAddressRepositoryCommand.On("Batch", mock.Anything, mock.Anything).Return(suite.runBatch)
+	suite.mockAddressRepositoryCommand.On("UpsertManyCities", mock.Anything, mock.MatchedBy(func(cities []entity.City) bool {
+		return len(cities) == 2 && cities[0].Id == "9171" && cities[1].Id == "9672" && cities[1].ProvinceId == "96"
+	})).Return(mockChannel(helpers.Result{Count: 2}))
+	suite.mockAddressRepositoryCommand.On("UpdateCityReferences", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, result.Created)
+	assert.Equal(suite.T(), 1, result.Updated)
+	suite.mockAddressRepositoryCommand.AssertNumberOfCalls(suite.T(), "Batch", 1)
+	suite.mockAddressRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertManyCities", 1)
+	suite.mockAddressRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpdateCityReferences", 1)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneCity", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsApplyErrUpsert() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCity,
+		Format:  "json",
+		Content: []byte(`[{"id":"9171","name":"Kota Sorong","parentId":96}]`),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(mockProvince()))
+	suite.mockAddressRepositoryQuery.On("FindOneCity", mock.Anything, "9171").Return(mockChannel(mockCity()))
+	suite.mockAddressRepositoryCommand.On("Batch", mock.Anything, mock.Anything).Return(suite.runBatch)
+	suite.mockAddressRepositoryCommand.On("UpsertManyCities", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Error: errors.InternalServerError("Error mongodb transaction")}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpdateCityReferences", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsCentroidErrors() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelSubdistrict,
+		Format:  "csv",
+		Content: []byte("id,name,parentId,latitude,longitude\n9171011001,Klademak,9171011,north,131.28\n9171011002,Klasaman,9171011,-0.88,\n9171011003,Klawasi,9171011,NaN,Inf\n"),
+	}
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.WithFields(errors.UnprocessableEntity("Import has invalid rows"), map[string]string{
+		"rows[1].latitude":  "must be a number",
+		"rows[2].longitude": "is required",
+		"rows[3].latitude":  "must be a number",
+		"rows[3].longitude": "must be a number",
+	}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsLocalizedNames() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelProvince,
+		Format:  "csv",
+		Content: []byte("id,name,names.en,names.EN-gb,names.zz-!\n96,Papua Barat Daya,Southwest Papua,South West Papua,\n97,Papua Pegunungan,Highland Papua,,x\n"),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "96").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.WithFields(errors.UnprocessableEntity("Import has invalid rows"), map[string]string{
+		"rows[2].names.zz-!": "must satisfy bcp47_language_tag",
+	}), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsRowErrors() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCity,
+		Format:  "csv",
+		Content: []byte("id,name,parentId\n9671,Kota Sorong,99\n9671,,96\n"),
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneProvince", mock.Anything, "99").Return(mockChannel(helpers.Result{}))
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.WithFields(errors.UnprocessableEntity("Import has invalid rows"), map[string]string{
+		"rows[1].parentId": "province not found",
+		"rows[2].id":       "is duplicated, first seen in row 1",
+		"rows[2].name":     "is required",
+	}), err)
+	suite.mockAddressRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneCity", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportCountriesRowErrors() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelCountry,
+		Format:  "csv",
+		Content: []byte("id,code,name,continentCode\nabc,ID,Indonesia,AS\n"),
+	}
+	suite.mockAddressRepositoryQuery.On("FindContinent", mock.Anything).Return(mockChannel(mockContinents()))
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), map[string]string{"rows[1].id": "must be an integer"}, err.(*errors.ErrorString).Fields())
+}
+
+func (suite *CommandUsecaseTestSuite) TestImportRegionsErrParse() {
+	// Arrange
+	payload := request.ImportRegions{
+		Level:   request.LevelProvince,
+		Format:  "json",
+		Content: []byte(`{"id":"96"}`),
+	}
+
+	// Act
+	_, err := suite.usecase.ImportRegions(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), 400, err.(*errors.ErrorString).Code())
+}
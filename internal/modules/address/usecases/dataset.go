@@ -0,0 +1,265 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
+	"user-service/internal/pkg/errors"
+
+	"go.elastic.co/apm"
+)
+
+// datasetPageSize is the number of regions loaded per query while building the dataset
+const datasetPageSize = 5000
+
+// deltaOverlap widens a delta back in time, a region saved by another instance while the dataset was loaded
+// can carry a time before the version. Clients upsert the regions they get twice.
+const deltaOverlap = time.Minute
+
+// datasetLevels are the levels of the dataset from the top, a parent always comes before its children
+var datasetLevels = [...]string{request.LevelProvince, request.LevelCity, request.LevelDistrict, request.LevelSubdistrict}
+
+type datasetRecord struct {
+	region    response.DatasetRegion
+	removed   bool
+	updatedAt time.Time
+}
+
+// datasetSnapshot is an immutable copy of every region per level in id order, deactivated regions included
+// so deltas can tell them
+type datasetSnapshot struct {
+	records [len(datasetLevels)][]datasetRecord
+	full    *response.Dataset
+}
+
+// datasetIndex holds the latest snapshot, it is shared by every copy of the query usecase
+type datasetIndex struct {
+	mu       sync.RWMutex
+	snapshot *datasetSnapshot
+	buildMu  sync.Mutex
+}
+
+func (i *datasetIndex) get() *datasetSnapshot {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.snapshot
+}
+
+func (i *datasetIndex) set(snapshot *datasetSnapshot) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.snapshot = snapshot
+}
+
+// ExportDataset is the whole dataset, or the changes after the version in Since. A client ahead of the
+// dataset, after a restore, gets the whole dataset again.
+func (q queryUsecase) ExportDataset(origCtx context.Context, payload request.Dataset) (*response.Dataset, error) {
+	domain := "addressUsecase-ExportDataset"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	snapshot := q.datasetIndex.get()
+	if snapshot == nil {
+		// The dataset is built at startup, an export arriving first builds it once for everyone
+		q.datasetIndex.buildMu.Lock()
+		snapshot = q.datasetIndex.get()
+		if snapshot == nil {
+			if err := q.BuildDataset(ctx); err != nil {
+				q.datasetIndex.buildMu.Unlock()
+				return nil, err
+			}
+			snapshot = q.datasetIndex.get()
+		}
+		q.datasetIndex.buildMu.Unlock()
+	}
+
+	if payload.Since == "" {
+		return snapshot.full, nil
+	}
+	since, err := strconv.ParseInt(payload.Since, 10, 64)
+	if err != nil {
+		return nil, errors.WithFields(errors.BadRequest("since is not a dataset version"), map[string]string{"since": "must be a dataset version"})
+	}
+	version, _ := strconv.ParseInt(snapshot.full.Version, 10, 64)
+	if since > version {
+		return snapshot.full, nil
+	}
+	return snapshot.delta(payload.Since, since, since == version), nil
+}
+
+// BuildDataset loads every region and swaps in a new snapshot, exports keep using the old one meanwhile
+func (q queryUsecase) BuildDataset(origCtx context.Context) error {
+	domain := "addressUsecase-BuildDataset"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	snapshot := &datasetSnapshot{}
+	var version time.Time
+	for level, name := range datasetLevels {
+		for cursor := ""; ; {
+			resp := <-q.addressRepositoryQuery.FindAllRegions(ctx, name, cursor, datasetPageSize)
+			if resp.Error != nil {
+				q.logger.Error(ctx, "Error load dataset", fmt.Sprintf("%+v", resp.Error))
+				return resp.Error
+			}
+			if resp.Data == nil {
+				break
+			}
+			records, err := datasetRecords(resp.Data)
+			if err != nil {
+				return err
+			}
+			for _, record := range records {
+				if record.updatedAt.After(version) {
+					version = record.updatedAt
+				}
+			}
+			snapshot.records[level] = append(snapshot.records[level], records...)
+			if cursor, _ = resp.MetaData.(string); cursor == "" {
+				break
+			}
+		}
+	}
+
+	full, err := snapshot.fullDataset(version)
+	if err != nil {
+		return err
+	}
+	snapshot.full = full
+	q.datasetIndex.set(snapshot)
+	q.logger.Info(ctx, "Dataset built", fmt.Sprintf("version %s, %d subdistricts", full.Version, len(full.Subdistricts)))
+	return nil
+}
+
+// fullDataset holds the active regions, its hash covers the regions only so it changes with the content
+func (s *datasetSnapshot) fullDataset(version time.Time) (*response.Dataset, error) {
+	var levels [len(datasetLevels)][]response.DatasetRegion
+	for level, records := range s.records {
+		levels[level] = make([]response.DatasetRegion, 0, len(records))
+		for _, record := range records {
+			if !record.removed {
+				levels[level] = append(levels[level], record.region)
+			}
+		}
+	}
+	encoded, err := json.Marshal(levels)
+	if err != nil {
+		return nil, errors.InternalServerError("cannot encode dataset")
+	}
+	sum := sha256.Sum256(encoded)
+
+	dataset := &response.Dataset{
+		Version:      "0",
+		Hash:         hex.EncodeToString(sum[:]),
+		Full:         true,
+		Provinces:    levels[0],
+		Cities:       levels[1],
+		Districts:    levels[2],
+		Subdistricts: levels[3],
+	}
+	if !version.IsZero() {
+		dataset.Version = strconv.FormatInt(version.UnixMilli(), 10)
+	}
+	return dataset, nil
+}
+
+// delta holds the regions saved after since, a client already on the version gets an empty delta
+func (s *datasetSnapshot) delta(sinceVersion string, since int64, current bool) *response.Dataset {
+	var levels [len(datasetLevels)][]response.DatasetRegion
+	var removed [len(datasetLevels)][]string
+	after := time.UnixMilli(since).Add(-deltaOverlap)
+	for level, records := range s.records {
+		levels[level] = make([]response.DatasetRegion, 0)
+		removed[level] = make([]string, 0)
+		if current {
+			continue
+		}
+		for _, record := range records {
+			switch {
+			case !record.updatedAt.After(after):
+			case record.removed:
+				removed[level] = append(removed[level], record.region.Id)
+			default:
+				levels[level] = append(levels[level], record.region)
+			}
+		}
+	}
+
+	return &response.Dataset{
+		Version:      s.full.Version,
+		Hash:         s.full.Hash,
+		Since:        sinceVersion,
+		Provinces:    levels[0],
+		Cities:       levels[1],
+		Districts:    levels[2],
+		Subdistricts: levels[3],
+		Removed: &response.DatasetRemoved{
+			Provinces:    removed[0],
+			Cities:       removed[1],
+			Districts:    removed[2],
+			Subdistricts: removed[3],
+		},
+	}
+}
+
+// datasetRecords keeps the fields an offline client needs from a page of regions of one level
+func datasetRecords(data interface{}) ([]datasetRecord, error) {
+	records := make([]datasetRecord, 0)
+	switch regions := data.(type) {
+	case *[]entity.Province:
+		for _, value := range *regions {
+			records = append(records, datasetRecord{
+				region:    response.DatasetRegion{Id: value.Id, Name: value.Name, Names: value.Names},
+				removed:   value.Deactivated,
+				updatedAt: value.UpdatedAt,
+			})
+		}
+	case *[]entity.City:
+		for _, value := range *regions {
+			records = append(records, datasetRecord{
+				region:    response.DatasetRegion{Id: value.Id, ParentId: value.ProvinceId, Name: value.Name, Names: value.Names},
+				removed:   value.Deactivated,
+				updatedAt: value.UpdatedAt,
+			})
+		}
+	case *[]entity.District:
+		for _, value := range *regions {
+			records = append(records, datasetRecord{
+				region:    response.DatasetRegion{Id: value.Id, ParentId: value.CityId, Name: value.Name, Names: value.Names},
+				removed:   value.Deactivated,
+				updatedAt: value.UpdatedAt,
+			})
+		}
+	case *[]entity.SubDistrict:
+		for _, value := range *regions {
+			records = append(records, datasetRecord{
+				region: response.DatasetRegion{
+					Id:         value.Id,
+					ParentId:   value.DistrictId,
+					Name:       value.Name,
+					Names:      value.Names,
+					PostalCode: value.PostalCode,
+				},
+				removed:   value.Deactivated,
+				updatedAt: value.UpdatedAt,
+			})
+		}
+	default:
+		return nil, errors.InternalServerError("cannot parsing data region")
+	}
+	return records, nil
+}
@@ -0,0 +1,155 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	uc "user-service/internal/modules/address/usecases"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
+	mockcertAddress "user-service/mocks/modules/address"
+	mocklog "user-service/mocks/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DatasetUsecaseTestSuite struct {
+	suite.Suite
+	mockAddressRepositoryQuery *mockcertAddress.MongodbRepositoryQuery
+	mockLogger                 *mocklog.Logger
+	usecase                    address.UsecaseQuery
+	ctx                        context.Context
+}
+
+func (suite *DatasetUsecaseTestSuite) SetupTest() {
+	suite.mockAddressRepositoryQuery = &mockcertAddress.MongodbRepositoryQuery{}
+	suite.mockLogger = &mocklog.Logger{}
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	suite.ctx = context.Background()
+	suite.usecase = uc.NewQueryUsecase(
+		suite.mockAddressRepositoryQuery,
+		suite.mockLogger,
+	)
+}
+
+func TestDatasetUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DatasetUsecaseTestSuite))
+}
+
+var (
+	seededAt = time.UnixMilli(1700000000000).UTC()
+	editedAt = time.UnixMilli(1760000000000).UTC()
+)
+
+// mockRegions seeds one region per level, the city was deactivated and the subdistrict renamed after seeding
+func (suite *DatasetUsecaseTestSuite) mockRegions() {
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelProvince, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.Province{{Id: "96", Name: "Papua Barat Daya", UpdatedAt: seededAt}},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelCity, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data:     &[]entity.City{{Id: "9171", Name: "Kota Sorong", ProvinceId: "96", UpdatedAt: seededAt}},
+		MetaData: "next",
+	}))
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelCity, "next", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.City{{Id: "9172", Name: "Kota Lama", ProvinceId: "96", Deactivated: true, UpdatedAt: editedAt}},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelDistrict, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.District{{Id: "9171011", Name: "Sorong Timur", CityId: "9171", UpdatedAt: seededAt}},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelSubdistrict, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.SubDistrict{{Id: "9171011001", Name: "Klademak", DistrictId: "9171011", PostalCode: "98414", UpdatedAt: editedAt}},
+	}))
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetFull() {
+	// Arrange
+	suite.mockRegions()
+
+	// Act
+	result, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Full)
+	assert.Equal(suite.T(), "1760000000000", result.Version)
+	assert.Len(suite.T(), result.Hash, 64)
+	assert.Len(suite.T(), result.Cities, 1)
+	assert.Equal(suite.T(), "96", result.Cities[0].ParentId)
+	assert.Equal(suite.T(), "98414", result.Subdistricts[0].PostalCode)
+	assert.Nil(suite.T(), result.Removed)
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetDelta() {
+	// Arrange
+	suite.mockRegions()
+
+	// Act
+	result, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{Since: "1750000000000"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Full)
+	assert.Equal(suite.T(), "1750000000000", result.Since)
+	assert.Empty(suite.T(), result.Provinces)
+	assert.Empty(suite.T(), result.Cities)
+	assert.Equal(suite.T(), []string{"9172"}, result.Removed.Cities)
+	assert.Len(suite.T(), result.Subdistricts, 1)
+	assert.Equal(suite.T(), "Klademak", result.Subdistricts[0].Name)
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetCurrent() {
+	// Arrange
+	suite.mockRegions()
+
+	// Act
+	result, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{Since: "1760000000000"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Full)
+	assert.Empty(suite.T(), result.Subdistricts)
+	assert.Empty(suite.T(), result.Removed.Cities)
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetSinceAhead() {
+	// Arrange
+	suite.mockRegions()
+
+	// Act
+	result, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{Since: "1790000000000"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Full)
+	assert.Len(suite.T(), result.Provinces, 1)
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetSinceNotVersion() {
+	// Arrange
+	suite.mockRegions()
+
+	// Act
+	_, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{Since: "99999999999999999999"})
+
+	// Assert
+	assert.Equal(suite.T(), errors.WithFields(errors.BadRequest("since is not a dataset version"), map[string]string{"since": "must be a dataset version"}), err)
+}
+
+func (suite *DatasetUsecaseTestSuite) TestExportDatasetErr() {
+	// Arrange
+	suite.mockAddressRepositoryQuery.On("FindAllRegions", mock.Anything, request.LevelProvince, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Error: errors.InternalServerError("error"),
+	}))
+
+	// Act
+	_, err := suite.usecase.ExportDataset(suite.ctx, request.Dataset{})
+
+	// Assert
+	assert.Equal(suite.T(), errors.InternalServerError("error"), err)
+}
@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
+	"user-service/internal/pkg/errors"
+
+	"go.elastic.co/apm"
+)
+
+// reverseGeocodeMaxDistance is how far in meters a position may be from a subdistrict centroid to resolve to it
+const reverseGeocodeMaxDistance = 25000
+
+func (q queryUsecase) ReverseGeocode(origCtx context.Context, payload request.ReverseGeocode) (*response.ReverseGeocode, error) {
+	domain := "addressUsecase-ReverseGeocode"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-q.addressRepositoryQuery.FindNearestSubdistrict(ctx, *payload.Latitude, *payload.Longitude, reverseGeocodeMaxDistance)
+	if resp.Error != nil {
+		msg := "Error query nearest subdistrict"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, errors.NotFound("no subdistrict near the position")
+	}
+	nearest, ok := resp.Data.(*[]entity.SubDistrictDistance)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if len(*nearest) == 0 {
+		msg := "No subdistrict near the position"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%v,%v", *payload.Latitude, *payload.Longitude))
+		return nil, errors.NotFound("no subdistrict near the position")
+	}
+
+	value := (*nearest)[0]
+	return &response.ReverseGeocode{
+		SubDistrict: subDistrictResponse(value.SubDistrict, payload.Languages),
+		Distance:    value.Distance,
+	}, nil
+}
+
+// parseLocation reads the optional coordinates of an address, both or neither must be given
+func parseLocation(latitude string, longitude string) (*response.Location, map[string]string) {
+	if latitude == "" && longitude == "" {
+		return nil, nil
+	}
+	fields := map[string]string{}
+	lat, err := strconv.ParseFloat(latitude, 64)
+	switch {
+	case latitude == "":
+		fields["latitude"] = "is required with longitude"
+	case err != nil || math.IsNaN(lat) || math.IsInf(lat, 0):
+		fields["latitude"] = "must be a number"
+	case lat < -90 || lat > 90:
+		fields["latitude"] = "must be between -90 and 90"
+	}
+	lng, err := strconv.ParseFloat(longitude, 64)
+	switch {
+	case longitude == "":
+		fields["longitude"] = "is required with latitude"
+	case err != nil || math.IsNaN(lng) || math.IsInf(lng, 0):
+		fields["longitude"] = "must be a number"
+	case lng < -180 || lng > 180:
+		fields["longitude"] = "must be between -180 and 180"
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+	return &response.Location{Latitude: lat, Longitude: lng}, nil
+}
+
+// boundsContain is true for a country without bounds on record
+func boundsContain(bounds *entity.Bounds, location response.Location) bool {
+	if bounds == nil {
+		return true
+	}
+	if location.Latitude < bounds.MinLatitude || location.Latitude > bounds.MaxLatitude {
+		return false
+	}
+	if bounds.MinLongitude <= bounds.MaxLongitude {
+		return location.Longitude >= bounds.MinLongitude && location.Longitude <= bounds.MaxLongitude
+	}
+	// The box crosses the antimeridian
+	return location.Longitude >= bounds.MinLongitude || location.Longitude <= bounds.MaxLongitude
+}
+
+func geoPoint(location *response.Location) *entity.GeoPoint {
+	if location == nil {
+		return nil
+	}
+	return &entity.GeoPoint{
+		Type:        "Point",
+		Coordinates: []float64{location.Longitude, location.Latitude},
+	}
+}
+
+func locationOf(point *entity.GeoPoint) *response.Location {
+	if point == nil || len(point.Coordinates) != 2 {
+		return nil
+	}
+	return &response.Location{
+		Latitude:  point.Coordinates[1],
+		Longitude: point.Coordinates[0],
+	}
+}
@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"strings"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/response"
+)
+
+// localize picks the name in the first requested language that has one. A regional tag such as en-gb falls
+// back to its base language before the next language is tried, the default name is used when none match.
+func localize(names map[string]string, fallback string, languages []string) string {
+	if len(names) == 0 {
+		return fallback
+	}
+	for _, language := range languages {
+		for tag := language; tag != ""; tag = parentTag(tag) {
+			if name := names[tag]; name != "" {
+				return name
+			}
+		}
+	}
+	return fallback
+}
+
+// parentTag drops the last subtag of a language tag, it is empty for a base language
+func parentTag(tag string) string {
+	if i := strings.LastIndexByte(tag, '-'); i > 0 {
+		return tag[:i]
+	}
+	return ""
+}
+
+// normalizeNames lowercases the language tags so they match the tags read from requests
+func normalizeNames(names map[string]string) map[string]string {
+	if names == nil {
+		return nil
+	}
+	normalized := make(map[string]string, len(names))
+	for tag, name := range names {
+		normalized[strings.ToLower(strings.ReplaceAll(tag, "_", "-"))] = strings.TrimSpace(name)
+	}
+	return normalized
+}
+
+func subDistrictResponse(value entity.SubDistrict, languages []string) response.SubDistrict {
+	return response.SubDistrict{
+		Id:           value.Id,
+		Name:         localize(value.Names, value.Name, languages),
+		DistrictId:   value.DistrictId,
+		DistrictName: localize(value.DistrictNames, value.DistrictName, languages),
+		CityId:       value.CityId,
+		CityName:     localize(value.CityNames, value.CityName, languages),
+		ProvinceId:   value.ProvinceId,
+		ProvinceName: localize(value.ProvinceNames, value.ProvinceName, languages),
+		PostalCode:   value.PostalCode,
+	}
+}
+
+func countryResponse(country entity.Country, languages []string) *response.Country {
+	return &response.Country{
+		Id:            country.Id,
+		Code:          country.Code,
+		Iso3:          country.Iso3,
+		Number:        country.Number,
+		Name:          localize(country.Names, country.Name, languages),
+		ContinentCode: country.ContinentCode,
+		ContinentName: localize(country.ContinentNames, country.ContinentName, languages),
+		FullName:      country.FullName,
+	}
+}
@@ -3,6 +3,8 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 	"user-service/internal/modules/address"
 	"user-service/internal/modules/address/models/entity"
@@ -18,12 +20,16 @@ import (
 type queryUsecase struct {
 	addressRepositoryQuery address.MongodbRepositoryQuery
 	logger                 log.Logger
+	searchIndex            *searchIndex
+	datasetIndex           *datasetIndex
 }
 
 func NewQueryUsecase(amq address.MongodbRepositoryQuery, log log.Logger) address.UsecaseQuery {
 	return queryUsecase{
 		addressRepositoryQuery: amq,
 		logger:                 log,
+		searchIndex:            &searchIndex{},
+		datasetIndex:           &datasetIndex{},
 	}
 }
 
@@ -57,13 +63,13 @@ func (q queryUsecase) FindProvinces(origCtx context.Context, payload request.Pro
 	for _, value := range *province {
 		collectionData = append(collectionData, response.Province{
 			Id:   value.Id,
-			Name: value.Name,
+			Name: localize(value.Names, value.Name, payload.Languages),
 		})
 	}
 
 	return &response.ProvinceResp{
 		CollectionData: collectionData,
-		MetaData:       helpers.GenerateMetaData(resp.Count, int64(len(*province)), payload.Page, payload.Size),
+		MetaData:       helpers.GenerateResultMetaData(resp, int64(len(*province)), payload.Page, payload.Size),
 	}, nil
 
 }
@@ -98,15 +104,15 @@ func (q queryUsecase) FindCities(origCtx context.Context, payload request.City)
 	for _, value := range *city {
 		collectionData = append(collectionData, response.City{
 			Id:           value.Id,
-			Name:         value.Name,
+			Name:         localize(value.Names, value.Name, payload.Languages),
 			ProvinceId:   value.ProvinceId,
-			ProvinceName: value.ProvinceName,
+			ProvinceName: localize(value.ProvinceNames, value.ProvinceName, payload.Languages),
 		})
 	}
 
 	return &response.CityResp{
 		CollectionData: collectionData,
-		MetaData:       helpers.GenerateMetaData(resp.Count, int64(len(*city)), payload.Page, payload.Size),
+		MetaData:       helpers.GenerateResultMetaData(resp, int64(len(*city)), payload.Page, payload.Size),
 	}, nil
 
 }
@@ -141,17 +147,17 @@ func (q queryUsecase) FindDistricts(origCtx context.Context, payload request.Dis
 	for _, value := range *district {
 		collectionData = append(collectionData, response.District{
 			Id:           value.Id,
-			Name:         value.Name,
+			Name:         localize(value.Names, value.Name, payload.Languages),
 			CityId:       value.CityId,
-			CityName:     value.CityName,
+			CityName:     localize(value.CityNames, value.CityName, payload.Languages),
 			ProvinceId:   value.ProvinceId,
-			ProvinceName: value.ProvinceName,
+			ProvinceName: localize(value.ProvinceNames, value.ProvinceName, payload.Languages),
 		})
 	}
 
 	return &response.DistrictResp{
 		CollectionData: collectionData,
-		MetaData:       helpers.GenerateMetaData(resp.Count, int64(len(*district)), payload.Page, payload.Size),
+		MetaData:       helpers.GenerateResultMetaData(resp, int64(len(*district)), payload.Page, payload.Size),
 	}, nil
 }
 
@@ -183,25 +189,51 @@ func (q queryUsecase) FindSubDistricts(origCtx context.Context, payload request.
 
 	var collectionData = make([]response.SubDistrict, 0)
 	for _, value := range *subdistrict {
-		collectionData = append(collectionData, response.SubDistrict{
-			Id:           value.Id,
-			Name:         value.Name,
-			DistrictId:   value.DistrictId,
-			DistrictName: value.DistrictName,
-			CityId:       value.CityId,
-			CityName:     value.CityName,
-			ProvinceId:   value.ProvinceId,
-			ProvinceName: value.ProvinceName,
-		})
+		collectionData = append(collectionData, subDistrictResponse(value, payload.Languages))
 	}
 
 	return &response.SubDistrictResp{
 		CollectionData: collectionData,
-		MetaData:       helpers.GenerateMetaData(resp.Count, int64(len(*subdistrict)), payload.Page, payload.Size),
+		MetaData:       helpers.GenerateResultMetaData(resp, int64(len(*subdistrict)), payload.Page, payload.Size),
 	}, nil
 
 }
 
+// FindByPostalCode lists the subdistricts sharing a postal code, a code can cover several subdistricts
+func (q queryUsecase) FindByPostalCode(origCtx context.Context, payload request.PostalCode) ([]response.SubDistrict, error) {
+	domain := "addressUsecase-FindByPostalCode"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-q.addressRepositoryQuery.FindSubdistrictsByPostalCode(ctx, payload.Code)
+	if resp.Error != nil {
+		msg := "Error query postal code"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, errors.NotFound("postal code not found")
+	}
+	subdistricts, ok := resp.Data.(*[]entity.SubDistrict)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if len(*subdistricts) == 0 {
+		msg := "Postal code not found"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.NotFound("postal code not found")
+	}
+
+	result := make([]response.SubDistrict, 0, len(*subdistricts))
+	for _, value := range *subdistricts {
+		result = append(result, subDistrictResponse(value, payload.Languages))
+	}
+	return result, nil
+}
+
 func (q queryUsecase) FindCountries(origCtx context.Context, payload request.Country) (*response.CountryResp, error) {
 	domain := "addressUsecase-FindCountries"
 	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
@@ -230,24 +262,45 @@ func (q queryUsecase) FindCountries(origCtx context.Context, payload request.Cou
 
 	var collectionData = make([]response.Country, 0)
 	for _, value := range *country {
-		collectionData = append(collectionData, response.Country{
-			Id:            value.Id,
-			Code:          value.Code,
-			Name:          value.Name,
-			ContinentCode: value.ContinentCode,
-			ContinentName: value.ContinentName,
-			FullName:      value.FullName,
-		})
+		collectionData = append(collectionData, *countryResponse(value, payload.Languages))
 	}
 
 	return &response.CountryResp{
 		CollectionData: collectionData,
-		MetaData:       helpers.GenerateMetaData(resp.Count, int64(len(*country)), payload.Page, payload.Size),
+		MetaData:       helpers.GenerateResultMetaData(resp, int64(len(*country)), payload.Page, payload.Size),
 	}, nil
 
 }
 
-func (q queryUsecase) FindContinent(origCtx context.Context) (*response.ContinentResp, error) {
+// FindCountryByCode looks up an active country by its ISO 3166-1 alpha-2, alpha-3 or numeric code
+func (q queryUsecase) FindCountryByCode(origCtx context.Context, payload request.CountryCode) (*response.Country, error) {
+	domain := "addressUsecase-FindCountryByCode"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-q.addressRepositoryQuery.FindOneCountryByCode(ctx, payload.Code)
+	if resp.Error != nil {
+		msg := "Error query country"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", resp.Error))
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, errors.NotFound("country not found")
+	}
+	country, ok := resp.Data.(*entity.Country)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if country.Deactivated {
+		return nil, errors.NotFound("country not found")
+	}
+	return countryResponse(*country, payload.Languages), nil
+}
+
+func (q queryUsecase) FindContinent(origCtx context.Context, payload request.Continent) (*response.ContinentResp, error) {
 	domain := "addressUsecase-FindContinent"
 	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
 		Start:  time.Now(),
@@ -277,7 +330,7 @@ func (q queryUsecase) FindContinent(origCtx context.Context) (*response.Continen
 	for _, value := range *continent {
 		collectionData = append(collectionData, response.Continent{
 			Code: value.Code,
-			Name: value.Name,
+			Name: localize(value.Names, value.Name, payload.Languages),
 		})
 	}
 
@@ -287,3 +340,133 @@ func (q queryUsecase) FindContinent(origCtx context.Context) (*response.Continen
 	}, nil
 
 }
+
+var isoAlphaCode = regexp.MustCompile(`^[A-Za-z]{2,3}$`)
+
+// hierarchyRule is how the address of one country is validated
+type hierarchyRule struct {
+	// SubdistrictRequired resolves the province, city and district from a mandatory subdistrict
+	SubdistrictRequired bool
+}
+
+// hierarchyRules is keyed by country code, countries without a rule take a free text address
+var hierarchyRules = map[string]hierarchyRule{
+	"ID": {SubdistrictRequired: true},
+}
+
+func (q queryUsecase) ValidateHierarchy(origCtx context.Context, payload request.Hierarchy) (*response.Hierarchy, error) {
+	domain := "addressUsecase-ValidateHierarchy"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	// A numeric CountryId is the country id, ISO numeric codes are not accepted here
+	countryId, err := strconv.Atoi(payload.CountryId)
+	if err != nil && !isoAlphaCode.MatchString(payload.CountryId) {
+		msg := "CountryId must be an id or an ISO code"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, map[string]string{"countryId": "must be an integer or an ISO 3166-1 alpha-2 or alpha-3 code"})
+	}
+	location, fields := parseLocation(payload.Latitude, payload.Longitude)
+	if len(fields) > 0 {
+		msg := "Address coordinates are not valid"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	var respCountry helpers.Result
+	if err == nil {
+		respCountry = <-q.addressRepositoryQuery.FindOneCountry(ctx, countryId)
+	} else {
+		respCountry = <-q.addressRepositoryQuery.FindOneCountryByCode(ctx, payload.CountryId)
+	}
+	if respCountry.Error != nil {
+		return nil, respCountry.Error
+	}
+	if respCountry.Data == nil {
+		msg := errors.CountryNotFound.Message
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.CountryNotFound.New(), map[string]string{"countryId": "not found"})
+	}
+	country, ok := respCountry.Data.(*entity.Country)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data country")
+	}
+	if country.Deactivated {
+		msg := errors.CountryDeactivated.Message
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.CountryDeactivated.New(), map[string]string{"countryId": "is deactivated"})
+	}
+	if location != nil && !boundsContain(country.Bounds, *location) {
+		msg := "Address coordinates are outside the country"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		outside := fmt.Sprintf("is outside %s", country.Name)
+		return nil, errors.ValidationError(msg, map[string]string{"latitude": outside, "longitude": outside})
+	}
+	result := response.Hierarchy{
+		Country:  *countryResponse(*country, nil),
+		Location: location,
+	}
+
+	rule, ok := hierarchyRules[country.Code]
+	if !ok {
+		return &result, nil
+	}
+	fields = map[string]string{}
+	if rule.SubdistrictRequired && payload.SubdistrictId == "" {
+		fields["subdistrictId"] = "is required"
+	}
+	if len(fields) > 0 {
+		msg := "Address hierarchy is not valid"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+	if payload.SubdistrictId == "" {
+		return &result, nil
+	}
+
+	respSubdistrict := <-q.addressRepositoryQuery.FindOneSubdistrict(ctx, payload.SubdistrictId)
+	if respSubdistrict.Error != nil {
+		return nil, respSubdistrict.Error
+	}
+	if respSubdistrict.Data == nil {
+		msg := errors.SubdistrictNotFound.Message
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.SubdistrictNotFound.New(), map[string]string{"subdistrictId": "not found"})
+	}
+	subdistrict, ok := respSubdistrict.Data.(*entity.SubDistrict)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data subdistrict")
+	}
+	if subdistrict.Deactivated {
+		msg := errors.SubdistrictDeactivated.Message
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.WithFields(errors.SubdistrictDeactivated.New(), map[string]string{"subdistrictId": "is deactivated"})
+	}
+
+	// Parent ids are optional, but when given they must be the parents of the subdistrict
+	if payload.ProvinceId != "" && payload.ProvinceId != subdistrict.ProvinceId {
+		fields["provinceId"] = fmt.Sprintf("does not match the subdistrict, expected %s", subdistrict.ProvinceId)
+	}
+	if payload.CityId != "" && payload.CityId != subdistrict.CityId {
+		fields["cityId"] = fmt.Sprintf("does not match the subdistrict, expected %s", subdistrict.CityId)
+	}
+	if payload.DistrictId != "" && payload.DistrictId != subdistrict.DistrictId {
+		fields["districtId"] = fmt.Sprintf("does not match the subdistrict, expected %s", subdistrict.DistrictId)
+	}
+	// Subdistricts without a postal code on record accept any
+	if payload.PostalCode != "" && subdistrict.PostalCode != "" && payload.PostalCode != subdistrict.PostalCode {
+		fields["postalCode"] = fmt.Sprintf("does not match the subdistrict, expected %s", subdistrict.PostalCode)
+	}
+	if len(fields) > 0 {
+		msg := "Address hierarchy is not valid"
+		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		return nil, errors.ValidationError(msg, fields)
+	}
+
+	// The hierarchy is stored on the user, it keeps the default names
+	subdistrictResponse := subDistrictResponse(*subdistrict, nil)
+	result.SubDistrict = &subdistrictResponse
+	return &result, nil
+}
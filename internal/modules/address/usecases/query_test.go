@@ -6,7 +6,9 @@ import (
 	"user-service/internal/modules/address"
 	"user-service/internal/modules/address/models/entity"
 	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
 	uc "user-service/internal/modules/address/usecases"
+	"user-service/internal/pkg/constants"
 	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
 	mockcertAddress "user-service/mocks/modules/address"
@@ -64,6 +66,52 @@ func (suite *QueryUsecaseTestSuite) TestFindProvinceSuccess() {
 	assert.NotNil(suite.T(), result)
 }
 
+func (suite *QueryUsecaseTestSuite) TestFindProvinceByCursor() {
+	// Arrange
+	payload := request.Province{
+		Size:  1,
+		Count: true,
+	}
+	suite.mockAddressRepositoryQuery.On("FindProvinces", mock.Anything, payload).Return(mockChannel(helpers.Result{
+		Data:     &[]entity.Province{{Id: "11", Name: "ACEH"}},
+		MetaData: "after-aceh",
+		Count:    38,
+	}))
+
+	// Act
+	result, err := suite.usecase.FindProvinces(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), constants.MetaData{Count: 1, TotalPage: 38, TotalData: 38, NextCursor: "after-aceh"}, result.MetaData)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindProvinceLocalized() {
+	// Arrange
+	payload := request.Province{
+		Page:      1,
+		Size:      10,
+		Languages: []string{"en-gb", "id"},
+	}
+
+	mockUserQueryResponse := helpers.Result{
+		Data: &[]entity.Province{
+			{Id: "31", Name: "DKI Jakarta", Names: map[string]string{"en": "Jakarta Special Capital Region"}},
+			{Id: "96", Name: "Papua Barat Daya", Names: map[string]string{"fr": "Papouasie du Sud-Ouest"}},
+		},
+		Error: nil,
+	}
+	suite.mockAddressRepositoryQuery.On("FindProvinces", mock.Anything, payload).Return(mockChannel(mockUserQueryResponse))
+
+	// Act
+	result, err := suite.usecase.FindProvinces(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Jakarta Special Capital Region", result.CollectionData[0].Name)
+	assert.Equal(suite.T(), "Papua Barat Daya", result.CollectionData[1].Name)
+}
+
 func (suite *QueryUsecaseTestSuite) TestFindProvinceErr() {
 	// Arrange
 	payload := request.Province{
@@ -549,7 +597,7 @@ func (suite *QueryUsecaseTestSuite) TestFindContinentSuccess() {
 	suite.mockAddressRepositoryQuery.On("FindContinent", mock.Anything).Return(mockChannel(mockUserQueryResponse))
 
 	// Act
-	result, err := suite.usecase.FindContinent(suite.ctx)
+	result, err := suite.usecase.FindContinent(suite.ctx, request.Continent{})
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -566,7 +614,7 @@ func (suite *QueryUsecaseTestSuite) TestFindContinentSuccessErr() {
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 
 	// Act
-	_, err := suite.usecase.FindContinent(suite.ctx)
+	_, err := suite.usecase.FindContinent(suite.ctx, request.Continent{})
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -579,7 +627,7 @@ func (suite *QueryUsecaseTestSuite) TestFindContinentSuccessErr() {
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 
 	// Act
-	_, err = suite.usecase.FindContinent(suite.ctx)
+	_, err = suite.usecase.FindContinent(suite.ctx, request.Continent{})
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -600,7 +648,7 @@ func (suite *QueryUsecaseTestSuite) TestFindContinentSuccessErrParse() {
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 
 	// Act
-	_, err := suite.usecase.FindContinent(suite.ctx)
+	_, err := suite.usecase.FindContinent(suite.ctx, request.Continent{})
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -617,3 +665,415 @@ func mockChannel(result helpers.Result) <-chan helpers.Result {
 
 	return responseChan
 }
+
+func mockIndonesia() helpers.Result {
+	return helpers.Result{
+		Data:  &entity.Country{Id: 100, Code: "ID", Name: "Indonesia", ContinentCode: "AS", ContinentName: "Asia"},
+		Error: nil,
+	}
+}
+
+func mockSubdistrict() helpers.Result {
+	return helpers.Result{
+		Data: &entity.SubDistrict{
+			Id:           "3201010001",
+			Name:         "Cibinong",
+			DistrictId:   "3201010",
+			DistrictName: "Cibinong",
+			CityId:       "3201",
+			CityName:     "Kabupaten Bogor",
+			ProvinceId:   "32",
+			ProvinceName: "Jawa Barat",
+			PostalCode:   "16914",
+		},
+		Error: nil,
+	}
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchySuccess() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		ProvinceId:    "32",
+		CityId:        "3201",
+		DistrictId:    "3201010",
+		SubdistrictId: "3201010001",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(mockSubdistrict()))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ID", result.Country.Code)
+	assert.Equal(suite.T(), "Kabupaten Bogor", result.SubDistrict.CityName)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyParentsOptional() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		SubdistrictId: "3201010001",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(mockSubdistrict()))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "32", result.SubDistrict.ProvinceId)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyParentMismatch() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		ProvinceId:    "31",
+		CityId:        "3201",
+		DistrictId:    "3171010",
+		SubdistrictId: "3201010001",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(mockSubdistrict()))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address hierarchy is not valid", map[string]string{
+		"provinceId": "does not match the subdistrict, expected 32",
+		"districtId": "does not match the subdistrict, expected 3201010",
+	}), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchySubdistrictRequired() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "100",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address hierarchy is not valid", map[string]string{
+		"subdistrictId": "is required",
+	}), err)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneSubdistrict", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCountryWithoutRule() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "2",
+		SubdistrictId: "3201010001",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 2).Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 2, Code: "SG", Name: "Singapore"},
+	}))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "SG", result.Country.Code)
+	assert.Nil(suite.T(), result.SubDistrict)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneSubdistrict", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCountryNotFound() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "999",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 999).Return(mockChannel(helpers.Result{Data: nil}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	errString, ok := err.(*errors.ErrorString)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), errors.CountryNotFound.Code, errString.Code())
+	assert.Equal(suite.T(), map[string]string{"countryId": "not found"}, errString.Fields())
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCountryIdNotValid() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "ID-1",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("CountryId must be an id or an ISO code", map[string]string{"countryId": "must be an integer or an ISO 3166-1 alpha-2 or alpha-3 code"}), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCountryIsoCode() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "sgp",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountryByCode", mock.Anything, "sgp").Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 2, Code: "SG", Iso3: "SGP", Name: "Singapore"},
+	}))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, result.Country.Id)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneCountry", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindCountryByCode() {
+	// Arrange
+	payload := request.CountryCode{Code: "360", Languages: []string{"id"}}
+	suite.mockAddressRepositoryQuery.On("FindOneCountryByCode", mock.Anything, "360").Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 100, Code: "ID", Iso3: "IDN", Number: 360, Name: "Indonesia", ContinentName: "Asia", ContinentNames: map[string]string{"id": "Asia Tenggara"}},
+	}))
+
+	// Act
+	result, err := suite.usecase.FindCountryByCode(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "IDN", result.Iso3)
+	assert.Equal(suite.T(), "Asia Tenggara", result.ContinentName)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindCountryByCodeDeactivated() {
+	// Arrange
+	payload := request.CountryCode{Code: "AN"}
+	suite.mockAddressRepositoryQuery.On("FindOneCountryByCode", mock.Anything, "AN").Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 8, Code: "AN", Name: "Netherlands Antilles", Deactivated: true},
+	}))
+
+	// Act
+	_, err := suite.usecase.FindCountryByCode(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.NotFound("country not found"), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchySubdistrictDeactivated() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		SubdistrictId: "3201010001",
+	}
+	subdistrict := mockSubdistrict()
+	subdistrict.Data.(*entity.SubDistrict).Deactivated = true
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(subdistrict))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), map[string]string{"subdistrictId": "is deactivated"}, err.(*errors.ErrorString).Fields())
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyPostalCodeMismatch() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		SubdistrictId: "3201010001",
+		PostalCode:    "16915",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(mockSubdistrict()))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), map[string]string{"postalCode": "does not match the subdistrict, expected 16914"}, err.(*errors.ErrorString).Fields())
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyPostalCodeNotOnRecord() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		SubdistrictId: "3201010001",
+		PostalCode:    "16915",
+	}
+	subdistrict := mockSubdistrict()
+	subdistrict.Data.(*entity.SubDistrict).PostalCode = ""
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(mockIndonesia()))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(subdistrict))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "3201010001", result.SubDistrict.Id)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindByPostalCodeSuccess() {
+	// Arrange
+	subdistricts := []entity.SubDistrict{*mockSubdistrict().Data.(*entity.SubDistrict)}
+	suite.mockAddressRepositoryQuery.On("FindSubdistrictsByPostalCode", mock.Anything, "16914").Return(mockChannel(helpers.Result{Data: &subdistricts}))
+
+	// Act
+	result, err := suite.usecase.FindByPostalCode(suite.ctx, request.PostalCode{Code: "16914"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+	assert.Equal(suite.T(), "Kabupaten Bogor", result[0].CityName)
+	assert.Equal(suite.T(), "16914", result[0].PostalCode)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindByPostalCodeNotFound() {
+	// Arrange
+	var subdistricts []entity.SubDistrict
+	suite.mockAddressRepositoryQuery.On("FindSubdistrictsByPostalCode", mock.Anything, "99999").Return(mockChannel(helpers.Result{Data: &subdistricts}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.FindByPostalCode(suite.ctx, request.PostalCode{Code: "99999"})
+
+	// Assert
+	assert.Equal(suite.T(), errors.NotFound("postal code not found"), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCoordinatesInvalid() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "100",
+		Latitude:  "91",
+		Longitude: "east",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address coordinates are not valid", map[string]string{
+		"latitude":  "must be between -90 and 90",
+		"longitude": "must be a number",
+	}), err)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneCountry", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCoordinatesNotFinite() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "100",
+		Latitude:  "NaN",
+		Longitude: "-Inf",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address coordinates are not valid", map[string]string{
+		"latitude":  "must be a number",
+		"longitude": "must be a number",
+	}), err)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneCountry", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyCoordinatesIncomplete() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "100",
+		Latitude:  "-6.48",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.Equal(suite.T(), map[string]string{"longitude": "is required with latitude"}, err.(*errors.ErrorString).Fields())
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyLocation() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId:     "100",
+		SubdistrictId: "3201010001",
+		Latitude:      "-6.48",
+		Longitude:     "106.84",
+	}
+	country := mockIndonesia()
+	country.Data.(*entity.Country).Bounds = &entity.Bounds{MinLatitude: -11, MinLongitude: 95, MaxLatitude: 6, MaxLongitude: 141}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 100).Return(mockChannel(country))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "3201010001").Return(mockChannel(mockSubdistrict()))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &response.Location{Latitude: -6.48, Longitude: 106.84}, result.Location)
+}
+
+func (suite *QueryUsecaseTestSuite) TestValidateHierarchyLocationAcrossAntimeridian() {
+	// Arrange
+	payload := request.Hierarchy{
+		CountryId: "242",
+		Latitude:  "-16.8",
+		Longitude: "-179.9",
+	}
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 242).Return(mockChannel(helpers.Result{
+		Data: &entity.Country{Id: 242, Code: "FJ", Name: "Fiji", Bounds: &entity.Bounds{MinLatitude: -21, MinLongitude: 177, MaxLatitude: -12, MaxLongitude: -178}},
+	}))
+
+	// Act
+	result, err := suite.usecase.ValidateHierarchy(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), -179.9, result.Location.Longitude)
+}
+
+func (suite *QueryUsecaseTestSuite) TestReverseGeocodeSuccess() {
+	// Arrange
+	latitude, longitude := -6.48, 106.84
+	nearest := []entity.SubDistrictDistance{{SubDistrict: *mockSubdistrict().Data.(*entity.SubDistrict), Distance: 812.5}}
+	suite.mockAddressRepositoryQuery.On("FindNearestSubdistrict", mock.Anything, latitude, longitude, mock.Anything).Return(mockChannel(helpers.Result{Data: &nearest}))
+
+	// Act
+	result, err := suite.usecase.ReverseGeocode(suite.ctx, request.ReverseGeocode{Latitude: &latitude, Longitude: &longitude})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "3201010001", result.Id)
+	assert.Equal(suite.T(), 812.5, result.Distance)
+}
+
+func (suite *QueryUsecaseTestSuite) TestReverseGeocodeNotFound() {
+	// Arrange
+	latitude, longitude := -60.0, 106.84
+	var nearest []entity.SubDistrictDistance
+	suite.mockAddressRepositoryQuery.On("FindNearestSubdistrict", mock.Anything, latitude, longitude, mock.Anything).Return(mockChannel(helpers.Result{Data: &nearest}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	_, err := suite.usecase.ReverseGeocode(suite.ctx, request.ReverseGeocode{Latitude: &latitude, Longitude: &longitude})
+
+	// Assert
+	assert.Equal(suite.T(), errors.NotFound("no subdistrict near the position"), err)
+}
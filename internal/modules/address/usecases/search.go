@@ -0,0 +1,304 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	"user-service/internal/modules/address/models/response"
+	"user-service/internal/pkg/errors"
+
+	"go.elastic.co/apm"
+)
+
+// searchPageSize is the number of subdistricts loaded per query while building the index
+const searchPageSize = 5000
+
+const defaultSearchLimit = 10
+
+// Quality of a query term matching a name token
+const (
+	matchFuzzy  = 1.0
+	matchPrefix = 2.0
+	matchExact  = 3.0
+)
+
+// levelWeights favour a match on the subdistrict name over its district, city and province, in that order
+var levelWeights = [...]float64{0.3, 0.2, 0.1, 0}
+
+type posting struct {
+	entry int32
+	level uint8
+}
+
+// searchSnapshot is an immutable index over the subdistricts loaded when it was built
+type searchSnapshot struct {
+	entries  []entity.SubDistrict
+	tokens   []string
+	postings map[string][]posting
+}
+
+// searchIndex holds the latest snapshot, it is shared by every copy of the query usecase
+type searchIndex struct {
+	mu       sync.RWMutex
+	snapshot *searchSnapshot
+	buildMu  sync.Mutex
+}
+
+func (i *searchIndex) get() *searchSnapshot {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.snapshot
+}
+
+func (i *searchIndex) set(snapshot *searchSnapshot) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.snapshot = snapshot
+}
+
+func (q queryUsecase) SearchAddresses(origCtx context.Context, payload request.Search) ([]response.SearchResult, error) {
+	domain := "addressUsecase-SearchAddresses"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	snapshot := q.searchIndex.get()
+	if snapshot == nil {
+		// The index is warmed at startup, a search arriving first builds it once for everyone
+		q.searchIndex.buildMu.Lock()
+		snapshot = q.searchIndex.get()
+		if snapshot == nil {
+			if err := q.BuildSearchIndex(ctx); err != nil {
+				q.searchIndex.buildMu.Unlock()
+				return nil, err
+			}
+			snapshot = q.searchIndex.get()
+		}
+		q.searchIndex.buildMu.Unlock()
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	return snapshot.search(payload.Query, limit, payload.Languages), nil
+}
+
+// BuildSearchIndex loads every active subdistrict and swaps in a new index, searches keep using the old one meanwhile
+func (q queryUsecase) BuildSearchIndex(origCtx context.Context) error {
+	domain := "addressUsecase-BuildSearchIndex"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	entries := make([]entity.SubDistrict, 0)
+	for cursor := ""; ; {
+		resp := <-q.addressRepositoryQuery.FindAllSubdistricts(ctx, cursor, searchPageSize)
+		if resp.Error != nil {
+			q.logger.Error(ctx, "Error load search index", fmt.Sprintf("%+v", resp.Error))
+			return resp.Error
+		}
+		if resp.Data == nil {
+			break
+		}
+		subdistricts, ok := resp.Data.(*[]entity.SubDistrict)
+		if !ok {
+			return errors.InternalServerError("cannot parsing data subdistrict")
+		}
+		for _, value := range *subdistricts {
+			// The centroid is not needed to answer a search
+			value.Centroid = nil
+			entries = append(entries, value)
+		}
+		if cursor, _ = resp.MetaData.(string); cursor == "" {
+			break
+		}
+	}
+
+	q.searchIndex.set(newSearchSnapshot(entries))
+	q.logger.Info(ctx, "Search index built", fmt.Sprintf("%d subdistricts", len(entries)))
+	return nil
+}
+
+// newSearchSnapshot indexes the default and every localized name of each level, a token is posted once per level
+func newSearchSnapshot(entries []entity.SubDistrict) *searchSnapshot {
+	snapshot := &searchSnapshot{
+		entries:  entries,
+		postings: map[string][]posting{},
+	}
+	for i, entry := range entries {
+		levels := [...]struct {
+			name  string
+			names map[string]string
+		}{
+			{entry.Name, entry.Names},
+			{entry.DistrictName, entry.DistrictNames},
+			{entry.CityName, entry.CityNames},
+			{entry.ProvinceName, entry.ProvinceNames},
+		}
+		for level, names := range levels {
+			tokens := map[string]bool{}
+			for _, token := range tokenize(names.name) {
+				tokens[token] = true
+			}
+			for _, name := range names.names {
+				for _, token := range tokenize(name) {
+					tokens[token] = true
+				}
+			}
+			for token := range tokens {
+				snapshot.postings[token] = append(snapshot.postings[token], posting{entry: int32(i), level: uint8(level)})
+			}
+		}
+	}
+	snapshot.tokens = make([]string, 0, len(snapshot.postings))
+	for token := range snapshot.postings {
+		snapshot.tokens = append(snapshot.tokens, token)
+	}
+	sort.Strings(snapshot.tokens)
+	return snapshot
+}
+
+// search ranks the subdistricts matching every term of the query, each term scores its best match in the hierarchy.
+// Results carry the names in the requested languages whichever language matched.
+func (s *searchSnapshot) search(query string, limit int, languages []string) []response.SearchResult {
+	results := make([]response.SearchResult, 0)
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return results
+	}
+
+	var scores map[int32]float64
+	for n, term := range terms {
+		termScores := map[int32]float64{}
+		for token, quality := range s.candidates(term) {
+			for _, p := range s.postings[token] {
+				if score := quality + levelWeights[p.level]; score > termScores[p.entry] {
+					termScores[p.entry] = score
+				}
+			}
+		}
+		if n == 0 {
+			scores = termScores
+			continue
+		}
+		for entry, score := range scores {
+			termScore, ok := termScores[entry]
+			if !ok {
+				delete(scores, entry)
+				continue
+			}
+			scores[entry] = score + termScore
+		}
+	}
+
+	for entry, score := range scores {
+		results = append(results, response.SearchResult{
+			SubDistrict: subDistrictResponse(s.entries[entry], languages),
+			Score:       score,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if len(results[i].Name) != len(results[j].Name) {
+			return len(results[i].Name) < len(results[j].Name)
+		}
+		return results[i].Id < results[j].Id
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// candidates are the name tokens a query term matches with their quality, a term may be
+// the start of a token and longer terms tolerate typos
+func (s *searchSnapshot) candidates(term string) map[string]float64 {
+	found := map[string]float64{}
+	for i := sort.SearchStrings(s.tokens, term); i < len(s.tokens) && strings.HasPrefix(s.tokens[i], term); i++ {
+		if s.tokens[i] == term {
+			found[s.tokens[i]] = matchExact
+		} else {
+			found[s.tokens[i]] = matchPrefix
+		}
+	}
+
+	maxEdits := allowedEdits(term)
+	if maxEdits == 0 {
+		return found
+	}
+	for _, token := range s.tokens {
+		if _, ok := found[token]; ok || len(token) < len(term)-maxEdits {
+			continue
+		}
+		if prefixDistance(term, token) <= maxEdits {
+			found[token] = matchFuzzy
+		}
+	}
+	return found
+}
+
+// allowedEdits is the number of typos tolerated for a term, short terms must be spelled right
+func allowedEdits(term string) int {
+	switch {
+	case len(term) < 4:
+		return 0
+	case len(term) < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefixDistance is the smallest edit distance between term and any prefix of token
+func prefixDistance(term string, token string) int {
+	previous := make([]int, len(token)+1)
+	current := make([]int, len(token)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(term); i++ {
+		current[0] = i
+		for j := 1; j <= len(token); j++ {
+			cost := 1
+			if term[i-1] == token[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	best := previous[0]
+	for _, distance := range previous[1:] {
+		best = minInt(best, distance)
+	}
+	return best
+}
+
+func minInt(first int, others ...int) int {
+	for _, value := range others {
+		if value < first {
+			first = value
+		}
+	}
+	return first
+}
+
+// tokenize lowercases a name and splits it on anything that is not a letter or a digit
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
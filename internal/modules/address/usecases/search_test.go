@@ -0,0 +1,177 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"user-service/internal/modules/address"
+	"user-service/internal/modules/address/models/entity"
+	"user-service/internal/modules/address/models/request"
+	uc "user-service/internal/modules/address/usecases"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
+	mockcertAddress "user-service/mocks/modules/address"
+	mocklog "user-service/mocks/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchUsecaseTestSuite struct {
+	suite.Suite
+	mockAddressRepositoryQuery *mockcertAddress.MongodbRepositoryQuery
+	mockLogger                 *mocklog.Logger
+	usecase                    address.UsecaseQuery
+	ctx                        context.Context
+}
+
+func (suite *SearchUsecaseTestSuite) SetupTest() {
+	suite.mockAddressRepositoryQuery = &mockcertAddress.MongodbRepositoryQuery{}
+	suite.mockLogger = &mocklog.Logger{}
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	suite.ctx = context.Background()
+	suite.usecase = uc.NewQueryUsecase(
+		suite.mockAddressRepositoryQuery,
+		suite.mockLogger,
+	)
+}
+
+func TestSearchUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchUsecaseTestSuite))
+}
+
+func (suite *SearchUsecaseTestSuite) mockSubdistricts() {
+	suite.mockAddressRepositoryQuery.On("FindAllSubdistricts", mock.Anything, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.SubDistrict{
+			{Id: "3174071001", Name: "SELONG", DistrictId: "317407", DistrictName: "KEBAYORAN BARU", CityId: "3174", CityName: "KOTA ADM. JAKARTA SELATAN", ProvinceId: "31", ProvinceName: "DKI JAKARTA"},
+			{Id: "3174071005", Name: "KRAMAT PELA", DistrictId: "317407", DistrictName: "KEBAYORAN BARU", CityId: "3174", CityName: "KOTA ADM. JAKARTA SELATAN", ProvinceId: "31", ProvinceName: "DKI JAKARTA"},
+			{Id: "3174061001", Name: "KEBAYORAN LAMA UTARA", DistrictId: "317406", DistrictName: "KEBAYORAN LAMA", CityId: "3174", CityName: "KOTA ADM. JAKARTA SELATAN", ProvinceId: "31", ProvinceName: "DKI JAKARTA"},
+			{Id: "3273011001", Name: "SUKAJADI", DistrictId: "327301", DistrictName: "SUKAJADI", CityId: "3273", CityName: "KOTA BANDUNG", ProvinceId: "32", ProvinceName: "JAWA BARAT"},
+		},
+	})).Once()
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchAcrossLevels() {
+	// Arrange
+	suite.mockSubdistricts()
+
+	// Act
+	result, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "kebayoran baru jakarta"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), "317407", result[0].DistrictId)
+	assert.Equal(suite.T(), "KOTA ADM. JAKARTA SELATAN", result[1].CityName)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchRanksSubdistrictName() {
+	// Arrange
+	suite.mockSubdistricts()
+
+	// Act
+	result, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "sukajadi"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+	assert.InDelta(suite.T(), 3.3, result[0].Score, 0.001)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchPrefixAndTypo() {
+	// Arrange
+	suite.mockSubdistricts()
+
+	// Act
+	prefix, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "kramat pe"})
+	typo, typoErr := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "kebayoran lamma"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "3174071005", prefix[0].Id)
+	assert.NoError(suite.T(), typoErr)
+	assert.Equal(suite.T(), "3174061001", typo[0].Id)
+	suite.mockAddressRepositoryQuery.AssertNumberOfCalls(suite.T(), "FindAllSubdistricts", 1)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchLimit() {
+	// Arrange
+	suite.mockSubdistricts()
+
+	// Act
+	result, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "jakarta", Limit: 2})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchLocalizedNames() {
+	// Arrange
+	suite.mockAddressRepositoryQuery.On("FindAllSubdistricts", mock.Anything, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data: &[]entity.SubDistrict{
+			{Id: "3174071001", Name: "SELONG", CityName: "KOTA ADM. JAKARTA SELATAN", ProvinceName: "DKI JAKARTA", ProvinceNames: map[string]string{"en": "Jakarta Special Capital Region"}},
+			{Id: "3273011001", Name: "SUKAJADI", CityName: "KOTA BANDUNG", ProvinceName: "JAWA BARAT", ProvinceNames: map[string]string{"en": "West Java"}},
+		},
+	})).Once()
+
+	// Act
+	english, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "special capital", Languages: []string{"en"}})
+	indonesian, indonesianErr := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "west java"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), english, 1)
+	assert.Equal(suite.T(), "Jakarta Special Capital Region", english[0].ProvinceName)
+	assert.NoError(suite.T(), indonesianErr)
+	assert.Len(suite.T(), indonesian, 1)
+	assert.Equal(suite.T(), "JAWA BARAT", indonesian[0].ProvinceName)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchNoMatch() {
+	// Arrange
+	suite.mockSubdistricts()
+
+	// Act
+	result, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "surabaya"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearchErrBuild() {
+	// Arrange
+	suite.mockAddressRepositoryQuery.On("FindAllSubdistricts", mock.Anything, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Error: errors.InternalServerError("Error mongodb connection"),
+	}))
+
+	// Act
+	_, err := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "selong"})
+
+	// Assert
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SearchUsecaseTestSuite) TestBuildSearchIndexFollowsCursor() {
+	// Arrange
+	suite.mockAddressRepositoryQuery.On("FindAllSubdistricts", mock.Anything, "", mock.Anything).Return(mockChannel(helpers.Result{
+		Data:     &[]entity.SubDistrict{{Id: "3174071001", Name: "SELONG", ProvinceName: "DKI JAKARTA"}},
+		MetaData: "after-selong",
+	})).Once()
+	suite.mockAddressRepositoryQuery.On("FindAllSubdistricts", mock.Anything, "after-selong", mock.Anything).Return(mockChannel(helpers.Result{
+		Data:     &[]entity.SubDistrict{{Id: "3273011001", Name: "SUKAJADI", ProvinceName: "JAWA BARAT"}},
+		MetaData: "",
+	})).Once()
+
+	// Act
+	err := suite.usecase.BuildSearchIndex(suite.ctx)
+	result, searchErr := suite.usecase.SearchAddresses(suite.ctx, request.Search{Query: "sukajadi"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), searchErr)
+	assert.Len(suite.T(), result, 1)
+	suite.mockAddressRepositoryQuery.AssertNumberOfCalls(suite.T(), "FindAllSubdistricts", 2)
+}
@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 	user "user-service/internal/modules/user"
 	userRequest "user-service/internal/modules/user/models/request"
 	"user-service/internal/pkg/errors"
@@ -26,7 +30,7 @@ func InitUserHttpHandler(app *fiber.App, uuc user.UsecaseCommand, uuq user.Useca
 		UserUsecaseCommand: uuc,
 		UserUsecaseQuery:   uuq,
 		Logger:             log,
-		Validator:          validator.New(),
+		Validator:          helpers.NewValidator(),
 	}
 	middlewares := middlewares.NewMiddlewares(redisClient)
 	route := app.Group("/api/users")
@@ -35,6 +39,24 @@ func InitUserHttpHandler(app *fiber.App, uuc user.UsecaseCommand, uuq user.Useca
 	route.Post("/v1/login", middlewares.VerifyBasicAuth(), handler.Login)
 	route.Put("/v1/profile", middlewares.VerifyBearer(), handler.UpdateUser)
 	route.Get("/v1/profile", middlewares.VerifyBearer(), handler.GetProfile)
+	route.Patch("/v1/profile", middlewares.VerifyBearer(), handler.PatchUser)
+	route.Post("/v1/profile/avatar", middlewares.VerifyBearer(), handler.UploadAvatar)
+	route.Delete("/v1/account", middlewares.VerifyBearer(), handler.DeleteAccount)
+	route.Post("/v1/data-export", middlewares.VerifyBearer(), handler.RequestDataExport)
+	route.Get("/v1/data-export/:exportId", middlewares.VerifyBearer(), handler.GetDataExport)
+	route.Get("/v1/data-export/:exportId/download", handler.DownloadDataExport)
+	route.Get("/v1/terms", middlewares.VerifyBasicAuth(), handler.GetTermsVersion)
+	route.Get("/v1/consents", middlewares.VerifyBearer(), handler.GetConsents)
+	route.Put("/v1/consents", middlewares.VerifyBearer(), handler.UpdateConsents)
+	route.Get("/v1/preferences", middlewares.VerifyBearer(), handler.GetPreferences)
+	route.Patch("/v1/preferences", middlewares.VerifyBearer(), handler.UpdatePreferences)
+	route.Get("/v1/addresses", middlewares.VerifyBearer(), handler.GetAddresses)
+	route.Post("/v1/addresses", middlewares.VerifyBearer(), handler.CreateAddress)
+	route.Put("/v1/addresses/:addressId", middlewares.VerifyBearer(), handler.UpdateAddress)
+	route.Delete("/v1/addresses/:addressId", middlewares.VerifyBearer(), handler.DeleteAddress)
+	route.Put("/v1/addresses/:addressId/default", middlewares.VerifyBearer(), handler.SetDefaultAddress)
+	route.Get("/v1/internal/consents/marketing", middlewares.VerifyInternalAuth(), handler.GetMarketingConsent)
+	route.Get("/v1/internal/audiences/marketing", middlewares.VerifyInternalAuth(), handler.FindMarketingAudience)
 }
 
 func (u UserHttpHandler) UpdateUser(c *fiber.Ctx) error {
@@ -43,7 +65,7 @@ func (u UserHttpHandler) UpdateUser(c *fiber.Ctx) error {
 		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
 	}
 	if err := u.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, u.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
 	}
 	userId := c.Locals("userId").(string)
 	resp, err := u.UserUsecaseCommand.UpdateUser(c.Context(), *req, userId)
@@ -53,6 +75,66 @@ func (u UserHttpHandler) UpdateUser(c *fiber.Ctx) error {
 	return helpers.RespSuccess(c, u.Logger, resp, "Update user success")
 }
 
+func (u UserHttpHandler) PatchUser(c *fiber.Ctx) error {
+	req := new(userRequest.PatchUser)
+	req.IfMatch = c.Get(fiber.HeaderIfMatch)
+	if req.IfMatch == "" {
+		return helpers.RespError(c, u.Logger, errors.PreconditionRequired("If-Match header is required"))
+	}
+	req.Patch = c.Body()
+	if !json.Valid(req.Patch) {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	resp, err := u.UserUsecaseCommand.PatchUser(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	c.Set(fiber.HeaderETag, helpers.FormatETag(resp.Version))
+	return helpers.RespSuccess(c, u.Logger, resp, "Patch user success")
+}
+
+// maxAvatarSize caps avatar uploads well below the 30 MB app body limit
+const maxAvatarSize = 5 * 1024 * 1024
+
+func (u UserHttpHandler) UploadAvatar(c *fiber.Ctx) error {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("avatar file is required"))
+	}
+	if file.Size > maxAvatarSize {
+		return helpers.RespCustomError(c, u.Logger, errors.AvatarTooLarge.New())
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	f, err := file.Open()
+	if err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req := userRequest.UploadAvatar{
+		UserId:  userId,
+		Content: content,
+	}
+	resp, err := u.UserUsecaseCommand.UploadAvatar(c.Context(), req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Upload avatar success")
+}
+
 func (u UserHttpHandler) RegisterUser(c *fiber.Ctx) error {
 	req := new(userRequest.RegisterUser)
 	if err := c.BodyParser(req); err != nil {
@@ -60,7 +142,7 @@ func (u UserHttpHandler) RegisterUser(c *fiber.Ctx) error {
 	}
 
 	if err := u.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, u.Logger, errors.BadRequest(err.Error()))
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
 	}
 	resp, err := u.UserUsecaseCommand.RegisterUser(c.Context(), *req)
 	if err != nil {
@@ -76,7 +158,7 @@ func (u UserHttpHandler) VerifyRegisterUser(c *fiber.Ctx) error {
 	}
 
 	if err := u.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, u.Logger, errors.BadRequest("validation error"))
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
 	}
 	resp, err := u.UserUsecaseCommand.VerifyRegisterUser(c.Context(), *req)
 	if err != nil {
@@ -91,7 +173,7 @@ func (u UserHttpHandler) Login(c *fiber.Ctx) error {
 		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
 	}
 	if err := u.Validator.Struct(req); err != nil {
-		return helpers.RespError(c, u.Logger, errors.BadRequest("validation error"))
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
 	}
 
 	resp, err := u.UserUsecaseCommand.LoginUser(c.Context(), *req)
@@ -101,8 +183,283 @@ func (u UserHttpHandler) Login(c *fiber.Ctx) error {
 	return helpers.RespSuccess(c, u.Logger, resp, "Login user success")
 }
 
+func (u UserHttpHandler) DeleteAccount(c *fiber.Ctx) error {
+	req := new(userRequest.DeleteAccount)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	resp, err := u.UserUsecaseCommand.DeleteAccount(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Delete account requested")
+}
+
+func (u UserHttpHandler) RequestDataExport(c *fiber.Ctx) error {
+	req := new(userRequest.RequestDataExport)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	resp, err := u.UserUsecaseCommand.RequestDataExport(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Request data export success")
+}
+
+func (u UserHttpHandler) GetDataExport(c *fiber.Ctx) error {
+	req := new(userRequest.GetDataExport)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	req.ExportId = c.Params("exportId")
+	resp, err := u.UserUsecaseQuery.GetDataExport(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get data export success")
+}
+
+func (u UserHttpHandler) DownloadDataExport(c *fiber.Ctx) error {
+	req := new(userRequest.DownloadDataExport)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+
+	req.ExportId = c.Params("exportId")
+	resp, err := u.UserUsecaseQuery.DownloadDataExport(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", resp.FileName))
+	return c.Send(resp.Content)
+}
+
+func (u UserHttpHandler) GetTermsVersion(c *fiber.Ctx) error {
+	resp, err := u.UserUsecaseQuery.GetTermsVersion(c.Context())
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get terms version success")
+}
+
+func (u UserHttpHandler) GetConsents(c *fiber.Ctx) error {
+	req := new(userRequest.GetConsents)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	resp, err := u.UserUsecaseQuery.GetConsents(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get consents success")
+}
+
+func (u UserHttpHandler) UpdateConsents(c *fiber.Ctx) error {
+	req := new(userRequest.UpdateConsents)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	resp, err := u.UserUsecaseCommand.UpdateConsents(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Update consents success")
+}
+
+func (u UserHttpHandler) GetMarketingConsent(c *fiber.Ctx) error {
+	req := new(userRequest.GetMarketingConsent)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+
+	resp, err := u.UserUsecaseQuery.GetMarketingConsent(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get marketing consent success")
+}
+
+func (u UserHttpHandler) FindMarketingAudience(c *fiber.Ctx) error {
+	req := new(userRequest.FindMarketingAudience)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+
+	resp, err := u.UserUsecaseQuery.FindMarketingAudience(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespPagination(c, u.Logger, resp.CollectionData, resp.MetaData, "Find marketing audience success")
+}
+
+func (u UserHttpHandler) GetPreferences(c *fiber.Ctx) error {
+	req := new(userRequest.GetPreferences)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	resp, err := u.UserUsecaseQuery.GetPreferences(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get preferences success")
+}
+
+func (u UserHttpHandler) UpdatePreferences(c *fiber.Ctx) error {
+	req := new(userRequest.UpdatePreferences)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	resp, err := u.UserUsecaseCommand.UpdatePreferences(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Update preferences success")
+}
+
+func (u UserHttpHandler) GetAddresses(c *fiber.Ctx) error {
+	req := new(userRequest.GetAddresses)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	resp, err := u.UserUsecaseQuery.GetAddresses(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Get addresses success")
+}
+
+func (u UserHttpHandler) CreateAddress(c *fiber.Ctx) error {
+	req := new(userRequest.SaveAddress)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	resp, err := u.UserUsecaseCommand.CreateAddress(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Create address success")
+}
+
+func (u UserHttpHandler) UpdateAddress(c *fiber.Ctx) error {
+	req := new(userRequest.SaveAddress)
+	if err := c.BodyParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+	if err := u.Validator.Struct(req); err != nil {
+		return helpers.RespError(c, u.Logger, helpers.ValidationError(err))
+	}
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.AddressId = c.Params("addressId")
+	resp, err := u.UserUsecaseCommand.UpdateAddress(c.Context(), *req, userId)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Update address success")
+}
+
+func (u UserHttpHandler) DeleteAddress(c *fiber.Ctx) error {
+	req := new(userRequest.AddressBookEntry)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	req.AddressId = c.Params("addressId")
+	if err := u.UserUsecaseCommand.DeleteAddress(c.Context(), *req); err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, nil, "Delete address success")
+}
+
+func (u UserHttpHandler) SetDefaultAddress(c *fiber.Ctx) error {
+	req := new(userRequest.AddressBookEntry)
+	userId, ok := c.Locals("userId").(string)
+	if !ok {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
+
+	req.UserId = userId
+	req.AddressId = c.Params("addressId")
+	resp, err := u.UserUsecaseCommand.SetDefaultAddress(c.Context(), *req)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, err)
+	}
+	return helpers.RespSuccess(c, u.Logger, resp, "Set default address success")
+}
+
 func (u UserHttpHandler) GetProfile(c *fiber.Ctx) error {
 	req := new(userRequest.GetProfile)
+	if err := c.QueryParser(req); err != nil {
+		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
+	}
 	userId, ok := c.Locals("userId").(string)
 	if !ok {
 		return helpers.RespError(c, u.Logger, errors.BadRequest("bad request"))
@@ -113,5 +470,28 @@ func (u UserHttpHandler) GetProfile(c *fiber.Ctx) error {
 	if err != nil {
 		return helpers.RespCustomError(c, u.Logger, err)
 	}
-	return helpers.RespSuccess(c, u.Logger, resp, "Get Profile Success")
+	if resp == nil {
+		return helpers.RespSuccess(c, u.Logger, resp, "Get Profile Success")
+	}
+	c.Set(fiber.HeaderETag, helpers.FormatETag(resp.Version))
+	if req.Fields == "" {
+		return helpers.RespSuccess(c, u.Logger, resp, "Get Profile Success")
+	}
+
+	var fields []string
+	for _, field := range strings.Split(req.Fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	selected, unknown, err := helpers.SelectFields(resp, fields)
+	if err != nil {
+		return helpers.RespCustomError(c, u.Logger, errors.InternalServerError("cannot select profile fields"))
+	}
+	if len(unknown) > 0 {
+		return helpers.RespError(c, u.Logger, errors.ValidationError("Unknown profile field", map[string]string{
+			"fields": fmt.Sprintf("unknown %s", strings.Join(unknown, ", ")),
+		}))
+	}
+	return helpers.RespSuccess(c, u.Logger, selected, "Get Profile Success")
 }
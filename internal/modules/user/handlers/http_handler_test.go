@@ -5,12 +5,17 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"user-service/internal/modules/user/handlers"
 	userRequest "user-service/internal/modules/user/models/request"
 	userResponse "user-service/internal/modules/user/models/response"
 	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
 	mockcert "user-service/mocks/modules/user"
 	mocklog "user-service/mocks/pkg/log"
 	mockredis "user-service/mocks/pkg/redis"
@@ -39,7 +44,9 @@ func (suite *UserHttpHandlerTestSuite) SetupTest() {
 	suite.cUC = new(mockcert.UsecaseCommand)
 	suite.cUQ = new(mockcert.UsecaseQuery)
 	suite.cLog = new(mocklog.Logger)
-	suite.validator = validator.New()
+	suite.cLog.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	suite.cLog.On("Warn", mock.Anything, mock.Anything, mock.Anything)
+	suite.validator = helpers.NewValidator()
 	suite.cRedis = new(mockredis.Collections)
 	suite.handler = &handlers.UserHttpHandler{
 		UserUsecaseCommand: suite.cUC,
@@ -62,20 +69,22 @@ func (suite *UserHttpHandlerTestSuite) TestRegisterUser() {
 	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	reqM := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "123",
+		CityId:               "123",
+		DistrictId:           "123",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	requestBody, _ := json.Marshal(reqM)
 	req := httptest.NewRequest(fiber.MethodPost, "/v1/register", bytes.NewBuffer(requestBody))
@@ -139,20 +148,22 @@ func (suite *UserHttpHandlerTestSuite) TestRegisterUserError() {
 	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	reqM := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "123",
+		CityId:               "123",
+		DistrictId:           "123",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	requestBody, _ := json.Marshal(reqM)
 	req := httptest.NewRequest(fiber.MethodPost, "/v1/register", bytes.NewBuffer(requestBody))
@@ -513,6 +524,57 @@ func (suite *UserHttpHandlerTestSuite) TestGetProfileError() {
 	assert.Nil(suite.T(), err)
 }
 
+func (suite *UserHttpHandlerTestSuite) TestGetProfileErrorWrapped() {
+	suite.cUQ.On("GetProfile", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("get profile: %w", errors.NotFound("User not found")))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/profile", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.GetProfile)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/profile", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusNotFound, resp.StatusCode)
+
+	var body struct {
+		Meta struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"meta"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), fiber.StatusNotFound, body.Meta.Code)
+	assert.Equal(suite.T(), "User not found", body.Meta.Message)
+	suite.cLog.AssertNotCalled(suite.T(), "Error", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetProfileErrorForeign() {
+	suite.cUQ.On("GetProfile", mock.Anything, mock.Anything).Return(nil, http.ErrHandlerTimeout)
+
+	app := fiber.New()
+	app.Get("/v1/profile", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.GetProfile)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/profile", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body struct {
+		Meta struct {
+			Message string `json:"message"`
+		} `json:"meta"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "Internal Server Error", body.Meta.Message)
+	suite.cLog.AssertCalled(suite.T(), "Error", mock.Anything, "audit-log", mock.MatchedBy(func(meta string) bool {
+		return strings.Contains(meta, http.ErrHandlerTimeout.Error())
+	}))
+}
+
 func (suite *UserHttpHandlerTestSuite) TestGetProfileErrorParse() {
 	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
 	ctx.Locals("userId", 12345)
@@ -526,3 +588,862 @@ func (suite *UserHttpHandlerTestSuite) TestGetProfileErrorParse() {
 	err := suite.handler.GetProfile(ctx)
 	assert.Nil(suite.T(), err)
 }
+
+func (suite *UserHttpHandlerTestSuite) TestDeleteAccount() {
+	suite.cUC.On("DeleteAccount", mock.Anything, mock.Anything, "12345").Return(&userResponse.DeleteAccount{
+		UserId: "12345",
+		Status: "pending_deletion",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reqM := userRequest.DeleteAccount{
+		Password: "Password1@",
+	}
+	requestBody, _ := json.Marshal(reqM)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/account")
+	ctx.Request().Header.SetMethod(fiber.MethodDelete)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.DeleteAccount(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDeleteAccountErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reqM := userRequest.DeleteAccount{}
+	requestBody, _ := json.Marshal(reqM)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/account")
+	ctx.Request().Header.SetMethod(fiber.MethodDelete)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.DeleteAccount(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDeleteAccountError() {
+	suite.cUC.On("DeleteAccount", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.BadRequest("Password not match"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reqM := userRequest.DeleteAccount{
+		Password: "Password1@",
+	}
+	requestBody, _ := json.Marshal(reqM)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/account")
+	ctx.Request().Header.SetMethod(fiber.MethodDelete)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.DeleteAccount(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestRequestDataExport() {
+	suite.cUC.On("RequestDataExport", mock.Anything, mock.Anything, "12345").Return(&userResponse.DataExport{
+		ExportId: "export-1",
+		Status:   "pending",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reqM := userRequest.RequestDataExport{
+		Format: "csv",
+	}
+	requestBody, _ := json.Marshal(reqM)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/data-export")
+	ctx.Request().Header.SetMethod(fiber.MethodPost)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.RequestDataExport(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestRequestDataExportErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reqM := userRequest.RequestDataExport{
+		Format: "xml",
+	}
+	requestBody, _ := json.Marshal(reqM)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/data-export")
+	ctx.Request().Header.SetMethod(fiber.MethodPost)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.RequestDataExport(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestRequestDataExportError() {
+	suite.cUC.On("RequestDataExport", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.TooManyRequest("Data export already requested, please try again later"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody, _ := json.Marshal(userRequest.RequestDataExport{})
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/data-export")
+	ctx.Request().Header.SetMethod(fiber.MethodPost)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.RequestDataExport(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusTooManyRequests, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetDataExport() {
+	suite.cUQ.On("GetDataExport", mock.Anything, userRequest.GetDataExport{
+		UserId:   "12345",
+		ExportId: "export-1",
+	}).Return(&userResponse.DataExport{
+		ExportId: "export-1",
+		Status:   "ready",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/data-export/:exportId", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.GetDataExport)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/data-export/export-1", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDownloadDataExport() {
+	suite.cUQ.On("DownloadDataExport", mock.Anything, userRequest.DownloadDataExport{
+		ExportId:  "export-1",
+		Expires:   1700000000,
+		Signature: "abc",
+	}).Return(&userResponse.DataExportFile{
+		FileName: "data-export-export-1.zip",
+		Content:  []byte("archive"),
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/v1/data-export/:exportId/download", suite.handler.DownloadDataExport)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/data-export/export-1/download?expires=1700000000&signature=abc", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "application/zip", resp.Header.Get(fiber.HeaderContentType))
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDownloadDataExportErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/data-export/:exportId/download", suite.handler.DownloadDataExport)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/data-export/export-1/download", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDownloadDataExportError() {
+	suite.cUQ.On("DownloadDataExport", mock.Anything, mock.Anything).Return(nil, errors.ForbiddenError("Invalid download link"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/v1/data-export/:exportId/download", suite.handler.DownloadDataExport)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/v1/data-export/export-1/download?expires=1700000000&signature=abc", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetTermsVersion() {
+	suite.cUQ.On("GetTermsVersion", mock.Anything).Return(&userResponse.TermsVersion{
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetRequestURI("/v1/terms")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetTermsVersion(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetConsents() {
+	suite.cUQ.On("GetConsents", mock.Anything, userRequest.GetConsents{UserId: "12345"}).Return(&userResponse.Consents{
+		TermsVersion: "1.0",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/consents")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetConsents(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdateConsents() {
+	suite.cUC.On("UpdateConsents", mock.Anything, mock.Anything, "12345").Return(&userResponse.Consents{
+		TermsVersion: "1.0",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"marketingEmail":true}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/consents")
+	ctx.Request().Header.SetMethod(fiber.MethodPut)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.UpdateConsents(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdateConsentsError() {
+	suite.cUC.On("UpdateConsents", mock.Anything, mock.Anything, "12345").Return(nil, errors.BadRequest("No consent to update"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/consents")
+	ctx.Request().Header.SetMethod(fiber.MethodPut)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.UpdateConsents(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetMarketingConsent() {
+	suite.cUQ.On("GetMarketingConsent", mock.Anything, userRequest.GetMarketingConsent{
+		UserId:  "12345",
+		Channel: "email",
+	}).Return(&userResponse.MarketingConsent{
+		UserId:  "12345",
+		Channel: "email",
+		Granted: true,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetRequestURI("/v1/internal/consents/marketing?userId=12345&channel=email")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetMarketingConsent(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetMarketingConsentErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetRequestURI("/v1/internal/consents/marketing?userId=12345&channel=push")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetMarketingConsent(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestFindMarketingAudience() {
+	latitude, longitude := -6.2, 106.8
+	suite.cUQ.On("FindMarketingAudience", mock.Anything, userRequest.FindMarketingAudience{
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		RadiusKm:  15,
+		Channel:   "email",
+		Page:      1,
+		Size:      100,
+	}).Return(&userResponse.MarketingAudience{
+		CollectionData: []string{"12345"},
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetRequestURI("/v1/internal/audiences/marketing?lat=-6.2&lng=106.8&radiusKm=15&channel=email&page=1&size=100")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.FindMarketingAudience(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestFindMarketingAudienceErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().SetRequestURI("/v1/internal/audiences/marketing?lat=-6.2&channel=email&page=1&size=100")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.FindMarketingAudience(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetPreferences() {
+	suite.cUQ.On("GetPreferences", mock.Anything, userRequest.GetPreferences{UserId: "12345"}).Return(&userResponse.Preferences{
+		Locale: "id-ID",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/preferences")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetPreferences(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdatePreferences() {
+	suite.cUC.On("UpdatePreferences", mock.Anything, mock.Anything, "12345").Return(&userResponse.Preferences{
+		Locale:   "en-US",
+		Timezone: "America/New_York",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"locale":"en-US","timezone":"America/New_York","favouriteGenres":["Jazz"]}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/preferences")
+	ctx.Request().Header.SetMethod(fiber.MethodPatch)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.UpdatePreferences(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdatePreferencesErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"locale":"not a locale!","timezone":"Mars/Olympus"}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/preferences")
+	ctx.Request().Header.SetMethod(fiber.MethodPatch)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.UpdatePreferences(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+	suite.cUC.AssertNotCalled(suite.T(), "UpdatePreferences", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestPatchUser() {
+	suite.cUC.On("PatchUser", mock.Anything, userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"3"`,
+		Patch:   []byte(`{"fullName":"Alif Septian"}`),
+	}).Return(&userResponse.GetProfile{
+		UserId:  "12345",
+		Version: 4,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile")
+	ctx.Request().Header.SetMethod(fiber.MethodPatch)
+	ctx.Request().Header.SetContentType("application/merge-patch+json")
+	ctx.Request().Header.Set(fiber.HeaderIfMatch, `"3"`)
+	ctx.Request().SetBody([]byte(`{"fullName":"Alif Septian"}`))
+
+	err := suite.handler.PatchUser(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+	assert.Equal(suite.T(), `"4"`, string(ctx.Response().Header.Peek(fiber.HeaderETag)))
+}
+
+func (suite *UserHttpHandlerTestSuite) TestPatchUserWithoutIfMatch() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile")
+	ctx.Request().Header.SetMethod(fiber.MethodPatch)
+	ctx.Request().Header.SetContentType("application/merge-patch+json")
+	ctx.Request().SetBody([]byte(`{"fullName":"Alif Septian"}`))
+
+	err := suite.handler.PatchUser(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusPreconditionRequired, ctx.Response().StatusCode())
+	suite.cUC.AssertNotCalled(suite.T(), "PatchUser", mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestPatchUserPreconditionFailed() {
+	suite.cUC.On("PatchUser", mock.Anything, mock.Anything).Return(nil, errors.PreconditionFailed("Profile has been modified, reload and try again"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile")
+	ctx.Request().Header.SetMethod(fiber.MethodPatch)
+	ctx.Request().Header.SetContentType("application/merge-patch+json")
+	ctx.Request().Header.Set(fiber.HeaderIfMatch, `"2"`)
+	ctx.Request().SetBody([]byte(`{"fullName":"Alif Septian"}`))
+
+	err := suite.handler.PatchUser(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusPreconditionFailed, ctx.Response().StatusCode())
+}
+
+func newAvatarRequest(fieldName string, content []byte) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile(fieldName, "avatar.png")
+	_, _ = part.Write(content)
+	_ = writer.Close()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/profile/avatar", body)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	return req
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUploadAvatar() {
+	suite.cUC.On("UploadAvatar", mock.Anything, userRequest.UploadAvatar{
+		UserId:  "12345",
+		Content: []byte("\x89PNG content"),
+	}).Return(&userResponse.Avatar{
+		AvatarUrl: "http://localhost/media/avatars/12345/1-medium.jpg",
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/profile/avatar", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.UploadAvatar)
+
+	resp, err := app.Test(newAvatarRequest("avatar", []byte("\x89PNG content")))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUploadAvatarMissingFile() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/profile/avatar", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.UploadAvatar)
+
+	resp, err := app.Test(newAvatarRequest("photo", []byte("\x89PNG content")))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	suite.cUC.AssertNotCalled(suite.T(), "UploadAvatar", mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUploadAvatarError() {
+	suite.cUC.On("UploadAvatar", mock.Anything, mock.Anything).Return(nil, errors.AvatarUnsupportedType.New())
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/profile/avatar", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.UploadAvatar)
+
+	resp, err := app.Test(newAvatarRequest("avatar", []byte("%PDF-1.4")))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUploadAvatarErrorLocalized() {
+	suite.cUC.On("UploadAvatar", mock.Anything, mock.Anything).Return(nil, errors.AvatarUnsupportedType.New())
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/profile/avatar", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.UploadAvatar)
+
+	req := newAvatarRequest("avatar", []byte("%PDF-1.4"))
+	req.Header.Set(fiber.HeaderAcceptLanguage, "id-ID, en;q=0.8")
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	assert.Equal(suite.T(), "id", resp.Header.Get(fiber.HeaderContentLanguage))
+
+	var body struct {
+		Meta struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"meta"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), 4006, body.Meta.Code)
+	assert.Equal(suite.T(), "Avatar harus berupa gambar JPEG, PNG atau GIF", body.Meta.Message)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestErrorCatalog() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/api/errors/v1/catalog", helpers.ErrorCatalog(suite.cLog))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/errors/v1/catalog", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []errors.CatalogEntry `json:"data"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), errors.Catalog(), body.Data)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetLogLevel() {
+	leveler := new(mocklog.Leveler)
+	leveler.On("Level").Return("info")
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Get("/api/logger/v1/admin/level", helpers.GetLogLevel(suite.cLog, leveler))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/logger/v1/admin/level", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data helpers.LogLevel `json:"data"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "info", body.Data.Level)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestSetLogLevel() {
+	leveler := new(mocklog.Leveler)
+	leveler.On("Level").Return("info").Once()
+	leveler.On("SetLevel", "debug").Return(nil)
+	leveler.On("Level").Return("debug")
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.cLog.On("Warn", mock.Anything, "Log level changed", nil, mock.Anything, mock.Anything)
+
+	app := fiber.New()
+	app.Put("/api/logger/v1/admin/level", helpers.SetLogLevel(suite.cLog, leveler))
+
+	req := httptest.NewRequest(fiber.MethodPut, "/api/logger/v1/admin/level", strings.NewReader(`{"level":"debug"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data helpers.LogLevel `json:"data"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "debug", body.Data.Level)
+	suite.cLog.AssertCalled(suite.T(), "Warn", mock.Anything, "Log level changed", nil, mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestSetLogLevelErrUnknown() {
+	leveler := new(mocklog.Leveler)
+	leveler.On("Level").Return("info")
+	leveler.On("SetLevel", "verbose").Return(fmt.Errorf(`unknown log level "verbose"`))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/api/logger/v1/admin/level", helpers.SetLogLevel(suite.cLog, leveler))
+
+	req := httptest.NewRequest(fiber.MethodPut, "/api/logger/v1/admin/level", strings.NewReader(`{"level":"verbose"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestSetLogLevelErrValidator() {
+	leveler := new(mocklog.Leveler)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/api/logger/v1/admin/level", helpers.SetLogLevel(suite.cLog, leveler))
+
+	req := httptest.NewRequest(fiber.MethodPut, "/api/logger/v1/admin/level", strings.NewReader(`{}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	leveler.AssertNotCalled(suite.T(), "SetLevel", mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetAddresses() {
+	suite.cUQ.On("GetAddresses", mock.Anything, userRequest.GetAddresses{UserId: "12345"}).Return([]userResponse.Address{
+		{AddressId: "addr-home", Label: "home", IsDefault: true},
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/addresses")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetAddresses(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestCreateAddress() {
+	suite.cUC.On("CreateAddress", mock.Anything, mock.MatchedBy(func(req userRequest.SaveAddress) bool {
+		return req.Label == "office" && req.CountryId == "1"
+	}), "12345").Return(&userResponse.Address{AddressId: "addr-office", Label: "office"}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"label":"office","address":"Jalan kantor","countryId":"1"}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/addresses")
+	ctx.Request().Header.SetMethod(fiber.MethodPost)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.CreateAddress(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+}
+
+func (suite *UserHttpHandlerTestSuite) TestCreateAddressErrValidation() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"label":"office","address":"Jalan kantor","countryId":"100"}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/addresses")
+	ctx.Request().Header.SetMethod(fiber.MethodPost)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.CreateAddress(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+	suite.cUC.AssertNotCalled(suite.T(), "CreateAddress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdateAddress() {
+	suite.cUC.On("UpdateAddress", mock.Anything, mock.MatchedBy(func(req userRequest.SaveAddress) bool {
+		return req.AddressId == "addr-office" && req.Label == "office"
+	}), "12345").Return(&userResponse.Address{AddressId: "addr-office", Label: "office"}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/v1/addresses/:addressId", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.UpdateAddress)
+
+	req := httptest.NewRequest(fiber.MethodPut, "/v1/addresses/addr-office", strings.NewReader(`{"label":"office","address":"Jalan kantor","countryId":"1"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestDeleteAddressNotFound() {
+	suite.cUC.On("DeleteAddress", mock.Anything, userRequest.AddressBookEntry{UserId: "12345", AddressId: "addr-unknown"}).Return(errors.NotFound("Address not found"))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Delete("/v1/addresses/:addressId", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.DeleteAddress)
+
+	req := httptest.NewRequest(fiber.MethodDelete, "/v1/addresses/addr-unknown", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestSetDefaultAddress() {
+	suite.cUC.On("SetDefaultAddress", mock.Anything, userRequest.AddressBookEntry{UserId: "12345", AddressId: "addr-office"}).Return(&userResponse.Address{
+		AddressId: "addr-office",
+		IsDefault: true,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Put("/v1/addresses/:addressId/default", func(c *fiber.Ctx) error {
+		c.Locals("userId", "12345")
+		return c.Next()
+	}, suite.handler.SetDefaultAddress)
+
+	req := httptest.NewRequest(fiber.MethodPut, "/v1/addresses/addr-office/default", nil)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestUpdateUserErrHierarchy() {
+	suite.cUC.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return("", errors.ValidationError("Address hierarchy is not valid", map[string]string{
+		"cityId": "does not match the subdistrict, expected 3201",
+	}))
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	requestBody := []byte(`{"fullName":"FullName","mobileNumber":"+6281281015121","provinceId":"32","cityId":"3171",` +
+		`"districtId":"3201010","subdictrictId":"3201010001","countryId":"100","role":"user"}`)
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile")
+	ctx.Request().Header.SetMethod(fiber.MethodPut)
+	ctx.Request().Header.SetContentType("application/json")
+	ctx.Request().SetBody(requestBody)
+
+	err := suite.handler.UpdateUser(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+	var body struct {
+		Data struct {
+			Errors map[string]string `json:"errors"`
+		} `json:"data"`
+	}
+	assert.Nil(suite.T(), json.Unmarshal(ctx.Response().Body(), &body))
+	assert.Equal(suite.T(), "does not match the subdistrict, expected 3201", body.Data.Errors["cityId"])
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetProfileSelectFields() {
+	suite.cUQ.On("GetProfile", mock.Anything, userRequest.GetProfile{
+		UserId: "12345",
+		Fields: "fullName, addressDetail.city,addressDetail.latitude",
+	}).Return(&userResponse.GetProfile{
+		UserId:   "12345",
+		FullName: "alif",
+		Email:    "alif@gmail.com",
+		AddressDetail: userResponse.ProfileAddress{
+			City:     userResponse.AddressLevel{Id: "3201", Name: "Kabupaten Bogor"},
+			Latitude: "-6.48",
+		},
+		Version: 2,
+	}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile?fields=fullName,%20addressDetail.city,addressDetail.latitude")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetProfile(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusOK, ctx.Response().StatusCode())
+	assert.Equal(suite.T(), `"2"`, string(ctx.Response().Header.Peek(fiber.HeaderETag)))
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.Nil(suite.T(), json.Unmarshal(ctx.Response().Body(), &body))
+	assert.Equal(suite.T(), map[string]interface{}{
+		"fullName": "alif",
+		"addressDetail": map[string]interface{}{
+			"city":     map[string]interface{}{"id": "3201", "name": "Kabupaten Bogor"},
+			"latitude": "-6.48",
+		},
+	}, body.Data)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestGetProfileUnknownField() {
+	suite.cUQ.On("GetProfile", mock.Anything, mock.Anything).Return(&userResponse.GetProfile{UserId: "12345"}, nil)
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := suite.app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Locals("userId", "12345")
+	ctx.Request().SetRequestURI("/v1/profile?fields=fullName,password,addressDetail.street")
+	ctx.Request().Header.SetMethod(fiber.MethodGet)
+
+	err := suite.handler.GetProfile(ctx)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, ctx.Response().StatusCode())
+	assert.Contains(suite.T(), string(ctx.Response().Body()), "unknown password, addressDetail.street")
+}
+
+func (suite *UserHttpHandlerTestSuite) TestLoginUserErrValidationProblem() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/login", suite.handler.Login)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/login", strings.NewReader(`{"password":"secret"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAccept, helpers.ProblemContentType)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(suite.T(), helpers.ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+
+	var body struct {
+		Status int                 `json:"status"`
+		Code   string              `json:"code"`
+		Detail string              `json:"detail"`
+		Errors []errors.FieldError `json:"errors"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), fiber.StatusBadRequest, body.Status)
+	assert.Equal(suite.T(), errors.ErrorCodeValidationFailed, body.Code)
+	assert.Equal(suite.T(), []errors.FieldError{{Field: "email", Rule: "required", Message: "is required"}}, body.Errors)
+	suite.cUC.AssertNotCalled(suite.T(), "LoginUser", mock.Anything, mock.Anything)
+}
+
+func (suite *UserHttpHandlerTestSuite) TestLoginUserErrValidationEnvelope() {
+	suite.cLog.On("Info", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	app := fiber.New()
+	app.Post("/v1/login", suite.handler.Login)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/v1/login", strings.NewReader(`{"email":"a@b.co"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(suite.T(), fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+
+	var body struct {
+		Meta struct {
+			Message string `json:"message"`
+		} `json:"meta"`
+		Data struct {
+			Errors map[string]string `json:"errors"`
+		} `json:"data"`
+	}
+	assert.Nil(suite.T(), json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(suite.T(), "Request is not valid", body.Meta.Message)
+	assert.Equal(suite.T(), map[string]string{"password": "is required"}, body.Data.Errors)
+}
@@ -2,16 +2,24 @@ package entity
 
 import (
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	StatusActive          = `active`
+	StatusPendingDeletion = `pending_deletion`
+	StatusDeleted         = `deleted`
 )
 
 type User struct {
 	UserId       string      `json:"userId" bson:"userId"`
 	FullName     string      `json:"fullName" bson:"fullName"`
-	Email        string      `json:"email" bson:"email"`
-	Password     string      `json:"password" bson:"password"`
-	NIK          string      `json:"nik" bson:"nik"`
-	MobileNumber string      `json:"mobileNumber" bson:"mobileNumber"`
-	Address      string      `json:"address" bson:"address"`
+	Email        string      `json:"email" bson:"email" log:"mask"`
+	Password     string      `json:"password" bson:"password" log:"secret"`
+	NIK          string      `json:"nik" bson:"nik" log:"secret"`
+	MobileNumber string      `json:"mobileNumber" bson:"mobileNumber" log:"mask"`
+	Address      string      `json:"address" bson:"address" log:"secret"`
 	Subdistrict  Subdistrict `json:"subdistrict" bson:"subdistrict"`
 	Country      Country     `json:"country" bson:"country"`
 	RtRw         string      `json:"rtrw" bson:"rtrw"`
@@ -20,6 +28,103 @@ type User struct {
 	LoginAt      time.Time   `json:"loginAt" bson:"loginAt"`
 	CreatedAt    time.Time   `json:"createdAt" bson:"createdAt"`
 	UpdatedAt    time.Time   `json:"updatedAt" bson:"updatedAt"`
+	Deletion     Deletion    `json:"deletion" bson:"deletion"`
+	Preferences  Preferences `json:"preferences" bson:"preferences"`
+	Avatar       Avatar      `json:"avatar" bson:"avatar"`
+	// Version is bumped on every profile change and exposed as the profile ETag
+	Version int64 `json:"version" bson:"version"`
+	// PendingConsents are given at registration and kept on the temp user until the email is verified
+	PendingConsents []Consent `json:"-" bson:"pendingConsents,omitempty"`
+}
+
+// Avatar holds the public urls of the resized avatar variants
+type Avatar struct {
+	Variants map[string]string `json:"variants" bson:"variants"`
+	// Keys are the blob store keys, removed when the avatar is replaced
+	Keys      []string  `json:"-" bson:"keys"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Preferences drives concert recommendations and notifications
+type Preferences struct {
+	Locale               string               `json:"locale" bson:"locale"`
+	Timezone             string               `json:"timezone" bson:"timezone"`
+	NotificationChannels NotificationChannels `json:"notificationChannels" bson:"notificationChannels"`
+	FavouriteGenres      []string             `json:"favouriteGenres" bson:"favouriteGenres"`
+	FavouriteArtists     []string             `json:"favouriteArtists" bson:"favouriteArtists"`
+	UpdatedAt            time.Time            `json:"updatedAt" bson:"updatedAt"`
+}
+
+type NotificationChannels struct {
+	Email bool `json:"email" bson:"email"`
+	Sms   bool `json:"sms" bson:"sms"`
+	Push  bool `json:"push" bson:"push"`
+}
+
+// Deletion holds the state of a self-service account deletion request
+type Deletion struct {
+	RequestedAt time.Time `json:"requestedAt" bson:"requestedAt"`
+	ScheduledAt time.Time `json:"scheduledAt" bson:"scheduledAt"`
+	DeletedAt   time.Time `json:"deletedAt" bson:"deletedAt"`
+}
+
+const (
+	DataExportStatusPending = `pending`
+	DataExportStatusReady   = `ready`
+	DataExportStatusFailed  = `failed`
+)
+
+// DataExport is a personal data export request, the generated archive is stored with it
+type DataExport struct {
+	ExportId    string    `json:"exportId" bson:"exportId"`
+	UserId      string    `json:"userId" bson:"userId"`
+	Format      string    `json:"format" bson:"format"`
+	Status      string    `json:"status" bson:"status"`
+	Archive     []byte    `json:"-" bson:"archive"`
+	RequestedAt time.Time `json:"requestedAt" bson:"requestedAt"`
+	CompletedAt time.Time `json:"completedAt" bson:"completedAt"`
+	ExpiredAt   time.Time `json:"expiredAt" bson:"expiredAt"`
+}
+
+const (
+	ConsentPurposeTerms          = `terms`
+	ConsentPurposePrivacyPolicy  = `privacy_policy`
+	ConsentPurposeMarketingEmail = `marketing_email`
+	ConsentPurposeMarketingSms   = `marketing_sms`
+)
+
+const (
+	ConsentSourceRegistration = `registration`
+	ConsentSourceLogin        = `login`
+	ConsentSourceProfile      = `profile`
+	ConsentSourceDeletion     = `account_deletion`
+)
+
+// Consent is the latest decision of a user for one purpose
+type Consent struct {
+	UserId    string    `json:"userId" bson:"userId"`
+	Purpose   string    `json:"purpose" bson:"purpose"`
+	Granted   bool      `json:"granted" bson:"granted"`
+	Version   string    `json:"version" bson:"version"`
+	Source    string    `json:"source" bson:"source"`
+	GrantedAt time.Time `json:"grantedAt" bson:"grantedAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Audience is one page of a marketing audience, Total holds a single count of the whole audience
+type Audience struct {
+	Users []AudienceMember `bson:"users"`
+	Total []AudienceTotal  `bson:"total"`
+}
+
+// AudienceMember carries its _id when read by cursor, it positions the next page
+type AudienceMember struct {
+	Id     bson.RawValue `bson:"_id,omitempty"`
+	UserId string        `bson:"userId"`
+}
+
+type AudienceTotal struct {
+	Count int64 `bson:"count"`
 }
 
 // Move to domain address
@@ -32,6 +137,7 @@ type Subdistrict struct {
 	CityName     string `json:"cityName" bson:"cityName"`
 	ProvinceId   string `json:"provinceId" bson:"provinceId"`
 	ProvinceName string `json:"provinceName" bson:"provinceName"`
+	PostalCode   string `json:"postalCode" bson:"postalCode"`
 }
 
 type Country struct {
@@ -43,4 +149,31 @@ type Country struct {
 	ContinentName string `json:"continentName" bson:"continentName"`
 	Latitude      string `json:"latitude" bson:"latitude"`
 	Longitude     string `json:"longitude" bson:"longitude"`
+	// Location is the validated Latitude and Longitude, indexed for geo queries. A nil Location is
+	// written as null so clearing the coordinates also clears the stored point.
+	Location *GeoPoint `json:"location,omitempty" bson:"location"`
+}
+
+// GeoPoint is a GeoJSON point, Coordinates holds the longitude then the latitude
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// AddressBookEntry is one labelled address of a user, the default entry mirrors
+// the address embedded in User
+type AddressBookEntry struct {
+	AddressId     string      `json:"addressId" bson:"addressId"`
+	UserId        string      `json:"userId" bson:"userId"`
+	Label         string      `json:"label" bson:"label"`
+	RecipientName string      `json:"recipientName" bson:"recipientName"`
+	MobileNumber  string      `json:"mobileNumber" bson:"mobileNumber" log:"mask"`
+	Address       string      `json:"address" bson:"address" log:"secret"`
+	Subdistrict   Subdistrict `json:"subdistrict" bson:"subdistrict"`
+	Country       Country     `json:"country" bson:"country"`
+	RtRw          string      `json:"rtrw" bson:"rtrw"`
+	IsDefault     bool        `json:"isDefault" bson:"isDefault"`
+	Deleted       bool        `json:"-" bson:"deleted"`
+	CreatedAt     time.Time   `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt" bson:"updatedAt"`
 }
@@ -14,27 +14,33 @@ var MapOfRole = map[string]string{
 
 type RegisterUser struct {
 	FullName      string `json:"fullName" validate:"required"`
-	Email         string `json:"email" validate:"required,min=1,max=50"`
-	Password      string `json:"password" validate:"required,min=8,max=20"`
-	NIK           string `json:"nik" validate:"required"`
-	MobileNumber  string `json:"mobileNumber" validate:"required"`
-	Address       string `json:"address"`
+	Email         string `json:"email" validate:"required,min=1,max=50" log:"mask"`
+	Password      string `json:"password" validate:"required,min=8,max=20" log:"secret"`
+	NIK           string `json:"nik" validate:"required" log:"secret"`
+	MobileNumber  string `json:"mobileNumber" validate:"required" log:"mask"`
+	Address       string `json:"address" log:"secret"`
 	ProvinceId    string `json:"provinceId" validate:"required_if=CountryId 100"`
 	CityId        string `json:"cityId" validate:"required_if=CountryId 100"`
 	DistrictId    string `json:"districtId" validate:"required_if=CountryId 100"`
 	SubdictrictId string `json:"subdictrictId" validate:"required_if=CountryId 100"`
+	PostalCode    string `json:"postalCode" validate:"omitempty,numeric,len=5"`
 	CountryId     string `json:"countryId" validate:"required"`
 	Latitude      string `json:"latitude"`
 	Longitude     string `json:"longitude"`
 	RtRw          string `json:"rtRw"`
 	Role          string `json:"role" validate:"required"`
-	KKNumber      string `json:"kkNumber"`
+	KKNumber      string `json:"kkNumber" log:"secret"`
+	// Versions of the documents the user accepted on the registration form
+	TermsVersion         string `json:"termsVersion" validate:"required"`
+	PrivacyPolicyVersion string `json:"privacyPolicyVersion" validate:"required"`
+	MarketingEmail       bool   `json:"marketingEmail"`
+	MarketingSms         bool   `json:"marketingSms"`
 }
 
 type UpdateUser struct {
 	FullName      string `json:"fullName" validate:"required"`
-	MobileNumber  string `json:"mobileNumber" validate:"required"`
-	Address       string `json:"address"`
+	MobileNumber  string `json:"mobileNumber" validate:"required" log:"mask"`
+	Address       string `json:"address" log:"secret"`
 	ProvinceId    string `json:"provinceId" validate:"required"`
 	CityId        string `json:"cityId" validate:"required"`
 	DistrictId    string `json:"districtId" validate:"required"`
@@ -46,16 +52,139 @@ type UpdateUser struct {
 	Longitude     string `json:"longitude"`
 }
 
+// PatchUser carries a JSON merge patch (RFC 7396) against ProfileDocument
+type PatchUser struct {
+	UserId  string
+	IfMatch string
+	Patch   []byte
+}
+
+// ProfileDocument is the patchable view of a profile
+type ProfileDocument struct {
+	FullName      string `json:"fullName"`
+	MobileNumber  string `json:"mobileNumber" log:"mask"`
+	Address       string `json:"address" log:"secret"`
+	SubdictrictId string `json:"subdictrictId"`
+	CountryId     string `json:"countryId"`
+	RtRw          string `json:"rtRw"`
+	Latitude      string `json:"latitude"`
+	Longitude     string `json:"longitude"`
+}
+
 type VerifyRegisterUser struct {
-	Email string `json:"email" validate:"required,min=1,max=50"`
-	Otp   string `json:"otpNumber" validate:"required"`
+	Email string `json:"email" validate:"required,min=1,max=50" log:"mask"`
+	Otp   string `json:"otpNumber" validate:"required" log:"secret"`
 }
 
 type LoginUser struct {
-	Email    string `json:"email" validate:"required,min=1,max=50"`
-	Password string `json:"password" validate:"required"`
+	Email    string `json:"email" validate:"required,min=1,max=50" log:"mask"`
+	Password string `json:"password" validate:"required" log:"secret"`
+	// Filled when the user re-accepts updated terms on login
+	TermsVersion         string `json:"termsVersion"`
+	PrivacyPolicyVersion string `json:"privacyPolicyVersion"`
+}
+
+type DeleteAccount struct {
+	Password string `json:"password" validate:"required" log:"secret"`
+}
+
+type RequestDataExport struct {
+	Format string `json:"format" validate:"omitempty,oneof=json csv"`
+}
+
+type GetDataExport struct {
+	UserId   string
+	ExportId string
+}
+
+type DownloadDataExport struct {
+	ExportId  string
+	Expires   int64  `query:"expires" validate:"required"`
+	Signature string `query:"signature" validate:"required"`
+}
+
+type UpdateConsents struct {
+	TermsVersion         string `json:"termsVersion"`
+	PrivacyPolicyVersion string `json:"privacyPolicyVersion"`
+	MarketingEmail       *bool  `json:"marketingEmail"`
+	MarketingSms         *bool  `json:"marketingSms"`
+}
+
+type GetConsents struct {
+	UserId string
+}
+
+type GetMarketingConsent struct {
+	UserId  string `query:"userId" validate:"required"`
+	Channel string `query:"channel" validate:"required,oneof=email sms"`
+}
+
+// FindMarketingAudience selects the users to notify by exactly one of a radius around a point, a city,
+// a province or a country. Without a page the audience is read by cursor and only counted on request.
+type FindMarketingAudience struct {
+	Latitude   *float64 `query:"lat" validate:"required_with=Longitude RadiusKm,omitempty,latitude"`
+	Longitude  *float64 `query:"lng" validate:"required_with=Latitude RadiusKm,omitempty,longitude"`
+	RadiusKm   float64  `query:"radiusKm" validate:"required_with=Latitude Longitude,omitempty,gt=0,lte=500"`
+	CityId     string   `query:"cityId"`
+	ProvinceId string   `query:"provinceId"`
+	CountryId  string   `query:"countryId" validate:"omitempty,alphanum,max=3"`
+	Channel    string   `query:"channel" validate:"required,oneof=email sms"`
+	Page       int64    `query:"page" validate:"omitempty,min=1,excluded_with=Cursor"`
+	Size       int64    `query:"size" validate:"required,min=1,max=1000"`
+	Cursor     string   `query:"cursor" validate:"omitempty,max=512"`
+	Count      bool     `query:"count"`
+}
+
+type NotificationChannels struct {
+	Email *bool `json:"email"`
+	Sms   *bool `json:"sms"`
+	Push  *bool `json:"push"`
+}
+
+// UpdatePreferences only changes the fields present in the body
+type UpdatePreferences struct {
+	Locale               *string               `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Timezone             *string               `json:"timezone" validate:"omitempty,timezone"`
+	NotificationChannels *NotificationChannels `json:"notificationChannels"`
+	FavouriteGenres      []string              `json:"favouriteGenres" validate:"omitempty,max=20,dive,required,max=50"`
+	FavouriteArtists     []string              `json:"favouriteArtists" validate:"omitempty,max=50,dive,required,max=100"`
+}
+
+type UploadAvatar struct {
+	UserId  string
+	Content []byte
+}
+
+type GetPreferences struct {
+	UserId string
 }
 
 type GetProfile struct {
 	UserId string
+	// Fields is a comma separated list of the profile fields to return, nested fields use dots
+	Fields string `query:"fields"`
+}
+
+// SaveAddress creates or replaces an address book entry
+type SaveAddress struct {
+	AddressId     string
+	Label         string `json:"label" validate:"required,max=30"`
+	RecipientName string `json:"recipientName" validate:"max=100"`
+	MobileNumber  string `json:"mobileNumber" log:"mask"`
+	Address       string `json:"address" validate:"required,max=255" log:"secret"`
+	SubdictrictId string `json:"subdictrictId" validate:"required_if=CountryId 100"`
+	CountryId     string `json:"countryId" validate:"required"`
+	RtRw          string `json:"rtRw"`
+	Latitude      string `json:"latitude"`
+	Longitude     string `json:"longitude"`
+	IsDefault     bool   `json:"isDefault"`
+}
+
+type GetAddresses struct {
+	UserId string
+}
+
+type AddressBookEntry struct {
+	UserId    string
+	AddressId string
 }
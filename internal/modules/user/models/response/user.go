@@ -1,33 +1,228 @@
 package response
 
+import (
+	"user-service/internal/pkg/constants"
+)
+
 type RegisterUser struct {
-	Email string `json:"email"`
+	Email string `json:"email" log:"mask"`
 }
 
 type VerifyRegister struct {
-	AuthToken    string `json:"authToken"`
-	RefreshToken string `json:"refreshToken"`
+	AuthToken    string `json:"authToken" log:"secret"`
+	RefreshToken string `json:"refreshToken" log:"secret"`
 	ExpiredAt    string `json:"expiredAt"`
 }
 
 type LoginUserResp struct {
-	AuthToken    string `json:"authToken" bson:"authToken"`
-	RefreshToken string `json:"refreshToken" bson:"refreshToken"`
+	AuthToken    string `json:"authToken" bson:"authToken" log:"secret"`
+	RefreshToken string `json:"refreshToken" bson:"refreshToken" log:"secret"`
 	ExpiredAt    string `json:"expiredAt" bson:"password"`
 }
 
 type GetProfile struct {
-	UserId        string `json:"userId"`
-	FullName      string `json:"fullName"`
-	Email         string `json:"email"`
-	NIK           string `json:"nik"`
-	MobileNumber  string `json:"mobileNumber"`
-	Address       string `json:"address"`
-	CountryCode   string `json:"countryCode"`
-	CountryName   string `json:"countryName"`
-	ContinentName string `json:"continentName" bson:"continentName"`
-	Latitude      string `json:"latitude" bson:"latitude"`
-	Longitude     string `json:"longitude" bson:"longitude"`
+	UserId         string            `json:"userId"`
+	FullName       string            `json:"fullName"`
+	Email          string            `json:"email" log:"mask"`
+	NIK            string            `json:"nik" log:"secret"`
+	MobileNumber   string            `json:"mobileNumber" log:"mask"`
+	Address        string            `json:"address" log:"secret"`
+	CountryCode    string            `json:"countryCode"`
+	CountryName    string            `json:"countryName"`
+	ContinentName  string            `json:"continentName" bson:"continentName"`
+	Latitude       string            `json:"latitude" bson:"latitude"`
+	Longitude      string            `json:"longitude" bson:"longitude"`
+	RtRw           string            `json:"rtRw"`
+	AddressDetail  ProfileAddress    `json:"addressDetail"`
+	Role           string            `json:"role"`
+	Preferences    Preferences       `json:"preferences"`
+	AvatarUrl      string            `json:"avatarUrl"`
+	AvatarVariants map[string]string `json:"avatarVariants,omitempty"`
+	Version        int64             `json:"version"`
+}
+
+// ProfileAddress is the full address of a profile with its resolved hierarchy
+type ProfileAddress struct {
+	Address     string         `json:"address" log:"secret"`
+	RtRw        string         `json:"rtRw"`
+	Subdistrict AddressLevel   `json:"subdistrict"`
+	District    AddressLevel   `json:"district"`
+	City        AddressLevel   `json:"city"`
+	Province    AddressLevel   `json:"province"`
+	PostalCode  string         `json:"postalCode"`
+	Country     AddressCountry `json:"country"`
+	Latitude    string         `json:"latitude"`
+	Longitude   string         `json:"longitude"`
+}
+
+type AddressLevel struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type AddressCountry struct {
+	Id            int    `json:"id"`
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	ContinentName string `json:"continentName"`
+}
+
+type Avatar struct {
+	AvatarUrl string            `json:"avatarUrl"`
+	Variants  map[string]string `json:"variants"`
+}
+
+type Preferences struct {
+	Locale               string               `json:"locale"`
+	Timezone             string               `json:"timezone"`
+	NotificationChannels NotificationChannels `json:"notificationChannels"`
+	FavouriteGenres      []string             `json:"favouriteGenres"`
+	FavouriteArtists     []string             `json:"favouriteArtists"`
+	UpdatedAt            string               `json:"updatedAt,omitempty"`
+}
+
+type NotificationChannels struct {
+	Email bool `json:"email"`
+	Sms   bool `json:"sms"`
+	Push  bool `json:"push"`
+}
+
+type DeleteAccount struct {
+	UserId      string `json:"userId"`
+	Status      string `json:"status"`
+	ScheduledAt string `json:"scheduledAt"`
+}
+
+type DataExport struct {
+	ExportId    string `json:"exportId"`
+	Status      string `json:"status"`
+	Format      string `json:"format"`
+	RequestedAt string `json:"requestedAt"`
+	CompletedAt string `json:"completedAt,omitempty"`
+	DownloadUrl string `json:"downloadUrl,omitempty"`
+	ExpiredAt   string `json:"expiredAt,omitempty"`
+}
+
+type DataExportFile struct {
+	FileName string
+	Content  []byte
+}
+
+// DataExportArchive is everything the service holds about a user
+type DataExportArchive struct {
+	GeneratedAt  string                 `json:"generatedAt"`
+	Profile      DataExportProfile      `json:"profile"`
+	Address      DataExportAddress      `json:"address"`
+	AddressBook  []Address              `json:"addressBook"`
+	Preferences  Preferences            `json:"preferences"`
+	Consents     []DataExportConsent    `json:"consents"`
+	Sessions     []DataExportSession    `json:"sessions"`
+	AuditEntries []DataExportAuditEntry `json:"auditEntries"`
+}
+
+type DataExportProfile struct {
+	UserId       string `json:"userId"`
+	FullName     string `json:"fullName"`
+	Email        string `json:"email" log:"mask"`
+	NIK          string `json:"nik" log:"secret"`
+	MobileNumber string `json:"mobileNumber" log:"mask"`
+	Role         string `json:"role"`
+	Status       string `json:"status"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+type DataExportAddress struct {
+	Address       string `json:"address" log:"secret"`
 	RtRw          string `json:"rtRw"`
-	Role          string `json:"role"`
+	SubdistrictId string `json:"subdistrictId"`
+	Subdistrict   string `json:"subdistrict"`
+	DistrictId    string `json:"districtId"`
+	District      string `json:"district"`
+	CityId        string `json:"cityId"`
+	City          string `json:"city"`
+	ProvinceId    string `json:"provinceId"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postalCode"`
+	CountryCode   string `json:"countryCode"`
+	Country       string `json:"country"`
+	Continent     string `json:"continent"`
+	Latitude      string `json:"latitude"`
+	Longitude     string `json:"longitude"`
+}
+
+type DataExportConsent struct {
+	Purpose   string `json:"purpose"`
+	Granted   bool   `json:"granted"`
+	Version   string `json:"version"`
+	Source    string `json:"source"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type DataExportSession struct {
+	LoginAt string `json:"loginAt"`
+}
+
+type DataExportAuditEntry struct {
+	Action string `json:"action"`
+	At     string `json:"at"`
+}
+
+type TermsVersion struct {
+	TermsVersion         string `json:"termsVersion"`
+	PrivacyPolicyVersion string `json:"privacyPolicyVersion"`
+}
+
+type Consent struct {
+	Purpose   string `json:"purpose"`
+	Granted   bool   `json:"granted"`
+	Version   string `json:"version"`
+	Source    string `json:"source"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type Consents struct {
+	TermsVersion       string    `json:"termsVersion"`
+	PrivacyVersion     string    `json:"privacyPolicyVersion"`
+	AcceptanceRequired bool      `json:"acceptanceRequired"`
+	Consents           []Consent `json:"consents"`
+}
+
+type MarketingConsent struct {
+	UserId    string `json:"userId"`
+	Channel   string `json:"channel"`
+	Granted   bool   `json:"granted"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// MarketingAudience is one page of the ids of the users matching an audience
+type MarketingAudience struct {
+	CollectionData []string
+	MetaData       constants.MetaData
+}
+
+type Address struct {
+	AddressId       string `json:"addressId"`
+	Label           string `json:"label"`
+	RecipientName   string `json:"recipientName"`
+	MobileNumber    string `json:"mobileNumber" log:"mask"`
+	Address         string `json:"address" log:"secret"`
+	RtRw            string `json:"rtRw"`
+	SubdistrictId   string `json:"subdistrictId"`
+	SubdistrictName string `json:"subdistrictName"`
+	DistrictId      string `json:"districtId"`
+	DistrictName    string `json:"districtName"`
+	CityId          string `json:"cityId"`
+	CityName        string `json:"cityName"`
+	ProvinceId      string `json:"provinceId"`
+	ProvinceName    string `json:"provinceName"`
+	PostalCode      string `json:"postalCode"`
+	CountryId       int    `json:"countryId"`
+	CountryCode     string `json:"countryCode"`
+	CountryName     string `json:"countryName"`
+	Latitude        string `json:"latitude"`
+	Longitude       string `json:"longitude"`
+	IsDefault       bool   `json:"isDefault"`
+	CreatedAt       string `json:"createdAt"`
+	UpdatedAt       string `json:"updatedAt"`
 }
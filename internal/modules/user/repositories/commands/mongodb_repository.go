@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"time"
 	user "user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	"user-service/internal/pkg/databases/mongodb"
@@ -58,3 +59,155 @@ func (c commandMongodbRepository) UpsertOneUser(ctx context.Context, user userEn
 
 	return output
 }
+
+// UpdateOneUserDueForDeletion only writes the user while its deletion is still pending and due at before,
+// the result Count is zero when the deletion was cancelled in the meantime
+func (c commandMongodbRepository) UpdateOneUserDueForDeletion(ctx context.Context, user userEntity.User, before time.Time) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpdateOne(mongodb.UpdateOne{
+			CollectionName: "users",
+			Document:       user,
+			Filter: bson.M{
+				"userId":               user.UserId,
+				"status":               userEntity.StatusPendingDeletion,
+				"deletion.scheduledAt": bson.M{"$lte": before},
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneDataExport(ctx context.Context, dataExport userEntity.DataExport) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "users-data-export",
+			Document:       dataExport,
+			Filter: bson.M{
+				"exportId": dataExport.ExportId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneConsent(ctx context.Context, consent userEntity.Consent) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "users-consent",
+			Document:       consent,
+			Filter: bson.M{
+				"userId":  consent.UserId,
+				"purpose": consent.Purpose,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// UpdateOneUserWithVersion only updates the user while the stored version still equals version,
+// the result Count is zero when another write got there first
+func (c commandMongodbRepository) UpdateOneUserWithVersion(ctx context.Context, user userEntity.User, version int64) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		filter := bson.M{
+			"userId":  user.UserId,
+			"version": version,
+		}
+		// Profiles stored before versioning have no version field
+		if version == 0 {
+			filter["version"] = bson.M{"$in": bson.A{0, nil}}
+		}
+		resp := <-c.mongoDb.UpdateOne(mongodb.UpdateOne{
+			CollectionName: "users",
+			Document:       user,
+			Filter:         filter,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (c commandMongodbRepository) UpsertOneAddress(ctx context.Context, address userEntity.AddressBookEntry) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpsertOne(mongodb.UpdateOne{
+			CollectionName: "users-address",
+			Document:       address,
+			Filter: bson.M{
+				"addressId": address.AddressId,
+				"userId":    address.UserId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// EraseAddressesByUserId blanks every saved address of the user, deleted ones included, and marks them deleted
+func (c commandMongodbRepository) EraseAddressesByUserId(ctx context.Context, userId string, erasedAt time.Time) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.UpdateMany(mongodb.UpdateOne{
+			CollectionName: "users-address",
+			Document: bson.M{
+				"label":         "",
+				"recipientName": "",
+				"mobileNumber":  "",
+				"address":       "",
+				"subdistrict":   userEntity.Subdistrict{},
+				"country":       userEntity.Country{},
+				"rtrw":          "",
+				"isDefault":     false,
+				"deleted":       true,
+				"updatedAt":     erasedAt,
+			},
+			Filter: bson.M{
+				"userId": userId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// DeleteDataExportsByUserId removes every data export of the user together with its archive
+func (c commandMongodbRepository) DeleteDataExportsByUserId(ctx context.Context, userId string) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-c.mongoDb.DeleteMany(mongodb.DeleteMany{
+			CollectionName: "users-data-export",
+			Filter: bson.M{
+				"userId": userId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
@@ -3,9 +3,11 @@ package commands_test
 import (
 	"context"
 	"testing"
+	"time"
 	"user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	mongoRC "user-service/internal/modules/user/repositories/commands"
+	"user-service/internal/pkg/databases/mongodb"
 	"user-service/internal/pkg/helpers"
 	mocks "user-service/mocks/pkg/databases/mongodb"
 	mocklog "user-service/mocks/pkg/log"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type CommandTestSuite struct {
@@ -90,3 +93,193 @@ func (suite *CommandTestSuite) TestUpsertOneUser() {
 	// Assert UpsertOne
 	suite.mockMongodb.AssertCalled(suite.T(), "UpsertOne", mock.Anything, mock.Anything)
 }
+
+func (suite *CommandTestSuite) TestUpdateOneUserDueForDeletion() {
+	testUser := userEntity.User{
+		UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+	}
+	before := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	expectedResult := make(chan helpers.Result)
+	// Mock UpdateOne, only a deletion still pending and due is written
+	suite.mockMongodb.On("UpdateOne", mock.MatchedBy(func(payload mongodb.UpdateOne) bool {
+		filter := payload.Filter.(bson.M)
+		return filter["userId"] == testUser.UserId && filter["status"] == userEntity.StatusPendingDeletion &&
+			assert.ObjectsAreEqual(bson.M{"$lte": before}, filter["deletion.scheduledAt"])
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.UpdateOneUserDueForDeletion(suite.ctx, testUser, before)
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil, Count: 1}
+		close(expectedResult)
+	}()
+
+	// Assert
+	assert.Equal(suite.T(), int64(1), (<-result).Count)
+}
+
+func (suite *CommandTestSuite) TestEraseAddressesByUserId() {
+	expectedResult := make(chan helpers.Result)
+	// Mock UpdateMany, deleted addresses are blanked too
+	suite.mockMongodb.On("UpdateMany", mock.MatchedBy(func(payload mongodb.UpdateOne) bool {
+		document := payload.Document.(bson.M)
+		return payload.CollectionName == "users-address" &&
+			assert.ObjectsAreEqual(bson.M{"userId": "12345"}, payload.Filter) &&
+			document["address"] == "" && document["recipientName"] == "" && document["mobileNumber"] == "" &&
+			document["deleted"] == true
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.EraseAddressesByUserId(suite.ctx, "12345", time.Now())
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil, Count: 3}
+		close(expectedResult)
+	}()
+
+	// Assert
+	assert.Equal(suite.T(), int64(3), (<-result).Count)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneDataExport() {
+	testDataExport := userEntity.DataExport{
+		ExportId: "export-1",
+	}
+
+	// Mock UpsertOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpsertOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.UpsertOneDataExport(suite.ctx, testDataExport)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert UpsertOne
+	suite.mockMongodb.AssertCalled(suite.T(), "UpsertOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneConsent() {
+	testConsent := userEntity.Consent{
+		UserId:  "userId",
+		Purpose: userEntity.ConsentPurposeTerms,
+	}
+
+	// Mock UpsertOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpsertOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.UpsertOneConsent(suite.ctx, testConsent)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert UpsertOne
+	suite.mockMongodb.AssertCalled(suite.T(), "UpsertOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestUpdateOneUserWithVersion() {
+	testUser := userEntity.User{
+		UserId:  "userId",
+		Version: 1,
+	}
+
+	// Mock UpdateOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpdateOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.UpdateOneUserWithVersion(suite.ctx, testUser, 0)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil, Count: 1}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert UpdateOne
+	suite.mockMongodb.AssertCalled(suite.T(), "UpdateOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestUpsertOneAddress() {
+	testAddress := userEntity.AddressBookEntry{
+		AddressId: "addressId",
+		UserId:    "userId",
+		Label:     "home",
+	}
+
+	// Mock UpsertOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("UpsertOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.UpsertOneAddress(suite.ctx, testAddress)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert UpsertOne
+	suite.mockMongodb.AssertCalled(suite.T(), "UpsertOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestDeleteDataExportsByUserId() {
+	// Mock DeleteMany
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("DeleteMany", mongodb.DeleteMany{
+		CollectionName: "users-data-export",
+		Filter:         bson.M{"userId": "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980"},
+	}, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.DeleteDataExportsByUserId(suite.ctx, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "Success delete data", Count: 2}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	resp := <-result
+	assert.Equal(suite.T(), int64(2), resp.Count)
+
+	// Assert DeleteMany
+	suite.mockMongodb.AssertCalled(suite.T(), "DeleteMany", mock.Anything, mock.Anything)
+}
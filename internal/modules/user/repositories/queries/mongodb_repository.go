@@ -2,8 +2,12 @@ package queries
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 	user "user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
+	userRequest "user-service/internal/modules/user/models/request"
 	"user-service/internal/pkg/databases/mongodb"
 	wrapper "user-service/internal/pkg/helpers"
 	"user-service/internal/pkg/log"
@@ -79,3 +83,365 @@ func (q queryMongodbRepository) FindOneByEmailUserTemp(ctx context.Context, emai
 
 	return output
 }
+
+func (q queryMongodbRepository) FindUsersDueForDeletion(ctx context.Context, before time.Time, size int64) <-chan wrapper.Result {
+	var users []userEntity.User
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &users,
+			CollectionName: "users",
+			Filter: bson.M{
+				"status":               userEntity.StatusPendingDeletion,
+				"deletion.scheduledAt": bson.M{"$lte": before},
+			},
+			Sort: &mongodb.Sort{
+				FieldName: "deletion.scheduledAt",
+				By:        mongodb.SortAscending,
+			},
+			Page: 1,
+			Size: size,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneDataExport(ctx context.Context, exportId string) <-chan wrapper.Result {
+	var dataExport userEntity.DataExport
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &dataExport,
+			CollectionName: "users-data-export",
+			Filter: bson.M{
+				"exportId": exportId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindDataExportsByStatus(ctx context.Context, status string, size int64) <-chan wrapper.Result {
+	var dataExports []userEntity.DataExport
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &dataExports,
+			CollectionName: "users-data-export",
+			Filter: bson.M{
+				"status": status,
+			},
+			Sort: &mongodb.Sort{
+				FieldName: "requestedAt",
+				By:        mongodb.SortAscending,
+			},
+			Page: 1,
+			Size: size,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindDataExportsByUserId(ctx context.Context, userId string) <-chan wrapper.Result {
+	var dataExports []userEntity.DataExport
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &dataExports,
+			CollectionName: "users-data-export",
+			Filter: bson.M{
+				"userId": userId,
+			},
+			Sort: &mongodb.Sort{
+				FieldName: "requestedAt",
+				By:        mongodb.SortDescending,
+			},
+			Page: 1,
+			Size: 100,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindConsentsByUserId(ctx context.Context, userId string) <-chan wrapper.Result {
+	var consents []userEntity.Consent
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &consents,
+			CollectionName: "users-consent",
+			Filter: bson.M{
+				"userId": userId,
+			},
+			Sort: &mongodb.Sort{
+				FieldName: "purpose",
+				By:        mongodb.SortAscending,
+			},
+			Page: 1,
+			Size: 20,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneConsent(ctx context.Context, userId string, purpose string) <-chan wrapper.Result {
+	var consent userEntity.Consent
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &consent,
+			CollectionName: "users-consent",
+			Filter: bson.M{
+				"userId":  userId,
+				"purpose": purpose,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// earthRadiusKm converts a radius to the radians $centerSphere expects
+const earthRadiusKm = 6378.1
+
+// FindMarketingAudience pages through the active users inside the audience who granted the marketing purpose,
+// the location and hierarchy filters run on the users indexes before the consents are joined
+func (q queryMongodbRepository) FindMarketingAudience(ctx context.Context, payload userRequest.FindMarketingAudience, purpose string) <-chan wrapper.Result {
+	var audience []userEntity.Audience
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+
+		filter := bson.M{
+			"status": userEntity.StatusActive,
+		}
+		switch {
+		case payload.Latitude != nil && payload.Longitude != nil:
+			filter["country.location"] = bson.M{"$geoWithin": bson.M{
+				"$centerSphere": bson.A{bson.A{*payload.Longitude, *payload.Latitude}, payload.RadiusKm / earthRadiusKm},
+			}}
+		case payload.CityId != "":
+			filter["subdistrict.cityId"] = payload.CityId
+		case payload.ProvinceId != "":
+			filter["subdistrict.provinceId"] = payload.ProvinceId
+		case payload.CountryId != "":
+			// Users keep the numeric id and the alpha-2 code of their country
+			if countryId, err := strconv.Atoi(payload.CountryId); err == nil {
+				filter["country.id"] = countryId
+			} else {
+				filter["country.code"] = strings.ToUpper(payload.CountryId)
+			}
+		}
+
+		if payload.Page == 0 {
+			output <- q.findMarketingAudienceByCursor(ctx, payload, filter, purpose)
+			return
+		}
+
+		resp := <-q.mongoDb.Aggregate(mongodb.Aggregate{
+			Result:         &audience,
+			CollectionName: "users",
+			Filter: append(audienceStages(filter, purpose),
+				bson.M{"$sort": bson.M{"userId": 1}},
+				bson.M{"$facet": bson.M{
+					"users": bson.A{
+						bson.M{"$skip": payload.Size * (payload.Page - 1)},
+						bson.M{"$limit": payload.Size},
+						bson.M{"$project": bson.M{"_id": 0, "userId": 1}},
+					},
+					"total": bson.A{
+						bson.M{"$count": "count"},
+					},
+				}},
+			),
+		}, ctx)
+		output <- resp
+	}()
+
+	return output
+}
+
+// findMarketingAudienceByCursor reads the page of the audience after the cursor in userId order, the
+// audience is counted by a second query only on request
+func (q queryMongodbRepository) findMarketingAudienceByCursor(ctx context.Context, payload userRequest.FindMarketingAudience, filter bson.M, purpose string) wrapper.Result {
+	sort := mongodb.Sort{FieldName: "userId", By: mongodb.SortAscending}
+	match, err := mongodb.CursorFilter(filter, sort, payload.Cursor)
+	if err != nil {
+		return wrapper.Result{Error: err}
+	}
+
+	var members []userEntity.AudienceMember
+	resp := <-q.mongoDb.Aggregate(mongodb.Aggregate{
+		Result:         &members,
+		CollectionName: "users",
+		Filter: append(audienceStages(match, purpose),
+			bson.M{"$sort": mongodb.CursorSort(sort)},
+			bson.M{"$limit": payload.Size + 1},
+			bson.M{"$project": bson.M{"_id": 1, "userId": 1}},
+		),
+	}, ctx)
+	if resp.Error != nil {
+		return resp
+	}
+
+	audience := userEntity.Audience{Users: members}
+	next := ""
+	if int64(len(members)) > payload.Size {
+		audience.Users = members[:payload.Size]
+		last := audience.Users[len(audience.Users)-1]
+		cursor, err := mongodb.EncodeCursor(sort, last.UserId, last.Id)
+		if err != nil {
+			return wrapper.Result{Error: err}
+		}
+		next = cursor
+	}
+
+	if payload.Count {
+		resp := <-q.mongoDb.Aggregate(mongodb.Aggregate{
+			Result:         &audience.Total,
+			CollectionName: "users",
+			Filter:         append(audienceStages(filter, purpose), bson.M{"$count": "count"}),
+		}, ctx)
+		if resp.Error != nil {
+			return resp
+		}
+	}
+
+	return wrapper.Result{
+		Data:     &[]userEntity.Audience{audience},
+		MetaData: next,
+	}
+}
+
+// audienceStages match the users of filter then keep those who granted purpose
+func audienceStages(filter interface{}, purpose string) bson.A {
+	return bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$lookup": bson.M{
+			"from": "users-consent",
+			"let":  bson.M{"userId": "$userId"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"purpose": purpose,
+					"granted": true,
+					"$expr":   bson.M{"$eq": bson.A{"$userId", "$$userId"}},
+				}},
+				bson.M{"$limit": 1},
+			},
+			"as": "consent",
+		}},
+		bson.M{"$match": bson.M{"consent": bson.M{"$ne": bson.A{}}}},
+	}
+}
+
+func (q queryMongodbRepository) FindAddressesByUserId(ctx context.Context, userId string) <-chan wrapper.Result {
+	var addresses []userEntity.AddressBookEntry
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindAllData(mongodb.FindAllData{
+			Result:         &addresses,
+			CollectionName: "users-address",
+			Filter: bson.M{
+				"userId":  userId,
+				"deleted": bson.M{"$ne": true},
+			},
+			Sort: &mongodb.Sort{
+				FieldName: "createdAt",
+				By:        mongodb.SortAscending,
+			},
+			Page: 1,
+			Size: 50,
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+// CountAddressesByUserId counts every saved address of the user, deleted ones included
+func (q queryMongodbRepository) CountAddressesByUserId(ctx context.Context, userId string) <-chan wrapper.Result {
+	var countData int64
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.CountData(mongodb.CountData{
+			Result:         &countData,
+			CollectionName: "users-address",
+			Filter: bson.M{
+				"userId": userId,
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindOneAddress(ctx context.Context, userId string, addressId string) <-chan wrapper.Result {
+	var address userEntity.AddressBookEntry
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &address,
+			CollectionName: "users-address",
+			Filter: bson.M{
+				"userId":    userId,
+				"addressId": addressId,
+				"deleted":   bson.M{"$ne": true},
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
+
+func (q queryMongodbRepository) FindDefaultAddress(ctx context.Context, userId string) <-chan wrapper.Result {
+	var address userEntity.AddressBookEntry
+	output := make(chan wrapper.Result)
+
+	go func() {
+		resp := <-q.mongoDb.FindOne(mongodb.FindOne{
+			Result:         &address,
+			CollectionName: "users-address",
+			Filter: bson.M{
+				"userId":    userId,
+				"isDefault": true,
+				"deleted":   bson.M{"$ne": true},
+			},
+		}, ctx)
+		output <- resp
+		close(output)
+	}()
+
+	return output
+}
@@ -3,8 +3,12 @@ package queries_test
 import (
 	"context"
 	"testing"
+	"time"
 	"user-service/internal/modules/user"
+	userEntity "user-service/internal/modules/user/models/entity"
+	userRequest "user-service/internal/modules/user/models/request"
 	mongoRQ "user-service/internal/modules/user/repositories/queries"
+	"user-service/internal/pkg/databases/mongodb"
 	"user-service/internal/pkg/helpers"
 	mocks "user-service/mocks/pkg/databases/mongodb"
 	mocklog "user-service/mocks/pkg/log"
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type CommandTestSuite struct {
@@ -107,3 +112,321 @@ func (suite *CommandTestSuite) TestFindOneByEmailUserTemp() {
 	// Assert FindOne
 	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
 }
+
+func (suite *CommandTestSuite) TestFindUsersDueForDeletion() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindUsersDueForDeletion(suite.ctx, time.Now(), 100)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindAllData
+	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindOneDataExport() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneDataExport(suite.ctx, "export-1")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindDataExportsByStatus() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindDataExportsByStatus(suite.ctx, "pending", 20)
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindAllData
+	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindDataExportsByUserId() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindDataExportsByUserId(suite.ctx, "userId")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindAllData
+	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindConsentsByUserId() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindConsentsByUserId(suite.ctx, "userId")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindAllData
+	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindOneConsent() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneConsent(suite.ctx, "userId", "marketing_email")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindAddressesByUserId() {
+
+	// Mock FindAllData
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindAllData", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindAddressesByUserId(suite.ctx, "userId")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindAllData
+	suite.mockMongodb.AssertCalled(suite.T(), "FindAllData", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestCountAddressesByUserId() {
+
+	// Mock CountData, deleted addresses are counted too
+	suite.mockMongodb.On("CountData", mock.MatchedBy(func(payload mongodb.CountData) bool {
+		return payload.CollectionName == "users-address" && assert.ObjectsAreEqual(bson.M{"userId": "userId"}, payload.Filter)
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 2}))
+
+	// Act
+	result := <-suite.repository.CountAddressesByUserId(suite.ctx, "userId")
+
+	// Assert
+	assert.Equal(suite.T(), int64(2), result.Count)
+}
+
+func (suite *CommandTestSuite) TestFindOneAddress() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindOneAddress(suite.ctx, "userId", "addressId")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindDefaultAddress() {
+
+	// Mock FindOne
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("FindOne", mock.Anything, mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindDefaultAddress(suite.ctx, "userId")
+	// Asset
+	assert.NotNil(suite.T(), result, "Expected a result")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	// Wait for the goroutine to complete
+	<-result
+
+	// Assert FindOne
+	suite.mockMongodb.AssertCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandTestSuite) TestFindMarketingAudience() {
+	latitude, longitude := -6.2, 106.8
+
+	// Mock Aggregate
+	expectedResult := make(chan helpers.Result)
+	suite.mockMongodb.On("Aggregate", mock.MatchedBy(func(payload mongodb.Aggregate) bool {
+		return payload.CollectionName == "users"
+	}), mock.Anything).Return((<-chan helpers.Result)(expectedResult))
+
+	// Act
+	result := suite.repository.FindMarketingAudience(suite.ctx, userRequest.FindMarketingAudience{
+		Latitude:  &latitude,
+		Longitude: &longitude,
+		RadiusKm:  10,
+		Page:      1,
+		Size:      100,
+	}, "marketing_email")
+
+	// Simulate receiving a result from the channel
+	go func() {
+		expectedResult <- helpers.Result{Data: "result not nil", Error: nil}
+		close(expectedResult)
+	}()
+
+	assert.Equal(suite.T(), "result not nil", (<-result).Data)
+}
+
+func (suite *CommandTestSuite) TestFindMarketingAudienceByCursor() {
+	member := func(userId string) userEntity.AudienceMember {
+		document, _ := bson.Marshal(bson.M{"_id": userId})
+		return userEntity.AudienceMember{Id: bson.Raw(document).Lookup("_id"), UserId: userId}
+	}
+
+	// Mock Aggregate, the page holds one member more than asked and the count runs separately
+	suite.mockMongodb.On("Aggregate", mock.MatchedBy(func(payload mongodb.Aggregate) bool {
+		_, ok := payload.Result.(*[]userEntity.AudienceMember)
+		return ok
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		members := args.Get(0).(mongodb.Aggregate).Result.(*[]userEntity.AudienceMember)
+		*members = []userEntity.AudienceMember{member("1"), member("2"), member("3")}
+	}).Return(mockChannel(helpers.Result{}))
+	suite.mockMongodb.On("Aggregate", mock.MatchedBy(func(payload mongodb.Aggregate) bool {
+		_, ok := payload.Result.(*[]userEntity.AudienceTotal)
+		return ok
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		totals := args.Get(0).(mongodb.Aggregate).Result.(*[]userEntity.AudienceTotal)
+		*totals = []userEntity.AudienceTotal{{Count: 3}}
+	}).Return(mockChannel(helpers.Result{}))
+
+	// Act
+	result := <-suite.repository.FindMarketingAudience(suite.ctx, userRequest.FindMarketingAudience{
+		CityId: "3171",
+		Size:   2,
+		Count:  true,
+	}, "marketing_email")
+
+	// Assert
+	assert.NoError(suite.T(), result.Error)
+	audience := (*result.Data.(*[]userEntity.Audience))[0]
+	assert.Len(suite.T(), audience.Users, 2)
+	assert.Equal(suite.T(), int64(3), audience.Total[0].Count)
+	assert.NotEmpty(suite.T(), result.MetaData)
+}
+
+func (suite *CommandTestSuite) TestFindMarketingAudienceErrCursor() {
+	// Act
+	result := <-suite.repository.FindMarketingAudience(suite.ctx, userRequest.FindMarketingAudience{
+		CityId: "3171",
+		Size:   2,
+		Cursor: "not a cursor",
+	}, "marketing_email")
+
+	// Assert
+	assert.Error(suite.T(), result.Error)
+	suite.mockMongodb.AssertNotCalled(suite.T(), "Aggregate", mock.Anything, mock.Anything)
+}
+
+func mockChannel(result helpers.Result) <-chan helpers.Result {
+	responseChan := make(chan helpers.Result)
+
+	go func() {
+		responseChan <- result
+		close(responseChan)
+	}()
+
+	return responseChan
+}
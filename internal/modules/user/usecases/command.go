@@ -1,21 +1,29 @@
 package usecases
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"user-service/configs"
 	"user-service/internal/modules/address"
-	addressEntity "user-service/internal/modules/address/models/entity"
+	addressRequest "user-service/internal/modules/address/models/request"
 	user "user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	userRequest "user-service/internal/modules/user/models/request"
 	userResponse "user-service/internal/modules/user/models/response"
+	"user-service/internal/pkg/blobstore"
 	"user-service/internal/pkg/constants"
 	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
+	"user-service/internal/pkg/imaging"
 	kafkaPkgConfluent "user-service/internal/pkg/kafka/confluent"
 	"user-service/internal/pkg/log"
 	"user-service/internal/pkg/redis"
@@ -25,30 +33,40 @@ import (
 )
 
 type commandUsecase struct {
-	userRepositoryQuery    user.MongodbRepositoryQuery
-	userRepositoryCommand  user.MongodbRepositoryCommand
-	logger                 log.Logger
-	redis                  redis.Collections
-	kafkaProducer          kafkaPkgConfluent.Producer
-	jwtHelper              helpers.TokenGenerator
-	addressRepositoryQuery address.MongodbRepositoryQuery
+	userRepositoryQuery   user.MongodbRepositoryQuery
+	userRepositoryCommand user.MongodbRepositoryCommand
+	logger                log.Logger
+	redis                 redis.Collections
+	kafkaProducer         kafkaPkgConfluent.Producer
+	jwtHelper             helpers.TokenGenerator
+	addressUsecaseQuery   address.UsecaseQuery
+	blobStore             blobstore.BlobStore
 }
 
 func NewCommandUsecase(
 	umq user.MongodbRepositoryQuery, umc user.MongodbRepositoryCommand,
 	log log.Logger, rc redis.Collections, kp kafkaPkgConfluent.Producer,
-	jwt helpers.TokenGenerator, amq address.MongodbRepositoryQuery) user.UsecaseCommand {
+	jwt helpers.TokenGenerator, auq address.UsecaseQuery, bs blobstore.BlobStore) user.UsecaseCommand {
 	return commandUsecase{
-		userRepositoryQuery:    umq,
-		userRepositoryCommand:  umc,
-		logger:                 log,
-		redis:                  rc,
-		kafkaProducer:          kp,
-		jwtHelper:              jwt,
-		addressRepositoryQuery: amq,
+		userRepositoryQuery:   umq,
+		userRepositoryCommand: umc,
+		logger:                log,
+		redis:                 rc,
+		kafkaProducer:         kp,
+		jwtHelper:             jwt,
+		addressUsecaseQuery:   auq,
+		blobStore:             bs,
 	}
 }
 
+var avatarVariants = []imaging.Variant{
+	{Name: "large", Size: 512},
+	{Name: "medium", Size: 256},
+	{Name: "small", Size: 64},
+}
+
+const avatarDefaultVariant = "medium"
+
 func (c commandUsecase) UpdateUser(origCtx context.Context, payload userRequest.UpdateUser, userId string) (string, error) {
 	domain := "userUsecase-UpdateUser"
 	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
@@ -59,7 +77,7 @@ func (c commandUsecase) UpdateUser(origCtx context.Context, payload userRequest.
 
 	if payload.Role != userRequest.RoleUser {
 		msg := "User role not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return "", errors.NotFound(msg)
 	}
 
@@ -70,7 +88,7 @@ func (c commandUsecase) UpdateUser(origCtx context.Context, payload userRequest.
 
 	if resp.Data == nil {
 		msg := "Email not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return "", errors.NotFound(msg)
 	}
 
@@ -79,90 +97,32 @@ func (c commandUsecase) UpdateUser(origCtx context.Context, payload userRequest.
 		return "", errors.InternalServerError("cannot parsing data")
 	}
 
-	countryId, err := strconv.Atoi(payload.CountryId)
+	country, subDistrictUser, err := c.resolveLocation(ctx, addressRequest.Hierarchy{
+		CountryId:     payload.CountryId,
+		ProvinceId:    payload.ProvinceId,
+		CityId:        payload.CityId,
+		DistrictId:    payload.DistrictId,
+		SubdistrictId: payload.SubdictrictId,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	})
 	if err != nil {
-		msg := "CountryId must integer"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return "", errors.BadRequest(msg)
-	}
-
-	country := <-c.addressRepositoryQuery.FindOneCountry(ctx, countryId)
-	if country.Error != nil {
-		return "", country.Error
-	}
-	if country.Data == nil {
-		msg := "Country not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return "", errors.CustomError(msg, 4002, http.StatusNotFound)
-	}
-	countryData, ok := country.Data.(*addressEntity.Country)
-	if !ok {
-		msg := "cannot parsing data country"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return "", errors.InternalServerError("cannot parsing data")
-	}
-
-	var subDistrictUser userEntity.Subdistrict
-	if countryData.Code == "ID" {
-		subdistrict := <-c.addressRepositoryQuery.FindOneSubdistrict(ctx, payload.SubdictrictId)
-		if subdistrict.Error != nil {
-			return "", subdistrict.Error
-		}
-		if subdistrict.Data == nil {
-			msg := "Subdistrict not found"
-			c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-			return "", errors.CustomError(msg, 4002, http.StatusNotFound)
-		}
-		subdistrictData, ok := subdistrict.Data.(*addressEntity.SubDistrict)
-		if !ok {
-			msg := "cannot parsing data subdistrict"
-			c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-			return "", errors.InternalServerError("cannot parsing data")
-		}
-		if subdistrictData != nil {
-			subDistrictUser = userEntity.Subdistrict{
-				Id:           subdistrictData.Id,
-				Name:         subdistrictData.Name,
-				DistrictId:   subdistrictData.DistrictId,
-				DistrictName: subdistrictData.DistrictName,
-				CityId:       subdistrictData.CityId,
-				CityName:     subdistrictData.CityName,
-				ProvinceId:   subdistrictData.ProvinceId,
-				ProvinceName: subdistrictData.ProvinceName,
-			}
-		}
+		return "", err
 	}
 
-	user := userEntity.User{
-		UserId:       userData.UserId,
-		FullName:     payload.FullName,
-		Email:        userData.Email,
-		NIK:          userData.NIK,
-		MobileNumber: helpers.VerifyPhoneNumber62(payload.MobileNumber),
-		Password:     userData.Password,
-		Subdistrict:  subDistrictUser,
-		Country: userEntity.Country{
-			Id:            countryData.Id,
-			Code:          countryData.Code,
-			Name:          countryData.Name,
-			FullName:      countryData.FullName,
-			ContinentId:   countryData.ContinentCode,
-			ContinentName: countryData.ContinentName,
-			Latitude:      payload.Latitude,
-			Longitude:     payload.Longitude,
-		},
-		Status:    userData.Status,
-		Address:   payload.Address,
-		RtRw:      payload.RtRw,
-		Role:      payload.Role,
-		LoginAt:   userData.LoginAt,
-		CreatedAt: userData.CreatedAt,
-		UpdatedAt: time.Now(),
-	}
-	respUser := <-c.userRepositoryCommand.UpsertOneUser(ctx, user)
-	if respUser.Error != nil {
-		return "", respUser.Error
+	err = c.saveProfile(ctx, userData, func(user *userEntity.User) {
+		user.FullName = payload.FullName
+		user.MobileNumber = helpers.VerifyPhoneNumber62(payload.MobileNumber)
+		user.Subdistrict = subDistrictUser
+		user.Country = country
+		user.Address = payload.Address
+		user.RtRw = payload.RtRw
+		user.Role = payload.Role
+	})
+	if err != nil {
+		return "", err
 	}
+	c.syncDefaultAddress(ctx, *userData)
 	return "Update user success", nil
 }
 
@@ -175,86 +135,56 @@ func (c commandUsecase) RegisterUser(origCtx context.Context, payload userReques
 	defer span.End()
 	validEmail := helpers.IsEmailValid(payload.Email)
 	if !validEmail {
-		msg := "Incorrect email format"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4001, http.StatusBadRequest)
+		msg := errors.EmailInvalid.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailInvalid.New()
 	}
 	if helpers.IsBlacklistedEmail(payload.Email) {
-		msg := "Email blacklist"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4002, http.StatusBadRequest)
+		msg := errors.EmailBlacklisted.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailBlacklisted.New()
 	}
 
 	if !helpers.IsValidPassword(payload.Password) {
-		msg := "Password not criteria"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4004, http.StatusBadRequest)
+		msg := errors.PasswordWeak.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.PasswordWeak.New()
 	}
 
 	if payload.Role != userRequest.RoleUser {
 		msg := "User role not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return nil, errors.NotFound(msg)
 	}
 
+	if payload.TermsVersion != currentTermsVersion() || payload.PrivacyPolicyVersion != currentPrivacyPolicyVersion() {
+		msg := errors.TermsNotCurrent.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.TermsNotCurrent.New()
+	}
+
 	resp := <-c.userRepositoryQuery.FindOneByEmail(ctx, payload.Email)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
 	if resp.Data != nil {
 		msg := "Email is already registered"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return nil, errors.BadRequest(msg)
 	}
 
-	countryId, err := strconv.Atoi(payload.CountryId)
+	country, subDistrictUser, err := c.resolveLocation(ctx, addressRequest.Hierarchy{
+		CountryId:     payload.CountryId,
+		ProvinceId:    payload.ProvinceId,
+		CityId:        payload.CityId,
+		DistrictId:    payload.DistrictId,
+		SubdistrictId: payload.SubdictrictId,
+		PostalCode:    payload.PostalCode,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	})
 	if err != nil {
-		msg := "CountryId must integer"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.BadRequest(msg)
-	}
-
-	country := <-c.addressRepositoryQuery.FindOneCountry(ctx, countryId)
-	if country.Error != nil {
-		return nil, country.Error
-	}
-	if country.Data == nil {
-		msg := "Country not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4002, http.StatusNotFound)
-	}
-	countryData, ok := country.Data.(*addressEntity.Country)
-	if !ok {
-		return nil, errors.InternalServerError("cannot parsing data country")
-	}
-
-	var subDistrictUser userEntity.Subdistrict
-	if countryData.Code == "ID" {
-		subdistrict := <-c.addressRepositoryQuery.FindOneSubdistrict(ctx, payload.SubdictrictId)
-		if subdistrict.Error != nil {
-			return nil, subdistrict.Error
-		}
-		if subdistrict.Data == nil {
-			msg := "Subdistrict not found"
-			c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-			return nil, errors.CustomError(msg, 4002, http.StatusNotFound)
-		}
-		subdistrictData, ok := subdistrict.Data.(*addressEntity.SubDistrict)
-		if !ok {
-			return nil, errors.InternalServerError("cannot parsing data subdistrict")
-		}
-		if subdistrictData != nil {
-			subDistrictUser = userEntity.Subdistrict{
-				Id:           subdistrictData.Id,
-				Name:         subdistrictData.Name,
-				DistrictId:   subdistrictData.DistrictId,
-				DistrictName: subdistrictData.DistrictName,
-				CityId:       subdistrictData.CityId,
-				CityName:     subdistrictData.CityName,
-				ProvinceId:   subdistrictData.ProvinceId,
-				ProvinceName: subdistrictData.ProvinceName,
-			}
-		}
+		return nil, err
 	}
 
 	passwordHash := helpers.GeneratePassword(payload.Password)
@@ -267,22 +197,20 @@ func (c commandUsecase) RegisterUser(origCtx context.Context, payload userReques
 		MobileNumber: helpers.VerifyPhoneNumber62(payload.MobileNumber),
 		Password:     passwordHash,
 		Subdistrict:  subDistrictUser,
-		Country: userEntity.Country{
-			Id:            countryData.Id,
-			Code:          countryData.Code,
-			Name:          countryData.Name,
-			FullName:      countryData.FullName,
-			ContinentId:   countryData.ContinentCode,
-			ContinentName: countryData.ContinentName,
-			Latitude:      payload.Latitude,
-			Longitude:     payload.Longitude,
-		},
-		Address:   payload.Address,
-		RtRw:      payload.RtRw,
-		Role:      payload.Role,
-		LoginAt:   time.Now(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Country:      country,
+		Address:      payload.Address,
+		RtRw:         payload.RtRw,
+		Role:         payload.Role,
+		LoginAt:      time.Now(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	userId := user.UserId
+	user.PendingConsents = []userEntity.Consent{
+		newConsent(userId, userEntity.ConsentPurposeTerms, true, payload.TermsVersion, userEntity.ConsentSourceRegistration),
+		newConsent(userId, userEntity.ConsentPurposePrivacyPolicy, true, payload.PrivacyPolicyVersion, userEntity.ConsentSourceRegistration),
+		newConsent(userId, userEntity.ConsentPurposeMarketingEmail, payload.MarketingEmail, payload.PrivacyPolicyVersion, userEntity.ConsentSourceRegistration),
+		newConsent(userId, userEntity.ConsentPurposeMarketingSms, payload.MarketingSms, payload.PrivacyPolicyVersion, userEntity.ConsentSourceRegistration),
 	}
 	respUser := <-c.userRepositoryCommand.UpsertOneUserTemp(ctx, user)
 	if respUser.Error != nil {
@@ -304,9 +232,9 @@ func (c commandUsecase) RegisterUser(origCtx context.Context, payload userReques
 		Otp:      string(otp),
 	}
 	marshaledKafkaData, _ := json.Marshal(kafkaData)
-	otpTopic := "concert-send-otp-user-registration"
+	otpTopic := constants.KafkaTopicOtpUserRegistration
 	c.kafkaProducer.Publish(otpTopic, marshaledKafkaData, nil)
-	c.logger.Info(ctx, fmt.Sprintf("Send kafka email otp : %s, topic: %s", payload.Email, otpTopic), fmt.Sprintf("%+v", payload.Email))
+	c.logger.Info(ctx, fmt.Sprintf("Send kafka email otp, topic: %s", otpTopic), payload)
 
 	c.redis.Set(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyOtpRegister, payload.Email), kafkaData.Otp, 3*time.Minute)
 
@@ -325,25 +253,25 @@ func (c commandUsecase) VerifyRegisterUser(origCtx context.Context, payload user
 
 	validEmail := helpers.IsEmailValid(payload.Email)
 	if !validEmail {
-		msg := "Incorrect email format"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4001, http.StatusBadRequest)
+		msg := errors.EmailInvalid.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailInvalid.New()
 	}
 	if helpers.IsBlacklistedEmail(payload.Email) {
-		msg := "Email blacklist"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4002, http.StatusBadRequest)
+		msg := errors.EmailBlacklisted.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailBlacklisted.New()
 	}
 	checkedOtp, _ := c.redis.Get(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyOtpRegister, payload.Email)).Result()
 	if checkedOtp == "" {
 		msg := "Otp expired"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return nil, errors.BadRequest(msg)
 	}
 	if checkedOtp != payload.Otp {
-		msg := "Otp not match"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4003, http.StatusBadRequest)
+		msg := errors.OtpMismatch.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.OtpMismatch.New()
 	}
 
 	// check data user in user temp
@@ -353,7 +281,7 @@ func (c commandUsecase) VerifyRegisterUser(origCtx context.Context, payload user
 	}
 	if resp.Data == nil {
 		msg := "Email not found"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		c.logger.Error(ctx, msg, payload)
 		return nil, errors.NotFound(msg)
 	}
 
@@ -361,10 +289,28 @@ func (c commandUsecase) VerifyRegisterUser(origCtx context.Context, payload user
 	if !ok {
 		return nil, errors.InternalServerError("cannot parsing data")
 	}
-	userData.Status = "active"
-	respUser := <-c.userRepositoryCommand.UpsertOneUser(ctx, *userData)
-	if respUser.Error != nil {
-		return nil, respUser.Error
+	userData.Status = userEntity.StatusActive
+	consents := userData.PendingConsents
+	userData.PendingConsents = nil
+
+	// A retry after a failed consent finds the account already created, it may have been changed since
+	// and is not written over with the registration data
+	respExisting := <-c.userRepositoryQuery.FindOneByEmail(ctx, payload.Email)
+	if respExisting.Error != nil {
+		return nil, respExisting.Error
+	}
+	if respExisting.Data == nil {
+		respUser := <-c.userRepositoryCommand.UpsertOneUser(ctx, *userData)
+		if respUser.Error != nil {
+			return nil, respUser.Error
+		}
+	}
+	// The otp is kept until the consents are saved, a failure here is retried with the same otp
+	for _, consent := range consents {
+		respConsent := <-c.userRepositoryCommand.UpsertOneConsent(ctx, consent)
+		if respConsent.Error != nil {
+			return nil, respConsent.Error
+		}
 	}
 	c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyOtpRegister, payload.Email))
 
@@ -381,33 +327,32 @@ func (c commandUsecase) LoginUser(origCtx context.Context, payload userRequest.L
 
 	validEmail := helpers.IsEmailValid(payload.Email)
 	if !validEmail {
-		msg := "Incorrect email format"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4001, http.StatusBadRequest)
+		msg := errors.EmailInvalid.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailInvalid.New()
 	}
 	if helpers.IsBlacklistedEmail(payload.Email) {
-		msg := "Email blacklist"
-		c.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
-		return nil, errors.CustomError(msg, 4002, http.StatusBadRequest)
+		msg := errors.EmailBlacklisted.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.EmailBlacklisted.New()
 	}
 
 	// Get attempt from redis
 	attempt, _ := c.redis.Get(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyLoginAttempt, payload.Email)).Result()
 	attemptInt, _ := strconv.Atoi(attempt)
 	if attemptInt >= 5 {
-		logMessage := "You have too many attempts, please wait 10 minutes"
-		c.logger.Info(ctx, logMessage, fmt.Sprintf("%+v", payload.Email))
-		return nil, errors.CustomError(logMessage, 4003, http.StatusBadRequest)
+		logMessage := errors.LoginTooManyAttempts.Message
+		c.logger.Info(ctx, logMessage, payload)
+		return nil, errors.LoginTooManyAttempts.New()
 	}
 
 	resp := <-c.userRepositoryQuery.FindOneByEmail(ctx, payload.Email)
-	fmt.Println("resp: ", resp)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
 	if resp.Data == nil {
 		logMessage := "email / password not found"
-		c.logger.Info(ctx, logMessage, fmt.Sprintf("%+v", helpers.MaskEmail(payload.Email)))
+		c.logger.Info(ctx, logMessage, payload)
 		return nil, errors.BadRequest(logMessage)
 	}
 	userData, ok := resp.Data.(*userEntity.User)
@@ -421,15 +366,28 @@ func (c commandUsecase) LoginUser(origCtx context.Context, payload userRequest.L
 		c.redis.Set(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyLoginAttempt, payload.Email), attemptInt, 10*time.Minute)
 
 		logMessage := "Username / password not match"
-		c.logger.Info(ctx, logMessage, fmt.Sprintf("%+v", helpers.MaskEmail(payload.Email)))
+		c.logger.Info(ctx, logMessage, payload)
 		return nil, errors.BadRequest(logMessage)
 	}
 
-	//
-	respUser := <-c.userRepositoryCommand.UpsertOneUser(ctx, *userData)
-	if respUser.Error != nil {
-		return nil, respUser.Error
+	if err := c.ensureCurrentTermsAccepted(ctx, userData.UserId, payload); err != nil {
+		return nil, err
+	}
+
+	// Login within the grace period cancels a pending account deletion
+	if userData.Status == userEntity.StatusPendingDeletion {
+		err := c.saveProfile(ctx, userData, func(user *userEntity.User) {
+			if user.Status == userEntity.StatusPendingDeletion {
+				user.Status = userEntity.StatusActive
+				user.Deletion = userEntity.Deletion{}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.logger.Info(ctx, "Account deletion cancelled by login", userData.UserId)
 	}
+
 	c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyLoginAttempt, payload.Email))
 	// Generate token
 	tokenPayload := map[string]interface{}{
@@ -451,3 +409,1247 @@ func (c commandUsecase) LoginUser(origCtx context.Context, payload userRequest.L
 	}, nil
 
 }
+
+func (c commandUsecase) PatchUser(origCtx context.Context, payload userRequest.PatchUser) (*userResponse.GetProfile, error) {
+	domain := "userUsecase-PatchUser"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, payload.UserId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	if !helpers.MatchETag(payload.IfMatch, helpers.FormatETag(userData.Version)) {
+		msg := "Profile has been modified, reload and try again"
+		c.logger.Info(ctx, msg, payload.UserId)
+		return nil, errors.PreconditionFailed(msg)
+	}
+
+	current := userRequest.ProfileDocument{
+		FullName:      userData.FullName,
+		MobileNumber:  userData.MobileNumber,
+		Address:       userData.Address,
+		SubdictrictId: userData.Subdistrict.Id,
+		CountryId:     strconv.Itoa(userData.Country.Id),
+		RtRw:          userData.RtRw,
+		Latitude:      userData.Country.Latitude,
+		Longitude:     userData.Country.Longitude,
+	}
+	currentJson, err := json.Marshal(current)
+	if err != nil {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	patchedJson, err := helpers.MergePatch(currentJson, payload.Patch)
+	if err != nil {
+		msg := "Invalid merge patch document"
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.BadRequest(msg)
+	}
+	var patched userRequest.ProfileDocument
+	if err := json.Unmarshal(patchedJson, &patched); err != nil {
+		msg := "Invalid merge patch document"
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.BadRequest(msg)
+	}
+	if patched.FullName == "" || patched.MobileNumber == "" || patched.CountryId == "" {
+		msg := "fullName, mobileNumber and countryId cannot be removed"
+		c.logger.Error(ctx, msg, patched)
+		return nil, errors.BadRequest(msg)
+	}
+
+	if patched.CountryId != current.CountryId || patched.SubdictrictId != current.SubdictrictId ||
+		patched.Latitude != current.Latitude || patched.Longitude != current.Longitude {
+		country, subdistrict, err := c.resolveLocation(ctx, addressRequest.Hierarchy{
+			CountryId:     patched.CountryId,
+			SubdistrictId: patched.SubdictrictId,
+			Latitude:      patched.Latitude,
+			Longitude:     patched.Longitude,
+		})
+		if err != nil {
+			return nil, err
+		}
+		userData.Subdistrict = subdistrict
+		userData.Country = country
+	}
+
+	userData.FullName = patched.FullName
+	userData.MobileNumber = helpers.VerifyPhoneNumber62(patched.MobileNumber)
+	userData.Address = patched.Address
+	userData.RtRw = patched.RtRw
+	userData.UpdatedAt = time.Now()
+	expectedVersion := userData.Version
+	userData.Version++
+
+	respUser := <-c.userRepositoryCommand.UpdateOneUserWithVersion(ctx, *userData, expectedVersion)
+	if respUser.Error != nil {
+		return nil, respUser.Error
+	}
+	// Another request updated the profile between our read and write
+	if respUser.Count == 0 {
+		msg := "Profile has been modified, reload and try again"
+		c.logger.Info(ctx, msg, payload.UserId)
+		return nil, errors.PreconditionFailed(msg)
+	}
+	c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyGetProfileUser, payload.UserId))
+	c.syncDefaultAddress(ctx, *userData)
+
+	response := profileResponse(*userData)
+	return &response, nil
+}
+
+func (c commandUsecase) UploadAvatar(origCtx context.Context, payload userRequest.UploadAvatar) (*userResponse.Avatar, error) {
+	domain := "userUsecase-UploadAvatar"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, payload.UserId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	images, err := imaging.GenerateVariants(payload.Content, avatarVariants)
+	if err == imaging.ErrUnsupportedFormat {
+		msg := errors.AvatarUnsupportedType.Message
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.AvatarUnsupportedType.New()
+	}
+	if err == imaging.ErrImageTooLarge {
+		msg := "Avatar dimensions are too large"
+		c.logger.Error(ctx, msg, payload.UserId)
+		return nil, errors.UnprocessableEntity(msg)
+	}
+	if err != nil {
+		c.logger.Error(ctx, "Error processing avatar", err.Error())
+		return nil, errors.InternalServerError("cannot process avatar")
+	}
+
+	// A fresh key per upload so cached urls of the previous avatar are never served
+	prefix := fmt.Sprintf("avatars/%s/%d", payload.UserId, time.Now().UnixNano())
+	avatar := userEntity.Avatar{
+		Variants:  map[string]string{},
+		UpdatedAt: time.Now(),
+	}
+	for _, img := range images {
+		key := fmt.Sprintf("%s-%s.jpg", prefix, img.Name)
+		url, err := c.blobStore.Put(ctx, key, img.Content, img.ContentType)
+		if err != nil {
+			c.logger.Error(ctx, "Error storing avatar", err.Error())
+			c.deleteBlobs(ctx, avatar.Keys)
+			return nil, errors.InternalServerError("cannot store avatar")
+		}
+		avatar.Keys = append(avatar.Keys, key)
+		avatar.Variants[img.Name] = url
+	}
+
+	var previousKeys []string
+	err = c.saveProfile(ctx, userData, func(user *userEntity.User) {
+		previousKeys = user.Avatar.Keys
+		user.Avatar = avatar
+	})
+	if err != nil {
+		c.deleteBlobs(ctx, avatar.Keys)
+		return nil, err
+	}
+	c.deleteBlobs(ctx, previousKeys)
+
+	return &userResponse.Avatar{
+		AvatarUrl: avatar.Variants[avatarDefaultVariant],
+		Variants:  avatar.Variants,
+	}, nil
+}
+
+// deleteBlobs is best effort, a leftover blob is only wasted storage
+func (c commandUsecase) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := c.blobStore.Delete(ctx, key); err != nil {
+			c.logger.Error(ctx, fmt.Sprintf("Error deleting blob %s", key), err.Error())
+		}
+	}
+}
+
+// ensureCurrentTermsAccepted forces re-acceptance when the published terms changed since the last acceptance
+func (c commandUsecase) ensureCurrentTermsAccepted(ctx context.Context, userId string, payload userRequest.LoginUser) error {
+	respConsents := <-c.userRepositoryQuery.FindConsentsByUserId(ctx, userId)
+	if respConsents.Error != nil {
+		return respConsents.Error
+	}
+	var consents []userEntity.Consent
+	if data, ok := respConsents.Data.(*[]userEntity.Consent); ok {
+		consents = *data
+	}
+	if !isAcceptanceRequired(consents) {
+		return nil
+	}
+
+	termsVersion := currentTermsVersion()
+	privacyPolicyVersion := currentPrivacyPolicyVersion()
+	if payload.TermsVersion != termsVersion || payload.PrivacyPolicyVersion != privacyPolicyVersion {
+		msg := errors.TermsNotAccepted.Message
+		c.logger.Info(ctx, msg, userId)
+		return errors.TermsNotAccepted.New()
+	}
+
+	accepted := []userEntity.Consent{
+		newConsent(userId, userEntity.ConsentPurposeTerms, true, termsVersion, userEntity.ConsentSourceLogin),
+		newConsent(userId, userEntity.ConsentPurposePrivacyPolicy, true, privacyPolicyVersion, userEntity.ConsentSourceLogin),
+	}
+	for _, consent := range accepted {
+		respConsent := <-c.userRepositoryCommand.UpsertOneConsent(ctx, consent)
+		if respConsent.Error != nil {
+			return respConsent.Error
+		}
+	}
+	c.logger.Info(ctx, fmt.Sprintf("Terms version %s accepted on login", termsVersion), userId)
+
+	return nil
+}
+
+func (c commandUsecase) UpdateConsents(origCtx context.Context, payload userRequest.UpdateConsents, userId string) (*userResponse.Consents, error) {
+	domain := "userUsecase-UpdateConsents"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	if payload.TermsVersion != "" && payload.TermsVersion != currentTermsVersion() {
+		msg := errors.TermsNotCurrent.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.TermsNotCurrent.New()
+	}
+	if payload.PrivacyPolicyVersion != "" && payload.PrivacyPolicyVersion != currentPrivacyPolicyVersion() {
+		msg := errors.PrivacyPolicyNotCurrent.Message
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.PrivacyPolicyNotCurrent.New()
+	}
+
+	var updates []userEntity.Consent
+	if payload.TermsVersion != "" {
+		updates = append(updates, newConsent(userId, userEntity.ConsentPurposeTerms, true, payload.TermsVersion, userEntity.ConsentSourceProfile))
+	}
+	if payload.PrivacyPolicyVersion != "" {
+		updates = append(updates, newConsent(userId, userEntity.ConsentPurposePrivacyPolicy, true, payload.PrivacyPolicyVersion, userEntity.ConsentSourceProfile))
+	}
+	if payload.MarketingEmail != nil {
+		updates = append(updates, newConsent(userId, userEntity.ConsentPurposeMarketingEmail, *payload.MarketingEmail, currentPrivacyPolicyVersion(), userEntity.ConsentSourceProfile))
+	}
+	if payload.MarketingSms != nil {
+		updates = append(updates, newConsent(userId, userEntity.ConsentPurposeMarketingSms, *payload.MarketingSms, currentPrivacyPolicyVersion(), userEntity.ConsentSourceProfile))
+	}
+	if len(updates) == 0 {
+		msg := "No consent to update"
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.BadRequest(msg)
+	}
+
+	respConsents := <-c.userRepositoryQuery.FindConsentsByUserId(ctx, userId)
+	if respConsents.Error != nil {
+		return nil, respConsents.Error
+	}
+	consents := map[string]userEntity.Consent{}
+	if data, ok := respConsents.Data.(*[]userEntity.Consent); ok {
+		for _, consent := range *data {
+			consents[consent.Purpose] = consent
+		}
+	}
+
+	for _, consent := range updates {
+		// Keep the original grant time while a consent stays granted
+		if existing, ok := consents[consent.Purpose]; ok && existing.Granted && consent.Granted {
+			consent.GrantedAt = existing.GrantedAt
+		}
+		respConsent := <-c.userRepositoryCommand.UpsertOneConsent(ctx, consent)
+		if respConsent.Error != nil {
+			return nil, respConsent.Error
+		}
+		consents[consent.Purpose] = consent
+	}
+
+	result := make([]userEntity.Consent, 0, len(consents))
+	for _, consent := range consents {
+		result = append(result, consent)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Purpose < result[j].Purpose
+	})
+
+	return consentsResponse(result), nil
+}
+
+func (c commandUsecase) UpdatePreferences(origCtx context.Context, payload userRequest.UpdatePreferences, userId string) (*userResponse.Preferences, error) {
+	domain := "userUsecase-UpdatePreferences"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, userId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	if _, changedFields := mergePreferences(userData.Preferences, payload); len(changedFields) == 0 {
+		msg := "No preference to update"
+		c.logger.Error(ctx, msg, payload)
+		return nil, errors.BadRequest(msg)
+	}
+	var preferences userEntity.Preferences
+	var changedFields []string
+	err := c.saveProfile(ctx, userData, func(user *userEntity.User) {
+		preferences, changedFields = mergePreferences(user.Preferences, payload)
+		user.Preferences = preferences
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kafkaData := struct {
+		UserId        string                 `json:"userId"`
+		ChangedFields []string               `json:"changedFields"`
+		Preferences   userEntity.Preferences `json:"preferences"`
+	}{
+		UserId:        userId,
+		ChangedFields: changedFields,
+		Preferences:   preferences,
+	}
+	marshaledKafkaData, _ := json.Marshal(kafkaData)
+	c.kafkaProducer.Publish(constants.KafkaTopicUserPreferencesUpdated, marshaledKafkaData, nil)
+	c.logger.Info(ctx, fmt.Sprintf("Send kafka user preferences updated, topic: %s", constants.KafkaTopicUserPreferencesUpdated), userId)
+
+	response := preferencesResponse(preferences)
+	return &response, nil
+}
+
+// uniqueValues trims the values and drops case-insensitive duplicates, keeping the first spelling
+func uniqueValues(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+// mergePreferences applies the fields given in payload onto preferences and names the changed ones
+func mergePreferences(preferences userEntity.Preferences, payload userRequest.UpdatePreferences) (userEntity.Preferences, []string) {
+	// Start from the defaults shown to the user until the first change
+	if preferences.UpdatedAt.IsZero() {
+		preferences.NotificationChannels.Email = true
+	}
+	var changedFields []string
+	if payload.Locale != nil {
+		preferences.Locale = *payload.Locale
+		changedFields = append(changedFields, "locale")
+	}
+	if payload.Timezone != nil {
+		preferences.Timezone = *payload.Timezone
+		changedFields = append(changedFields, "timezone")
+	}
+	if payload.NotificationChannels != nil {
+		if payload.NotificationChannels.Email != nil {
+			preferences.NotificationChannels.Email = *payload.NotificationChannels.Email
+		}
+		if payload.NotificationChannels.Sms != nil {
+			preferences.NotificationChannels.Sms = *payload.NotificationChannels.Sms
+		}
+		if payload.NotificationChannels.Push != nil {
+			preferences.NotificationChannels.Push = *payload.NotificationChannels.Push
+		}
+		changedFields = append(changedFields, "notificationChannels")
+	}
+	if payload.FavouriteGenres != nil {
+		preferences.FavouriteGenres = uniqueValues(payload.FavouriteGenres)
+		changedFields = append(changedFields, "favouriteGenres")
+	}
+	if payload.FavouriteArtists != nil {
+		preferences.FavouriteArtists = uniqueValues(payload.FavouriteArtists)
+		changedFields = append(changedFields, "favouriteArtists")
+	}
+	preferences.UpdatedAt = time.Now()
+	return preferences, changedFields
+}
+
+func newConsent(userId string, purpose string, granted bool, version string, source string) userEntity.Consent {
+	now := time.Now()
+	consent := userEntity.Consent{
+		UserId:    userId,
+		Purpose:   purpose,
+		Granted:   granted,
+		Version:   version,
+		Source:    source,
+		UpdatedAt: now,
+	}
+	if granted {
+		consent.GrantedAt = now
+	}
+	return consent
+}
+
+func currentTermsVersion() string {
+	if version := configs.GetConfig().Consent.TermsVersion; version != "" {
+		return version
+	}
+	return "1.0"
+}
+
+func currentPrivacyPolicyVersion() string {
+	if version := configs.GetConfig().Consent.PrivacyPolicyVersion; version != "" {
+		return version
+	}
+	return "1.0"
+}
+
+func (c commandUsecase) DeleteAccount(origCtx context.Context, payload userRequest.DeleteAccount, userId string) (*userResponse.DeleteAccount, error) {
+	domain := "userUsecase-DeleteAccount"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, userId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, userId)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if userData.Status == userEntity.StatusDeleted {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, userId)
+		return nil, errors.NotFound(msg)
+	}
+
+	passwordHash := helpers.GeneratePassword(payload.Password)
+	if passwordHash != userData.Password {
+		msg := "Password not match"
+		c.logger.Info(ctx, msg, userId)
+		return nil, errors.BadRequest(msg)
+	}
+
+	// Repeated requests keep the original schedule
+	if userData.Status != userEntity.StatusPendingDeletion {
+		err := c.saveProfile(ctx, userData, func(user *userEntity.User) {
+			if user.Status == userEntity.StatusPendingDeletion {
+				return
+			}
+			now := time.Now()
+			user.Status = userEntity.StatusPendingDeletion
+			user.Deletion = userEntity.Deletion{
+				RequestedAt: now,
+				ScheduledAt: now.Add(accountDeletionGracePeriod()),
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &userResponse.DeleteAccount{
+		UserId:      userData.UserId,
+		Status:      userData.Status,
+		ScheduledAt: userData.Deletion.ScheduledAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ProcessAccountDeletion anonymizes every account whose grace period has passed.
+// The userId is kept as a tombstone so orders in other services still resolve.
+func (c commandUsecase) ProcessAccountDeletion(origCtx context.Context) (int, error) {
+	domain := "userUsecase-ProcessAccountDeletion"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	now := time.Now()
+	resp := <-c.userRepositoryQuery.FindUsersDueForDeletion(ctx, now, 100)
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+	if resp.Data == nil {
+		return 0, nil
+	}
+	users, ok := resp.Data.(*[]userEntity.User)
+	if !ok {
+		return 0, errors.InternalServerError("cannot parsing data")
+	}
+
+	processed := 0
+	for _, userData := range *users {
+		// Each archive and saved address is a copy of the personal data, they go before the tombstone so a
+		// failure is retried on the next run while the account is still pending deletion
+		respExports := <-c.userRepositoryCommand.DeleteDataExportsByUserId(ctx, userData.UserId)
+		if respExports.Error != nil {
+			c.logger.Error(ctx, "Error erase data exports", userData.UserId)
+			continue
+		}
+		deletedAt := time.Now()
+		respAddresses := <-c.userRepositoryCommand.EraseAddressesByUserId(ctx, userData.UserId, deletedAt)
+		if respAddresses.Error != nil {
+			c.logger.Error(ctx, "Error erase address book", userData.UserId)
+			continue
+		}
+		tombstone := userEntity.User{
+			UserId:    userData.UserId,
+			FullName:  "Deleted User",
+			Email:     fmt.Sprintf("deleted-%s@anonymized.invalid", userData.UserId),
+			Role:      userData.Role,
+			Status:    userEntity.StatusDeleted,
+			LoginAt:   userData.LoginAt,
+			CreatedAt: userData.CreatedAt,
+			UpdatedAt: deletedAt,
+			Version:   userData.Version + 1,
+			Deletion: userEntity.Deletion{
+				RequestedAt: userData.Deletion.RequestedAt,
+				ScheduledAt: userData.Deletion.ScheduledAt,
+				DeletedAt:   deletedAt,
+			},
+		}
+		// A login cancelling the deletion after the read above must win over the tombstone
+		respUser := <-c.userRepositoryCommand.UpdateOneUserDueForDeletion(ctx, tombstone, now)
+		if respUser.Error != nil {
+			c.logger.Error(ctx, "Error anonymize user", userData.UserId)
+			continue
+		}
+		if respUser.Count == 0 {
+			c.logger.Info(ctx, "Account deletion cancelled before anonymization", userData.UserId)
+			continue
+		}
+		c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyGetProfileUser, userData.UserId))
+		c.deleteBlobs(ctx, userData.Avatar.Keys)
+		c.revokeMarketingConsents(ctx, userData.UserId)
+
+		kafkaData := struct {
+			UserId    string `json:"userId"`
+			DeletedAt string `json:"deletedAt"`
+		}{
+			UserId:    userData.UserId,
+			DeletedAt: deletedAt.Format(time.RFC3339),
+		}
+		marshaledKafkaData, _ := json.Marshal(kafkaData)
+		c.kafkaProducer.Publish(constants.KafkaTopicUserDeleted, marshaledKafkaData, nil)
+		c.logger.Info(ctx, fmt.Sprintf("Send kafka user deleted, topic: %s", constants.KafkaTopicUserDeleted), userData.UserId)
+		processed++
+	}
+
+	return processed, nil
+}
+
+// revokeMarketingConsents withdraws the marketing opt-ins of an anonymized account, a failure is only
+// logged since the consent of an account that is not active is never honored
+func (c commandUsecase) revokeMarketingConsents(ctx context.Context, userId string) {
+	for _, purpose := range []string{userEntity.ConsentPurposeMarketingEmail, userEntity.ConsentPurposeMarketingSms} {
+		respConsent := <-c.userRepositoryCommand.UpsertOneConsent(ctx,
+			newConsent(userId, purpose, false, currentPrivacyPolicyVersion(), userEntity.ConsentSourceDeletion))
+		if respConsent.Error != nil {
+			c.logger.Error(ctx, "Error revoke marketing consent", userId, log.String("purpose", purpose))
+		}
+	}
+}
+
+func accountDeletionGracePeriod() time.Duration {
+	graceDays := 30
+	parseGraceDays, err := strconv.Atoi(configs.GetConfig().AccountDeletion.GracePeriodDays)
+	if err == nil && parseGraceDays >= 0 {
+		graceDays = parseGraceDays
+	}
+	return time.Duration(graceDays) * 24 * time.Hour
+}
+
+func (c commandUsecase) RequestDataExport(origCtx context.Context, payload userRequest.RequestDataExport, userId string) (*userResponse.DataExport, error) {
+	domain := "userUsecase-RequestDataExport"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	// Only one export per user per day
+	limitKey := fmt.Sprintf("%s:%s", constants.RedisKeyDataExportLimit, userId)
+	allowed, _ := c.redis.SetNX(ctx, limitKey, 1, 24*time.Hour).Result()
+	if !allowed {
+		msg := "Data export already requested, please try again later"
+		c.logger.Info(ctx, msg, userId)
+		return nil, errors.TooManyRequest(msg)
+	}
+
+	dataExport := userEntity.DataExport{
+		ExportId:    uuid.New().String(),
+		UserId:      userId,
+		Format:      helpers.CustomIfEmpty(payload.Format, "json"),
+		Status:      userEntity.DataExportStatusPending,
+		RequestedAt: time.Now(),
+	}
+	respExport := <-c.userRepositoryCommand.UpsertOneDataExport(ctx, dataExport)
+	if respExport.Error != nil {
+		c.redis.Del(ctx, limitKey)
+		return nil, respExport.Error
+	}
+	c.logger.Info(ctx, "Data export requested", map[string]string{"exportId": dataExport.ExportId, "userId": userId})
+
+	return &userResponse.DataExport{
+		ExportId:    dataExport.ExportId,
+		Status:      dataExport.Status,
+		Format:      dataExport.Format,
+		RequestedAt: dataExport.RequestedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ProcessDataExport generates the archive of every pending data export request
+func (c commandUsecase) ProcessDataExport(origCtx context.Context) (int, error) {
+	domain := "userUsecase-ProcessDataExport"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-c.userRepositoryQuery.FindDataExportsByStatus(ctx, userEntity.DataExportStatusPending, 20)
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+	if resp.Data == nil {
+		return 0, nil
+	}
+	dataExports, ok := resp.Data.(*[]userEntity.DataExport)
+	if !ok {
+		return 0, errors.InternalServerError("cannot parsing data")
+	}
+
+	processed := 0
+	for _, dataExport := range *dataExports {
+		archive, err := c.generateDataExportArchive(ctx, dataExport)
+		now := time.Now()
+		if err != nil {
+			c.logger.Error(ctx, "Error generate data export", map[string]string{"exportId": dataExport.ExportId, "error": err.Error()})
+			dataExport.Status = userEntity.DataExportStatusFailed
+			// The daily limit only counts exports the user got, a failure on our side lets them ask again
+			c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyDataExportLimit, dataExport.UserId))
+		} else {
+			dataExport.Status = userEntity.DataExportStatusReady
+			dataExport.Archive = archive
+			dataExport.ExpiredAt = now.Add(7 * 24 * time.Hour)
+		}
+		dataExport.CompletedAt = now
+
+		respExport := <-c.userRepositoryCommand.UpsertOneDataExport(ctx, dataExport)
+		if respExport.Error != nil {
+			c.logger.Error(ctx, "Error update data export", dataExport.ExportId)
+			continue
+		}
+		if dataExport.Status == userEntity.DataExportStatusReady {
+			c.logger.Info(ctx, "Data export generated", map[string]string{"exportId": dataExport.ExportId, "userId": dataExport.UserId})
+			processed++
+		}
+	}
+
+	return processed, nil
+}
+
+func (c commandUsecase) generateDataExportArchive(ctx context.Context, dataExport userEntity.DataExport) ([]byte, error) {
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, dataExport.UserId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		return nil, errors.NotFound("User not found")
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	auditEntries := []userResponse.DataExportAuditEntry{
+		{Action: "account registered", At: userData.CreatedAt.Format(time.RFC3339)},
+		{Action: "profile updated", At: userData.UpdatedAt.Format(time.RFC3339)},
+	}
+	if !userData.Deletion.RequestedAt.IsZero() {
+		auditEntries = append(auditEntries, userResponse.DataExportAuditEntry{
+			Action: "account deletion requested", At: userData.Deletion.RequestedAt.Format(time.RFC3339),
+		})
+	}
+	respExports := <-c.userRepositoryQuery.FindDataExportsByUserId(ctx, dataExport.UserId)
+	if respExports.Error != nil {
+		return nil, respExports.Error
+	}
+	if exports, ok := respExports.Data.(*[]userEntity.DataExport); ok {
+		for _, value := range *exports {
+			auditEntries = append(auditEntries, userResponse.DataExportAuditEntry{
+				Action: fmt.Sprintf("data export requested (%s)", value.Format), At: value.RequestedAt.Format(time.RFC3339),
+			})
+		}
+	}
+
+	consents := []userResponse.DataExportConsent{}
+	respConsents := <-c.userRepositoryQuery.FindConsentsByUserId(ctx, dataExport.UserId)
+	if respConsents.Error != nil {
+		return nil, respConsents.Error
+	}
+	if data, ok := respConsents.Data.(*[]userEntity.Consent); ok {
+		for _, value := range *data {
+			consents = append(consents, userResponse.DataExportConsent{
+				Purpose:   value.Purpose,
+				Granted:   value.Granted,
+				Version:   value.Version,
+				Source:    value.Source,
+				UpdatedAt: value.UpdatedAt.Format(time.RFC3339),
+			})
+			auditEntries = append(auditEntries, userResponse.DataExportAuditEntry{
+				Action: fmt.Sprintf("consent %s updated (%s)", value.Purpose, value.Source), At: value.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+	}
+
+	addressBook := []userResponse.Address{}
+	respAddresses := <-c.userRepositoryQuery.FindAddressesByUserId(ctx, dataExport.UserId)
+	if respAddresses.Error != nil {
+		return nil, respAddresses.Error
+	}
+	if entries, ok := respAddresses.Data.(*[]userEntity.AddressBookEntry); ok && entries != nil {
+		for _, entry := range *entries {
+			addressBook = append(addressBook, addressResponse(entry))
+		}
+	}
+
+	archive := userResponse.DataExportArchive{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Profile: userResponse.DataExportProfile{
+			UserId:       userData.UserId,
+			FullName:     userData.FullName,
+			Email:        userData.Email,
+			NIK:          userData.NIK,
+			MobileNumber: userData.MobileNumber,
+			Role:         userData.Role,
+			Status:       userData.Status,
+			CreatedAt:    userData.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    userData.UpdatedAt.Format(time.RFC3339),
+		},
+		Address: userResponse.DataExportAddress{
+			Address:       userData.Address,
+			RtRw:          userData.RtRw,
+			SubdistrictId: userData.Subdistrict.Id,
+			Subdistrict:   userData.Subdistrict.Name,
+			DistrictId:    userData.Subdistrict.DistrictId,
+			District:      userData.Subdistrict.DistrictName,
+			CityId:        userData.Subdistrict.CityId,
+			City:          userData.Subdistrict.CityName,
+			ProvinceId:    userData.Subdistrict.ProvinceId,
+			Province:      userData.Subdistrict.ProvinceName,
+			PostalCode:    userData.Subdistrict.PostalCode,
+			CountryCode:   userData.Country.Code,
+			Country:       userData.Country.Name,
+			Continent:     userData.Country.ContinentName,
+			Latitude:      userData.Country.Latitude,
+			Longitude:     userData.Country.Longitude,
+		},
+		AddressBook: addressBook,
+		Preferences: preferencesResponse(userData.Preferences),
+		Consents:    consents,
+		Sessions: []userResponse.DataExportSession{
+			{LoginAt: userData.LoginAt.Format(time.RFC3339)},
+		},
+		AuditEntries: auditEntries,
+	}
+
+	jsonData, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	jsonFile, err := zipWriter.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := jsonFile.Write(jsonData); err != nil {
+		return nil, err
+	}
+	if dataExport.Format == "csv" {
+		csvFile, err := zipWriter.Create("data.csv")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeDataExportCsv(csvFile, jsonData); err != nil {
+			return nil, err
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeDataExportCsv flattens the json archive into field,value rows
+func writeDataExportCsv(w io.Writer, jsonData []byte) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return err
+	}
+	rows := map[string]string{}
+	flattenDataExport("", data, rows)
+
+	fields := make([]string, 0, len(rows))
+	for field := range rows {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write([]string{"field", "value"})
+	for _, field := range fields {
+		csvWriter.Write([]string{field, rows[field]})
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func flattenDataExport(prefix string, value interface{}, rows map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenDataExport(strings.TrimPrefix(prefix+"."+key, "."), child, rows)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenDataExport(fmt.Sprintf("%s.%d", prefix, i), child, rows)
+		}
+	case nil:
+		rows[prefix] = ""
+	default:
+		rows[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+// resolveLocation validates the address hierarchy and coordinates and converts them to the embedded user form
+func (c commandUsecase) resolveLocation(ctx context.Context, payload addressRequest.Hierarchy) (userEntity.Country, userEntity.Subdistrict, error) {
+	hierarchy, err := c.addressUsecaseQuery.ValidateHierarchy(ctx, payload)
+	if err != nil {
+		return userEntity.Country{}, userEntity.Subdistrict{}, err
+	}
+
+	country := userEntity.Country{
+		Id:            hierarchy.Country.Id,
+		Code:          hierarchy.Country.Code,
+		Name:          hierarchy.Country.Name,
+		FullName:      hierarchy.Country.FullName,
+		ContinentId:   hierarchy.Country.ContinentCode,
+		ContinentName: hierarchy.Country.ContinentName,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	}
+	if hierarchy.Location != nil {
+		country.Location = &userEntity.GeoPoint{
+			Type:        "Point",
+			Coordinates: []float64{hierarchy.Location.Longitude, hierarchy.Location.Latitude},
+		}
+	}
+	var subdistrict userEntity.Subdistrict
+	if hierarchy.SubDistrict != nil {
+		subdistrict = userEntity.Subdistrict{
+			Id:           hierarchy.SubDistrict.Id,
+			Name:         hierarchy.SubDistrict.Name,
+			DistrictId:   hierarchy.SubDistrict.DistrictId,
+			DistrictName: hierarchy.SubDistrict.DistrictName,
+			CityId:       hierarchy.SubDistrict.CityId,
+			CityName:     hierarchy.SubDistrict.CityName,
+			ProvinceId:   hierarchy.SubDistrict.ProvinceId,
+			ProvinceName: hierarchy.SubDistrict.ProvinceName,
+			PostalCode:   hierarchy.SubDistrict.PostalCode,
+		}
+	}
+	return country, subdistrict, nil
+}
+
+const maxAddressBookEntries = 10
+
+func (c commandUsecase) CreateAddress(origCtx context.Context, payload userRequest.SaveAddress, userId string) (*userResponse.Address, error) {
+	domain := "userUsecase-CreateAddress"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	userData, err := c.findActiveUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadAddressBook(ctx, c.userRepositoryQuery, c.userRepositoryCommand, *userData)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) >= maxAddressBookEntries {
+		msg := fmt.Sprintf("Address book is limited to %d addresses", maxAddressBookEntries)
+		c.logger.Error(ctx, msg, userId)
+		return nil, errors.UnprocessableEntity(msg)
+	}
+
+	country, subdistrict, err := c.resolveLocation(ctx, addressRequest.Hierarchy{
+		CountryId:     payload.CountryId,
+		SubdistrictId: payload.SubdictrictId,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := userEntity.AddressBookEntry{
+		AddressId:     uuid.New().String(),
+		UserId:        userId,
+		Label:         payload.Label,
+		RecipientName: payload.RecipientName,
+		MobileNumber:  helpers.VerifyPhoneNumber62(payload.MobileNumber),
+		Address:       payload.Address,
+		Subdistrict:   subdistrict,
+		Country:       country,
+		RtRw:          payload.RtRw,
+		// The first address of a user is always the default
+		IsDefault: payload.IsDefault || len(entries) == 0,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if entry.IsDefault {
+		if err := c.unsetDefaultAddress(ctx, userId, entry.AddressId); err != nil {
+			return nil, err
+		}
+	}
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, entry)
+	if respAddress.Error != nil {
+		return nil, respAddress.Error
+	}
+	if entry.IsDefault {
+		if err := c.mirrorDefaultAddress(ctx, userData, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	response := addressResponse(entry)
+	return &response, nil
+}
+
+func (c commandUsecase) UpdateAddress(origCtx context.Context, payload userRequest.SaveAddress, userId string) (*userResponse.Address, error) {
+	domain := "userUsecase-UpdateAddress"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	userData, err := c.findActiveUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := c.findAddress(ctx, userId, payload.AddressId)
+	if err != nil {
+		return nil, err
+	}
+
+	country, subdistrict, err := c.resolveLocation(ctx, addressRequest.Hierarchy{
+		CountryId:     payload.CountryId,
+		SubdistrictId: payload.SubdictrictId,
+		Latitude:      payload.Latitude,
+		Longitude:     payload.Longitude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wasDefault := entry.IsDefault
+	entry.Label = payload.Label
+	entry.RecipientName = payload.RecipientName
+	entry.MobileNumber = helpers.VerifyPhoneNumber62(payload.MobileNumber)
+	entry.Address = payload.Address
+	entry.Subdistrict = subdistrict
+	entry.Country = country
+	entry.RtRw = payload.RtRw
+	// The default can only move to another address, never be cleared
+	entry.IsDefault = wasDefault || payload.IsDefault
+	entry.UpdatedAt = time.Now()
+
+	if entry.IsDefault && !wasDefault {
+		if err := c.unsetDefaultAddress(ctx, userId, entry.AddressId); err != nil {
+			return nil, err
+		}
+	}
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, *entry)
+	if respAddress.Error != nil {
+		return nil, respAddress.Error
+	}
+	if entry.IsDefault {
+		if err := c.mirrorDefaultAddress(ctx, userData, *entry); err != nil {
+			return nil, err
+		}
+	}
+
+	response := addressResponse(*entry)
+	return &response, nil
+}
+
+func (c commandUsecase) SetDefaultAddress(origCtx context.Context, payload userRequest.AddressBookEntry) (*userResponse.Address, error) {
+	domain := "userUsecase-SetDefaultAddress"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	userData, err := c.findActiveUser(ctx, payload.UserId)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := c.findAddress(ctx, payload.UserId, payload.AddressId)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDefault {
+		response := addressResponse(*entry)
+		return &response, nil
+	}
+
+	if err := c.unsetDefaultAddress(ctx, payload.UserId, entry.AddressId); err != nil {
+		return nil, err
+	}
+	entry.IsDefault = true
+	entry.UpdatedAt = time.Now()
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, *entry)
+	if respAddress.Error != nil {
+		return nil, respAddress.Error
+	}
+	if err := c.mirrorDefaultAddress(ctx, userData, *entry); err != nil {
+		return nil, err
+	}
+
+	response := addressResponse(*entry)
+	return &response, nil
+}
+
+func (c commandUsecase) DeleteAddress(origCtx context.Context, payload userRequest.AddressBookEntry) error {
+	domain := "userUsecase-DeleteAddress"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	userData, err := c.findActiveUser(ctx, payload.UserId)
+	if err != nil {
+		return err
+	}
+	entry, err := c.findAddress(ctx, payload.UserId, payload.AddressId)
+	if err != nil {
+		return err
+	}
+
+	wasDefault := entry.IsDefault
+	entry.IsDefault = false
+	entry.Deleted = true
+	entry.UpdatedAt = time.Now()
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, *entry)
+	if respAddress.Error != nil {
+		return respAddress.Error
+	}
+	if !wasDefault {
+		return nil
+	}
+
+	// Promote the oldest remaining address, the profile keeps the last address when none is left
+	respAddresses := <-c.userRepositoryQuery.FindAddressesByUserId(ctx, payload.UserId)
+	if respAddresses.Error != nil {
+		return respAddresses.Error
+	}
+	entries, ok := respAddresses.Data.(*[]userEntity.AddressBookEntry)
+	if !ok || entries == nil || len(*entries) == 0 {
+		return nil
+	}
+	next := (*entries)[0]
+	next.IsDefault = true
+	next.UpdatedAt = time.Now()
+	respNext := <-c.userRepositoryCommand.UpsertOneAddress(ctx, next)
+	if respNext.Error != nil {
+		return respNext.Error
+	}
+	return c.mirrorDefaultAddress(ctx, userData, next)
+}
+
+func (c commandUsecase) findActiveUser(ctx context.Context, userId string) (*userEntity.User, error) {
+	resp := <-c.userRepositoryQuery.FindOneUserId(ctx, userId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, userId)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := resp.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if userData.Status == userEntity.StatusDeleted {
+		msg := "User not found"
+		c.logger.Error(ctx, msg, userId)
+		return nil, errors.NotFound(msg)
+	}
+	// The address book is erased with the account, it cannot change while the deletion is scheduled
+	if userData.Status == userEntity.StatusPendingDeletion {
+		msg := "Account is pending deletion"
+		c.logger.Info(ctx, msg, userId)
+		return nil, errors.ForbiddenError(msg)
+	}
+	return userData, nil
+}
+
+func (c commandUsecase) findAddress(ctx context.Context, userId string, addressId string) (*userEntity.AddressBookEntry, error) {
+	resp := <-c.userRepositoryQuery.FindOneAddress(ctx, userId, addressId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "Address not found"
+		c.logger.Error(ctx, msg, addressId)
+		return nil, errors.NotFound(msg)
+	}
+	entry, ok := resp.Data.(*userEntity.AddressBookEntry)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data address")
+	}
+	return entry, nil
+}
+
+// unsetDefaultAddress clears the current default unless it is the address becoming default
+func (c commandUsecase) unsetDefaultAddress(ctx context.Context, userId string, addressId string) error {
+	resp := <-c.userRepositoryQuery.FindDefaultAddress(ctx, userId)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	previous, ok := resp.Data.(*userEntity.AddressBookEntry)
+	if !ok || previous == nil || previous.AddressId == addressId {
+		return nil
+	}
+	previous.IsDefault = false
+	previous.UpdatedAt = time.Now()
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, *previous)
+	return respAddress.Error
+}
+
+// mirrorDefaultAddress copies the default address onto the profile so GetProfile keeps showing it
+func (c commandUsecase) mirrorDefaultAddress(ctx context.Context, userData *userEntity.User, entry userEntity.AddressBookEntry) error {
+	return c.saveProfile(ctx, userData, func(user *userEntity.User) {
+		user.Address = entry.Address
+		user.RtRw = entry.RtRw
+		user.Subdistrict = entry.Subdistrict
+		user.Country = entry.Country
+	})
+}
+
+const maxProfileWriteAttempts = 3
+
+// saveProfile applies change to userData and writes it only if the stored version is still the one it
+// was read at. When another request wrote in between, the profile is read again and change is applied
+// on top of it, a profile still changing after maxProfileWriteAttempts is a conflict.
+func (c commandUsecase) saveProfile(ctx context.Context, userData *userEntity.User, change func(user *userEntity.User)) error {
+	for attempt := 1; ; attempt++ {
+		change(userData)
+		userData.UpdatedAt = time.Now()
+		expectedVersion := userData.Version
+		userData.Version++
+		respUser := <-c.userRepositoryCommand.UpdateOneUserWithVersion(ctx, *userData, expectedVersion)
+		if respUser.Error != nil {
+			return respUser.Error
+		}
+		if respUser.Count > 0 {
+			c.redis.Del(ctx, fmt.Sprintf("%s:%s", constants.RedisKeyGetProfileUser, userData.UserId))
+			return nil
+		}
+		if attempt == maxProfileWriteAttempts {
+			msg := "Profile is being modified, try again"
+			c.logger.Info(ctx, msg, userData.UserId)
+			return errors.Conflict(msg)
+		}
+
+		resp := <-c.userRepositoryQuery.FindOneUserId(ctx, userData.UserId)
+		if resp.Error != nil {
+			return resp.Error
+		}
+		current, ok := resp.Data.(*userEntity.User)
+		if !ok || current == nil {
+			msg := "User not found"
+			c.logger.Error(ctx, msg, userData.UserId)
+			return errors.NotFound(msg)
+		}
+		*userData = *current
+	}
+}
+
+// syncDefaultAddress copies a profile address change onto the default address book entry
+func (c commandUsecase) syncDefaultAddress(ctx context.Context, userData userEntity.User) {
+	resp := <-c.userRepositoryQuery.FindDefaultAddress(ctx, userData.UserId)
+	if resp.Error != nil {
+		c.logger.Error(ctx, "Error find default address", userData.UserId)
+		return
+	}
+	entry, ok := resp.Data.(*userEntity.AddressBookEntry)
+	if !ok || entry == nil {
+		return
+	}
+	entry.Address = userData.Address
+	entry.RtRw = userData.RtRw
+	entry.Subdistrict = userData.Subdistrict
+	entry.Country = userData.Country
+	entry.UpdatedAt = time.Now()
+	respAddress := <-c.userRepositoryCommand.UpsertOneAddress(ctx, *entry)
+	if respAddress.Error != nil {
+		c.logger.Error(ctx, "Error sync default address", userData.UserId)
+	}
+}
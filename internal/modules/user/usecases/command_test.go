@@ -1,7 +1,12 @@
 package usecases_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"image"
+	"image/png"
 	"testing"
 	"time"
 
@@ -9,16 +14,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 
 	addressEntity "user-service/internal/modules/address/models/entity"
+	addressUsecases "user-service/internal/modules/address/usecases"
 	"user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	userRequest "user-service/internal/modules/user/models/request"
+	userResponse "user-service/internal/modules/user/models/response"
 	uc "user-service/internal/modules/user/usecases"
 	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
 	mockcertAddress "user-service/mocks/modules/address"
 	mockcert "user-service/mocks/modules/user"
+	mockblobstore "user-service/mocks/pkg/blobstore"
 	mockjwt "user-service/mocks/pkg/helpers"
 	mockkafka "user-service/mocks/pkg/kafka"
 	mocklog "user-service/mocks/pkg/log"
@@ -34,6 +44,7 @@ type CommandUsecaseTestSuite struct {
 	mockRedis                  *mockredis.Collections
 	mockKafkaProducer          *mockkafka.Producer
 	mockJwt                    *mockjwt.TokenGenerator
+	mockBlobStore              *mockblobstore.BlobStore
 	usecase                    user.UsecaseCommand
 	ctx                        context.Context
 }
@@ -46,6 +57,7 @@ func (suite *CommandUsecaseTestSuite) SetupTest() {
 	suite.mockRedis = &mockredis.Collections{}
 	suite.mockKafkaProducer = &mockkafka.Producer{}
 	suite.mockJwt = &mockjwt.TokenGenerator{}
+	suite.mockBlobStore = &mockblobstore.BlobStore{}
 	suite.ctx = context.Background()
 	suite.usecase = uc.NewCommandUsecase(
 		suite.mockUserRepositoryQuery,
@@ -54,7 +66,9 @@ func (suite *CommandUsecaseTestSuite) SetupTest() {
 		suite.mockRedis,
 		suite.mockKafkaProducer,
 		suite.mockJwt,
-		suite.mockAddressRepositoryQuery,
+		// The real address validator over the mocked address repository
+		addressUsecases.NewQueryUsecase(suite.mockAddressRepositoryQuery, suite.mockLogger),
+		suite.mockBlobStore,
 	)
 	array := [][]string{{}, {"yopmail.com"}}
 	helpers.CreateBlackListEmail(array)
@@ -67,20 +81,22 @@ func TestCommandUsecaseTestSuite(t *testing.T) {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserSuccess() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 
 	// Define a mock user repository query function
@@ -127,7 +143,7 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserSuccess() {
 	subdistrictData := &addressEntity.SubDistrict{
 		Id:           "Id",
 		Name:         "Name",
-		DistrictId:   "DistricId",
+		DistrictId:   "DistrictId",
 		DistrictName: "DistrictName",
 		CityId:       "CityId",
 		CityName:     "CityName",
@@ -165,7 +181,9 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserSuccess() {
 	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockFindOneByEmail)
 	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockFindOneCountry)
 	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", suite.ctx, payload.SubdictrictId).Return(mockFindOneSubdistrict)
-	suite.mockUserRepositoryCommand.On("UpsertOneUserTemp", mock.Anything, mock.Anything).Return(mockUpsertOneUserTemp)
+	suite.mockUserRepositoryCommand.On("UpsertOneUserTemp", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return len(u.PendingConsents) == 4 && u.PendingConsents[0].Purpose == userEntity.ConsentPurposeTerms
+	})).Return(mockUpsertOneUserTemp)
 	suite.mockKafkaProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockRedis.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -174,25 +192,28 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserSuccess() {
 	// Assert
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), payload.Email, payload.Email)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneConsent", mock.Anything, mock.Anything)
 
 }
 
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrPayloadCountry() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -220,20 +241,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrPayloadCountry() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountry() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -261,20 +284,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountry() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountryNil() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -303,20 +328,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountryNil() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountryParse() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -344,20 +371,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindCountryParse() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindSubdistrict() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -403,20 +432,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindSubdistrict() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindSubdistrictNil() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -463,20 +494,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindSubdistrictNil() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserErrFindSubdistrictParse() {
 	// Arrange user request register
 	payload := userRequest.RegisterUser{
-		FullName:      "Alif Septian",
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	mockFindOneByEmail := helpers.Result{
 		Data:  nil,
@@ -525,20 +558,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserValidationFailed() {
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 
 	payload := userRequest.RegisterUser{
-		Email:         "invalidemail.com",
-		Password:      "password",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		FullName:      "Full Name",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "32",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		Email:                "invalidemail.com",
+		Password:             "password",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		FullName:             "Full Name",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "32",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	_, err := suite.usecase.RegisterUser(suite.ctx, payload)
 
@@ -593,19 +628,21 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserValidationFailed() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserEmailRegistered() {
 	// Validation find email already register
 	payload := userRequest.RegisterUser{
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		FullName:      "Full Name",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		FullName:             "Full Name",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 	// Define a mock user repository query function
 	mockFindOneByEmail := func(ctx context.Context, email string) <-chan helpers.Result {
@@ -634,20 +671,22 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserEmailRegistered() {
 func (suite *CommandUsecaseTestSuite) TestRegisterUserUpsertOneError() {
 	// Arrange
 	payload := userRequest.RegisterUser{
-		Email:         "alif@gmail.com",
-		Password:      "Password1@",
-		FullName:      "Full Name",
-		NIK:           "12312131131",
-		MobileNumber:  "081281015121",
-		ProvinceId:    "123",
-		CityId:        "123",
-		DistrictId:    "123",
-		SubdictrictId: "123",
-		CountryId:     "1",
-		Address:       "Jalan jalan",
-		RtRw:          "12/12",
-		Role:          "user",
-		KKNumber:      "1212121212",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		FullName:             "Full Name",
+		NIK:                  "12312131131",
+		MobileNumber:         "081281015121",
+		ProvinceId:           "ProvinceId",
+		CityId:               "CityId",
+		DistrictId:           "DistrictId",
+		SubdictrictId:        "123",
+		CountryId:            "1",
+		Address:              "Jalan jalan",
+		RtRw:                 "12/12",
+		Role:                 "user",
+		KKNumber:             "1212121212",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
 	}
 
 	mockFindOneByEmail := helpers.Result{
@@ -674,7 +713,7 @@ func (suite *CommandUsecaseTestSuite) TestRegisterUserUpsertOneError() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -734,17 +773,27 @@ func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserSuccess() {
 			},
 			UpdatedAt: time.Now(),
 			UserId:    "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			PendingConsents: []userEntity.Consent{
+				{UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "1.0"},
+				{UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", Purpose: userEntity.ConsentPurposePrivacyPolicy, Granted: true, Version: "1.0"},
+			},
 		},
 		Error: nil,
 	}
 	suite.mockUserRepositoryQuery.On("FindOneByEmailUserTemp", mock.Anything, payload.Email).Return(mockChannel(mockUserQueryResponse))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
 
 	mockUserCommandResponse := helpers.Result{
 		Data:  nil,
 		Error: nil,
 	}
 
-	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUserCommandResponse))
+	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Status == userEntity.StatusActive && u.PendingConsents == nil
+	})).Return(mockChannel(mockUserCommandResponse))
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.MatchedBy(func(c userEntity.Consent) bool {
+		return c.UserId == "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980"
+	})).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
 	suite.mockRedis.On("Del", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewIntResult(1, nil))
 	// suite.mockJwt.On("GenerateToken", mock.Anything, mock.Anything).Return("mockedToken", "mockedExpiredAt", nil)
 	// suite.mockJwt.On("GenerateTokenRefresh", mock.Anything, mock.Anything).Return("mockedToken", nil)
@@ -754,6 +803,66 @@ func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserSuccess() {
 
 	// Assert
 	assert.NoError(suite.T(), err)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneConsent", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserErrUpsertConsent() {
+	payload := userRequest.VerifyRegisterUser{
+		Email: "alif@gmail.com",
+		Otp:   "123456",
+	}
+	mockUserQueryResponse := helpers.Result{
+		Data: &userEntity.User{
+			Email:  "alif@gmail.com",
+			UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			PendingConsents: []userEntity.Consent{
+				{UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "1.0"},
+			},
+		},
+		Error: nil,
+	}
+	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("123456", nil))
+	suite.mockUserRepositoryQuery.On("FindOneByEmailUserTemp", mock.Anything, payload.Email).Return(mockChannel(mockUserQueryResponse))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Data: nil, Error: errors.InternalServerError("Error")}))
+	// Act
+	_, err := suite.usecase.VerifyRegisterUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	suite.mockRedis.AssertNotCalled(suite.T(), "Del", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserRetryKeepsAccount() {
+	payload := userRequest.VerifyRegisterUser{
+		Email: "alif@gmail.com",
+		Otp:   "123456",
+	}
+	mockUserQueryResponse := helpers.Result{
+		Data: &userEntity.User{
+			Email:    "alif@gmail.com",
+			FullName: "alif",
+			UserId:   "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			PendingConsents: []userEntity.Consent{
+				{UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "1.0"},
+			},
+		},
+		Error: nil,
+	}
+	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("123456", nil))
+	suite.mockUserRepositoryQuery.On("FindOneByEmailUserTemp", mock.Anything, payload.Email).Return(mockChannel(mockUserQueryResponse))
+	// The first attempt created the account and it was renamed before the retry
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(helpers.Result{
+		Data: &userEntity.User{Email: "alif@gmail.com", FullName: "Alif Renamed", UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", Version: 2},
+	}))
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockRedis.On("Del", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewIntResult(1, nil))
+	// Act
+	_, err := suite.usecase.VerifyRegisterUser(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneUser", mock.Anything, mock.Anything)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneConsent", 1)
 }
 
 func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserValidationFailed() {
@@ -882,6 +991,7 @@ func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserErrUpsertOne() {
 		Error: nil,
 	}
 	suite.mockUserRepositoryQuery.On("FindOneByEmailUserTemp", mock.Anything, payload.Email).Return(mockChannel(mockUserQueryResponse))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
 
 	mockUserCommandResponse := helpers.Result{
 		Data:  nil,
@@ -895,6 +1005,17 @@ func (suite *CommandUsecaseTestSuite) TestVerifyRegisterUserErrUpsertOne() {
 }
 
 // Helper function to create a channel
+// storesNoLocation is true when the country is written with an explicit null location, the $set of
+// the document then removes a point stored before
+func storesNoLocation(country userEntity.Country) bool {
+	raw, err := bson.Marshal(country)
+	if err != nil {
+		return false
+	}
+	location, err := bson.Raw(raw).LookupErr("location")
+	return err == nil && location.Type == bsontype.Null
+}
+
 func mockChannel(result helpers.Result) <-chan helpers.Result {
 	responseChan := make(chan helpers.Result)
 
@@ -906,6 +1027,16 @@ func mockChannel(result helpers.Result) <-chan helpers.Result {
 	return responseChan
 }
 
+func mockCurrentConsents() helpers.Result {
+	return helpers.Result{
+		Data: &[]userEntity.Consent{
+			{Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "1.0"},
+			{Purpose: userEntity.ConsentPurposePrivacyPolicy, Granted: true, Version: "1.0"},
+		},
+		Error: nil,
+	}
+}
+
 func (suite *CommandUsecaseTestSuite) TestLoginUserSuccess() {
 	// Arrange user request register
 	payload := userRequest.LoginUser{
@@ -948,6 +1079,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserSuccess() {
 	}
 	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("3", nil))
 	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, mock.Anything).Return(mockChannel(mockCurrentConsents()))
 	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
 	suite.mockKafkaProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
@@ -1039,7 +1171,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrFindEmailParse() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1102,7 +1234,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrPassword() {
 	assert.Error(suite.T(), err)
 }
 
-func (suite *CommandUsecaseTestSuite) TestLoginUserErrUpsert() {
+func (suite *CommandUsecaseTestSuite) TestLoginUserErrCancelDeletion() {
 	// Arrange user request register
 	payload := userRequest.LoginUser{
 		Email:    "alif@gmail.com",
@@ -1133,6 +1265,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrUpsert() {
 			},
 			UpdatedAt: time.Now(),
 			UserId:    "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			Status:    userEntity.StatusPendingDeletion,
 		},
 		Error: nil,
 	}
@@ -1144,7 +1277,8 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrUpsert() {
 	}
 	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("3", nil))
 	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
-	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, mock.Anything).Return(mockChannel(mockCurrentConsents()))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
 	suite.mockKafkaProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockRedis.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -1199,6 +1333,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrGenToken() {
 	}
 	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("3", nil))
 	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, mock.Anything).Return(mockChannel(mockCurrentConsents()))
 	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
 	suite.mockKafkaProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
@@ -1254,6 +1389,7 @@ func (suite *CommandUsecaseTestSuite) TestLoginUserErrGenTokenRefresh() {
 	}
 	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("3", nil))
 	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, mock.Anything).Return(mockChannel(mockCurrentConsents()))
 	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
 	suite.mockKafkaProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
@@ -1280,8 +1416,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUser() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1331,7 +1467,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUser() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1345,11 +1481,14 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUser() {
 	mockUpsertOneUser := helpers.Result{
 		Data:  nil,
 		Error: nil,
+		Count: 1,
 	}
 	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneUser))
 	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(mockFindOneCountry))
 	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneSubdistrict))
-	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 	// Act
 	_, err := suite.usecase.UpdateUser(suite.ctx, payload, mock.Anything)
@@ -1388,8 +1527,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErr() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1439,7 +1578,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErr() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1478,8 +1617,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrParse() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1516,7 +1655,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrParse() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1555,8 +1694,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountry() {
 		CountryId:     "country",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1606,7 +1745,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountry() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1645,8 +1784,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryDb() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1696,7 +1835,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryDb() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1735,8 +1874,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryNil() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1776,7 +1915,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryNil() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1815,8 +1954,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryParse() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1851,7 +1990,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryParse() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1865,7 +2004,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrCountryParse() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1904,8 +2043,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrSubdistrict() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -1955,7 +2094,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrSubdistrict() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -1994,8 +2133,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrSubdistrictNil() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -2075,8 +2214,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrSubdistrictParse() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -2166,8 +2305,8 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrUpsert() {
 		CountryId:     "1",
 		RtRw:          "RtRw",
 		Role:          "user",
-		Latitude:      "Latitude",
-		Longitude:     "Longitude",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
 	}
 
 	mockFindOneUser := helpers.Result{
@@ -2217,7 +2356,7 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrUpsert() {
 		Data: &addressEntity.SubDistrict{
 			Id:           "Id",
 			Name:         "Name",
-			DistrictId:   "DistricId",
+			DistrictId:   "DistrictId",
 			DistrictName: "DistrictName",
 			CityId:       "CityId",
 			CityName:     "CityName",
@@ -2235,10 +2374,1271 @@ func (suite *CommandUsecaseTestSuite) TestUpdateUserErrUpsert() {
 	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneUser))
 	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(mockFindOneCountry))
 	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneSubdistrict))
-	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
 	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
 	// Act
 	_, err := suite.usecase.UpdateUser(suite.ctx, payload, mock.Anything)
 	// Assert
 	assert.Error(suite.T(), err)
 }
+
+func (suite *CommandUsecaseTestSuite) TestDeleteAccountSuccess() {
+	payload := userRequest.DeleteAccount{
+		Password: "Password1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			Email:    "alif@gmail.com",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Role:     "user",
+			Status:   userEntity.StatusActive,
+			Version:  2,
+		},
+		Error: nil,
+	}
+	mockUpsertOneUser := helpers.Result{
+		Data:  nil,
+		Count: 1,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980").Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Status == userEntity.StatusPendingDeletion && u.Deletion.ScheduledAt.After(u.Deletion.RequestedAt) && u.Version == 3
+	}), int64(2)).Return(mockChannel(mockUpsertOneUser))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	// Act
+	resp, err := suite.usecase.DeleteAccount(suite.ctx, payload, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userEntity.StatusPendingDeletion, resp.Status)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeleteAccountErrPassword() {
+	payload := userRequest.DeleteAccount{
+		Password: "WrongPassword1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Status:   userEntity.StatusActive,
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneUser))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.DeleteAccount(suite.ctx, payload, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	// Assert
+	assert.Error(suite.T(), err, "Password not match")
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeleteAccountNotFound() {
+	payload := userRequest.DeleteAccount{
+		Password: "Password1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneUser))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.DeleteAccount(suite.ctx, payload, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	// Assert
+	assert.Error(suite.T(), err, "User not found")
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeleteAccountErrUpsert() {
+	payload := userRequest.DeleteAccount{
+		Password: "Password1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Status:   userEntity.StatusActive,
+		},
+		Error: nil,
+	}
+	mockUpsertOneUser := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("Error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, mock.Anything).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneUser))
+	// Act
+	_, err := suite.usecase.DeleteAccount(suite.ctx, payload, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	// Assert
+	assert.Error(suite.T(), err, "Error")
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionSuccess() {
+	mockFindUsers := helpers.Result{
+		Data: &[]userEntity.User{
+			{
+				UserId:       "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+				FullName:     "alif",
+				Email:        "alif@gmail.com",
+				NIK:          "12312131131",
+				MobileNumber: "+6281281015121",
+				Address:      "<string>",
+				Role:         "user",
+				Status:       userEntity.StatusPendingDeletion,
+				Version:      4,
+				Country: userEntity.Country{
+					Id:       1,
+					Location: &userEntity.GeoPoint{Type: "Point", Coordinates: []float64{106.8, -6.2}},
+				},
+			},
+		},
+		Error: nil,
+	}
+	mockUpdateOneUser := helpers.Result{
+		Data:  "Success update data",
+		Count: 1,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, int64(100)).Return(mockChannel(mockFindUsers))
+	suite.mockUserRepositoryCommand.On("DeleteDataExportsByUserId", mock.Anything, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980").Return(mockChannel(helpers.Result{Count: 2}))
+	suite.mockUserRepositoryCommand.On("EraseAddressesByUserId", mock.Anything, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", mock.Anything).Return(mockChannel(helpers.Result{Count: 3}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserDueForDeletion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.UserId == "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980" && u.Status == userEntity.StatusDeleted &&
+			u.NIK == "" && u.MobileNumber == "" && u.Address == "" && u.Email != "alif@gmail.com" &&
+			u.Version == 5 && storesNoLocation(u.Country)
+	}), mock.Anything).Return(mockChannel(mockUpdateOneUser))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.MatchedBy(func(c userEntity.Consent) bool {
+		return c.UserId == "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980" && !c.Granted && c.Source == userEntity.ConsentSourceDeletion
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockKafkaProducer.On("Publish", "user.deleted", mock.Anything, mock.Anything)
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, processed)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneConsent", 2)
+	suite.mockUserRepositoryCommand.AssertCalled(suite.T(), "EraseAddressesByUserId", mock.Anything, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980", mock.Anything)
+	suite.mockUserRepositoryCommand.AssertCalled(suite.T(), "DeleteDataExportsByUserId", mock.Anything, "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980")
+	suite.mockKafkaProducer.AssertCalled(suite.T(), "Publish", "user.deleted", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionCancelled() {
+	mockFindUsers := helpers.Result{
+		Data: &[]userEntity.User{
+			{
+				UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+				Status: userEntity.StatusPendingDeletion,
+			},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindUsers))
+	suite.mockUserRepositoryCommand.On("DeleteDataExportsByUserId", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("EraseAddressesByUserId", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	// A login cancelled the deletion after the due accounts were read
+	suite.mockUserRepositoryCommand.On("UpdateOneUserDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Count: 0}))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, processed)
+	suite.mockKafkaProducer.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionErrEraseAddressBook() {
+	mockFindUsers := helpers.Result{
+		Data: &[]userEntity.User{
+			{
+				UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+				Status: userEntity.StatusPendingDeletion,
+			},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindUsers))
+	suite.mockUserRepositoryCommand.On("DeleteDataExportsByUserId", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("EraseAddressesByUserId", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{
+		Error: errors.InternalServerError("Error"),
+	}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, processed)
+	// The account stays pending deletion so the next run erases the address book again
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserDueForDeletion", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockKafkaProducer.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionErrEraseDataExports() {
+	mockFindUsers := helpers.Result{
+		Data: &[]userEntity.User{
+			{
+				UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+				Status: userEntity.StatusPendingDeletion,
+			},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindUsers))
+	suite.mockUserRepositoryCommand.On("DeleteDataExportsByUserId", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{
+		Error: errors.InternalServerError("Error"),
+	}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, processed)
+	// The account stays pending deletion so the next run erases the exports again
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserDueForDeletion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionErrUpdate() {
+	mockFindUsers := helpers.Result{
+		Data: &[]userEntity.User{
+			{
+				UserId: "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+				Status: userEntity.StatusPendingDeletion,
+			},
+		},
+		Error: nil,
+	}
+	mockUpdateOneUser := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("Error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindUsers))
+	suite.mockUserRepositoryCommand.On("DeleteDataExportsByUserId", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("EraseAddressesByUserId", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockUpdateOneUser))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, processed)
+	suite.mockKafkaProducer.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessAccountDeletionErrQuery() {
+	mockFindUsers := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("Error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindUsersDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindUsers))
+	// Act
+	_, err := suite.usecase.ProcessAccountDeletion(suite.ctx)
+	// Assert
+	assert.Error(suite.T(), err, "Error")
+}
+
+func (suite *CommandUsecaseTestSuite) TestLoginUserCancelDeletion() {
+	payload := userRequest.LoginUser{
+		Email:    "alif@gmail.com",
+		Password: "Password1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "a1d7e6c6-a4b4-48b0-b436-c882a9cb7980",
+			Email:    "alif@gmail.com",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Role:     "user",
+			Status:   userEntity.StatusPendingDeletion,
+			Deletion: userEntity.Deletion{
+				RequestedAt: time.Now(),
+				ScheduledAt: time.Now().Add(24 * time.Hour),
+			},
+		},
+		Error: nil,
+	}
+	mockUpsertOneUser := helpers.Result{
+		Data:  nil,
+		Count: 1,
+		Error: nil,
+	}
+	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("", nil))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, mock.Anything).Return(mockChannel(mockCurrentConsents()))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Status == userEntity.StatusActive && u.Deletion.ScheduledAt.IsZero() && u.Version == 1
+	}), int64(0)).Return(mockChannel(mockUpsertOneUser))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockJwt.On("GenerateToken", mock.Anything, mock.Anything).Return("mockedToken", "mockedExpiredAt", nil)
+	suite.mockJwt.On("GenerateTokenRefresh", mock.Anything, mock.Anything).Return("mockedToken", nil)
+	// Act
+	_, err := suite.usecase.LoginUser(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestRequestDataExportSuccess() {
+	payload := userRequest.RequestDataExport{
+		Format: "csv",
+	}
+	mockUpsertOneDataExport := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	suite.mockRedis.On("SetNX", mock.Anything, mock.Anything, mock.Anything, 24*time.Hour).Return(redis.NewBoolResult(true, nil))
+	suite.mockUserRepositoryCommand.On("UpsertOneDataExport", mock.Anything, mock.MatchedBy(func(d userEntity.DataExport) bool {
+		return d.Status == userEntity.DataExportStatusPending && d.Format == "csv" && d.UserId == "12345"
+	})).Return(mockChannel(mockUpsertOneDataExport))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	resp, err := suite.usecase.RequestDataExport(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userEntity.DataExportStatusPending, resp.Status)
+	assert.NotEmpty(suite.T(), resp.ExportId)
+}
+
+func (suite *CommandUsecaseTestSuite) TestRequestDataExportRateLimited() {
+	payload := userRequest.RequestDataExport{}
+	suite.mockRedis.On("SetNX", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(redis.NewBoolResult(false, nil))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.RequestDataExport(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err, "Data export already requested, please try again later")
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneDataExport", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestRequestDataExportErrUpsert() {
+	payload := userRequest.RequestDataExport{}
+	mockUpsertOneDataExport := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("Error"),
+	}
+	suite.mockRedis.On("SetNX", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(redis.NewBoolResult(true, nil))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockUserRepositoryCommand.On("UpsertOneDataExport", mock.Anything, mock.Anything).Return(mockChannel(mockUpsertOneDataExport))
+	// Act
+	_, err := suite.usecase.RequestDataExport(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err, "Error")
+	suite.mockRedis.AssertCalled(suite.T(), "Del", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessDataExportSuccess() {
+	mockFindDataExports := helpers.Result{
+		Data: &[]userEntity.DataExport{
+			{
+				ExportId:    "export-1",
+				UserId:      "12345",
+				Format:      "csv",
+				Status:      userEntity.DataExportStatusPending,
+				RequestedAt: time.Now(),
+			},
+		},
+		Error: nil,
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "12345",
+			FullName: "alif",
+			Email:    "alif@gmail.com",
+			NIK:      "12312131131",
+			Subdistrict: userEntity.Subdistrict{
+				Id:   "1",
+				Name: "Desa kkn",
+			},
+		},
+		Error: nil,
+	}
+	mockFindDataExportsByUser := helpers.Result{
+		Data: &[]userEntity.DataExport{
+			{ExportId: "export-1", Format: "csv", RequestedAt: time.Now()},
+		},
+		Error: nil,
+	}
+	mockUpsertOneDataExport := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindDataExportsByStatus", mock.Anything, userEntity.DataExportStatusPending, mock.Anything).Return(mockChannel(mockFindDataExports))
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindDataExportsByUserId", mock.Anything, "12345").Return(mockChannel(mockFindDataExportsByUser))
+	mockFindAddresses := helpers.Result{
+		Data: &[]userEntity.AddressBookEntry{
+			{AddressId: "address-1", UserId: "12345", Label: "Kantor", Address: "Jl. Sudirman No 5", IsDefault: true},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(mockCurrentConsents()))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(mockFindAddresses))
+	suite.mockUserRepositoryCommand.On("UpsertOneDataExport", mock.Anything, mock.MatchedBy(func(d userEntity.DataExport) bool {
+		if d.Status != userEntity.DataExportStatusReady {
+			return false
+		}
+		zipReader, err := zip.NewReader(bytes.NewReader(d.Archive), int64(len(d.Archive)))
+		if err != nil || len(zipReader.File) != 2 {
+			return false
+		}
+		if zipReader.File[0].Name != "data.json" || zipReader.File[1].Name != "data.csv" {
+			return false
+		}
+		jsonFile, err := zipReader.File[0].Open()
+		if err != nil {
+			return false
+		}
+		defer jsonFile.Close()
+		var archive userResponse.DataExportArchive
+		if err := json.NewDecoder(jsonFile).Decode(&archive); err != nil {
+			return false
+		}
+		return len(archive.AddressBook) == 1 && archive.AddressBook[0].AddressId == "address-1"
+	})).Return(mockChannel(mockUpsertOneDataExport))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessDataExport(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, processed)
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessDataExportUserNotFound() {
+	mockFindDataExports := helpers.Result{
+		Data: &[]userEntity.DataExport{
+			{
+				ExportId: "export-1",
+				UserId:   "12345",
+				Format:   "json",
+				Status:   userEntity.DataExportStatusPending,
+			},
+		},
+		Error: nil,
+	}
+	mockFindOneUser := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	mockUpsertOneDataExport := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindDataExportsByStatus", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindDataExports))
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryCommand.On("UpsertOneDataExport", mock.Anything, mock.MatchedBy(func(d userEntity.DataExport) bool {
+		return d.Status == userEntity.DataExportStatusFailed
+	})).Return(mockChannel(mockUpsertOneDataExport))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	processed, err := suite.usecase.ProcessDataExport(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, processed)
+	suite.mockRedis.AssertCalled(suite.T(), "Del", mock.Anything, "DATA-EXPORT-LIMIT:12345")
+}
+
+func (suite *CommandUsecaseTestSuite) TestProcessDataExportErrQuery() {
+	mockFindDataExports := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("Error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindDataExportsByStatus", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(mockFindDataExports))
+	// Act
+	_, err := suite.usecase.ProcessDataExport(suite.ctx)
+	// Assert
+	assert.Error(suite.T(), err, "Error")
+}
+
+func (suite *CommandUsecaseTestSuite) TestRegisterUserTermsNotCurrent() {
+	payload := userRequest.RegisterUser{
+		FullName:             "Alif Septian",
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		CountryId:            "1",
+		Role:                 "user",
+		TermsVersion:         "0.9",
+		PrivacyPolicyVersion: "1.0",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.RegisterUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.TermsNotCurrent.New(), err)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindOneByEmail", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestLoginUserTermsAcceptanceRequired() {
+	payload := userRequest.LoginUser{
+		Email:    "alif@gmail.com",
+		Password: "Password1@",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "12345",
+			Email:    "alif@gmail.com",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Role:     "user",
+		},
+		Error: nil,
+	}
+	mockFindConsents := helpers.Result{
+		Data: &[]userEntity.Consent{
+			{Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "0.9"},
+			{Purpose: userEntity.ConsentPurposePrivacyPolicy, Granted: true, Version: "1.0"},
+		},
+		Error: nil,
+	}
+	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("", nil))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(mockFindConsents))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.LoginUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.TermsNotAccepted.New(), err)
+	suite.mockJwt.AssertNotCalled(suite.T(), "GenerateToken", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestLoginUserAcceptUpdatedTerms() {
+	payload := userRequest.LoginUser{
+		Email:                "alif@gmail.com",
+		Password:             "Password1@",
+		TermsVersion:         "1.0",
+		PrivacyPolicyVersion: "1.0",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:   "12345",
+			Email:    "alif@gmail.com",
+			Password: "PzWCUGI/iepF6Xyz1dKIgfQYRwkVTN5AdXTTl9Yz+W8=",
+			Role:     "user",
+		},
+		Error: nil,
+	}
+	suite.mockRedis.On("Get", suite.ctx, mock.AnythingOfType("string")).Return(redis.NewStringResult("", nil))
+	suite.mockUserRepositoryQuery.On("FindOneByEmail", mock.Anything, payload.Email).Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &[]userEntity.Consent{}}))
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.MatchedBy(func(c userEntity.Consent) bool {
+		return c.Granted && c.Version == "1.0" && c.Source == userEntity.ConsentSourceLogin
+	})).Return(mockChannel(helpers.Result{})).Twice()
+	suite.mockUserRepositoryCommand.On("UpsertOneUser", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockJwt.On("GenerateToken", mock.Anything, mock.Anything).Return("mockedToken", "mockedExpiredAt", nil)
+	suite.mockJwt.On("GenerateTokenRefresh", mock.Anything, mock.Anything).Return("mockedToken", nil)
+	// Act
+	result, err := suite.usecase.LoginUser(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "mockedToken", result.AuthToken)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneConsent", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateConsentsSuccess() {
+	marketingEmail := false
+	payload := userRequest.UpdateConsents{
+		MarketingEmail: &marketingEmail,
+	}
+	grantedAt := time.Now().Add(-24 * time.Hour)
+	mockFindConsents := helpers.Result{
+		Data: &[]userEntity.Consent{
+			{Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "1.0", GrantedAt: grantedAt},
+			{Purpose: userEntity.ConsentPurposePrivacyPolicy, Granted: true, Version: "1.0", GrantedAt: grantedAt},
+			{Purpose: userEntity.ConsentPurposeMarketingEmail, Granted: true, Version: "1.0", GrantedAt: grantedAt},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(mockFindConsents))
+	suite.mockUserRepositoryCommand.On("UpsertOneConsent", mock.Anything, mock.MatchedBy(func(c userEntity.Consent) bool {
+		return c.UserId == "12345" && c.Purpose == userEntity.ConsentPurposeMarketingEmail && !c.Granted &&
+			c.Source == userEntity.ConsentSourceProfile && c.GrantedAt.IsZero()
+	})).Return(mockChannel(helpers.Result{}))
+	// Act
+	result, err := suite.usecase.UpdateConsents(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.AcceptanceRequired)
+	assert.Len(suite.T(), result.Consents, 3)
+	assert.Equal(suite.T(), userEntity.ConsentPurposeMarketingEmail, result.Consents[0].Purpose)
+	assert.False(suite.T(), result.Consents[0].Granted)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateConsentsTermsNotCurrent() {
+	payload := userRequest.UpdateConsents{
+		TermsVersion: "0.9",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateConsents(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.TermsNotCurrent.New(), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateConsentsEmpty() {
+	payload := userRequest.UpdateConsents{}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateConsents(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.BadRequest("No consent to update"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdatePreferencesSuccess() {
+	locale := "en-US"
+	push := true
+	payload := userRequest.UpdatePreferences{
+		Locale: &locale,
+		NotificationChannels: &userRequest.NotificationChannels{
+			Push: &push,
+		},
+		FavouriteGenres: []string{"Jazz", " jazz ", "Rock"},
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId: "12345",
+			Email:  "alif@gmail.com",
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		p := u.Preferences
+		return p.Locale == "en-US" && p.NotificationChannels.Email && p.NotificationChannels.Push &&
+			len(p.FavouriteGenres) == 2 && !p.UpdatedAt.IsZero()
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockKafkaProducer.On("Publish", "user.preferences.updated", mock.Anything, mock.Anything)
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	result, err := suite.usecase.UpdatePreferences(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "en-US", result.Locale)
+	assert.Equal(suite.T(), "Asia/Jakarta", result.Timezone)
+	assert.Equal(suite.T(), []string{"Jazz", "Rock"}, result.FavouriteGenres)
+	suite.mockKafkaProducer.AssertCalled(suite.T(), "Publish", "user.preferences.updated", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdatePreferencesRetriesOnConflict() {
+	locale := "en-US"
+	payload := userRequest.UpdatePreferences{
+		Locale: &locale,
+	}
+	mockFindOneUser := helpers.Result{
+		Data:  &userEntity.User{UserId: "12345", Version: 1},
+		Error: nil,
+	}
+	// Another request changed the timezone between our read and write
+	mockFindChangedUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:      "12345",
+			Version:     2,
+			Preferences: userEntity.Preferences{Timezone: "Asia/Makassar", UpdatedAt: time.Now()},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser)).Once()
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindChangedUser)).Once()
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, int64(1)).Return(mockChannel(helpers.Result{Count: 0}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Version == 3 && u.Preferences.Locale == "en-US" && u.Preferences.Timezone == "Asia/Makassar"
+	}), int64(2)).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockKafkaProducer.On("Publish", "user.preferences.updated", mock.Anything, mock.Anything)
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	result, err := suite.usecase.UpdatePreferences(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "en-US", result.Locale)
+	assert.Equal(suite.T(), "Asia/Makassar", result.Timezone)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpdateOneUserWithVersion", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdatePreferencesEmpty() {
+	payload := userRequest.UpdatePreferences{}
+	mockFindOneUser := helpers.Result{
+		Data:  &userEntity.User{UserId: "12345"},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdatePreferences(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.BadRequest("No preference to update"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdatePreferencesUserNotFound() {
+	timezone := "Asia/Tokyo"
+	payload := userRequest.UpdatePreferences{
+		Timezone: &timezone,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdatePreferences(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.NotFound("User not found"), err)
+}
+
+func mockPatchableUser() helpers.Result {
+	return helpers.Result{
+		Data: &userEntity.User{
+			UserId:       "12345",
+			FullName:     "alif",
+			Email:        "alif@gmail.com",
+			MobileNumber: "+6281281015121",
+			Address:      "Jalan jalan",
+			Subdistrict:  userEntity.Subdistrict{Id: "1", Name: "Desa kkn"},
+			Country:      userEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+			Version:      3,
+		},
+		Error: nil,
+	}
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserSuccess() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"3"`,
+		Patch:   []byte(`{"fullName":"Alif Septian","address":null}`),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.FullName == "Alif Septian" && u.Address == "" && u.MobileNumber == "+6281281015121" &&
+			u.Subdistrict.Id == "1" && u.Version == 4
+	}), int64(3)).Return(mockChannel(helpers.Result{Data: "Success update data", Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	// Act
+	result, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Alif Septian", result.FullName)
+	assert.Equal(suite.T(), int64(4), result.Version)
+	suite.mockAddressRepositoryQuery.AssertNotCalled(suite.T(), "FindOneCountry", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserChangeCountry() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `*`,
+		Patch:   []byte(`{"countryId":"2","subdictrictId":null}`),
+	}
+	mockFindOneCountry := helpers.Result{
+		Data:  &addressEntity.Country{Id: 2, Code: "SG", Name: "Singapore"},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 2).Return(mockChannel(mockFindOneCountry))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Country.Code == "SG" && u.Subdistrict.Id == ""
+	}), int64(3)).Return(mockChannel(helpers.Result{Data: "Success update data", Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, "12345").Return(mockChannel(mockDefaultAddress()))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-home" && a.Country.Code == "SG" && a.Subdistrict.Id == "" && a.Label == "home"
+	})).Return(mockChannel(helpers.Result{}))
+	// Act
+	result, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "SG", result.CountryCode)
+	suite.mockUserRepositoryCommand.AssertCalled(suite.T(), "UpsertOneAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserStaleIfMatch() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"2"`,
+		Patch:   []byte(`{"fullName":"Alif Septian"}`),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.PreconditionFailed("Profile has been modified, reload and try again"), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserConcurrentWrite() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"3"`,
+		Patch:   []byte(`{"fullName":"Alif Septian"}`),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, int64(3)).Return(mockChannel(helpers.Result{Data: "Success update data", Count: 0}))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.PreconditionFailed("Profile has been modified, reload and try again"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserRemoveRequiredField() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"3"`,
+		Patch:   []byte(`{"fullName":null}`),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.BadRequest("fullName, mobileNumber and countryId cannot be removed"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestPatchUserInvalidPatchType() {
+	payload := userRequest.PatchUser{
+		UserId:  "12345",
+		IfMatch: `"3"`,
+		Patch:   []byte(`{"fullName":123}`),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.PatchUser(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.BadRequest("Invalid merge patch document"), err)
+}
+
+func encodeAvatarPng() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 80, 60))
+	buf := new(bytes.Buffer)
+	_ = png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+func (suite *CommandUsecaseTestSuite) TestUploadAvatarSuccess() {
+	payload := userRequest.UploadAvatar{
+		UserId:  "12345",
+		Content: encodeAvatarPng(),
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId: "12345",
+			Avatar: userEntity.Avatar{
+				Keys: []string{"avatars/12345/1-medium.jpg"},
+			},
+			Version: 1,
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, "image/jpeg").Return(
+		func(ctx context.Context, key string, content []byte, contentType string) string {
+			return "http://localhost/media/" + key
+		}, nil)
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return len(u.Avatar.Keys) == 3 && len(u.Avatar.Variants) == 3 && u.Version == 2
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockBlobStore.On("Delete", mock.Anything, "avatars/12345/1-medium.jpg").Return(nil)
+	// Act
+	result, err := suite.usecase.UploadAvatar(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), result.AvatarUrl, "-medium.jpg")
+	assert.Len(suite.T(), result.Variants, 3)
+	suite.mockBlobStore.AssertNumberOfCalls(suite.T(), "Put", 3)
+	suite.mockBlobStore.AssertCalled(suite.T(), "Delete", mock.Anything, "avatars/12345/1-medium.jpg")
+}
+
+func (suite *CommandUsecaseTestSuite) TestUploadAvatarUnsupportedFormat() {
+	payload := userRequest.UploadAvatar{
+		UserId:  "12345",
+		Content: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &userEntity.User{UserId: "12345"}}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UploadAvatar(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.AvatarUnsupportedType.New(), err)
+	suite.mockBlobStore.AssertNotCalled(suite.T(), "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUploadAvatarErrUpdate() {
+	payload := userRequest.UploadAvatar{
+		UserId:  "12345",
+		Content: encodeAvatarPng(),
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &userEntity.User{UserId: "12345"}}))
+	suite.mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, "image/jpeg").Return("http://localhost/media/avatar.jpg", nil)
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{Error: errors.InternalServerError("Error mongodb connection")}))
+	suite.mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+	// Act
+	_, err := suite.usecase.UploadAvatar(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	suite.mockBlobStore.AssertNumberOfCalls(suite.T(), "Delete", 3)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUploadAvatarConflict() {
+	payload := userRequest.UploadAvatar{
+		UserId:  "12345",
+		Content: encodeAvatarPng(),
+	}
+	findUser := func(ctx context.Context, userId string) <-chan helpers.Result {
+		return mockChannel(helpers.Result{Data: &userEntity.User{UserId: "12345", Version: 1}})
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(findUser)
+	suite.mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, "image/jpeg").Return("http://localhost/media/avatar.jpg", nil)
+	// The profile keeps changing under every attempt
+	updateUser := func(ctx context.Context, user userEntity.User, version int64) <-chan helpers.Result {
+		return mockChannel(helpers.Result{Count: 0})
+	}
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.Anything, int64(1)).Return(updateUser)
+	suite.mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UploadAvatar(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.Conflict("Profile is being modified, try again"), err)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpdateOneUserWithVersion", 3)
+	suite.mockBlobStore.AssertNumberOfCalls(suite.T(), "Delete", 3)
+}
+
+func mockDefaultAddress() helpers.Result {
+	return helpers.Result{
+		Data: &userEntity.AddressBookEntry{
+			AddressId:   "addr-home",
+			UserId:      "12345",
+			Label:       "home",
+			Address:     "Jalan jalan",
+			Subdistrict: userEntity.Subdistrict{Id: "1", Name: "Desa kkn"},
+			Country:     userEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+			IsDefault:   true,
+		},
+		Error: nil,
+	}
+}
+
+func mockSaveAddress() userRequest.SaveAddress {
+	return userRequest.SaveAddress{
+		Label:         "office",
+		RecipientName: "alif",
+		MobileNumber:  "081281015121",
+		Address:       "Jalan kantor",
+		SubdictrictId: "2",
+		CountryId:     "1",
+	}
+}
+
+func (suite *CommandUsecaseTestSuite) mockResolveLocation() {
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(helpers.Result{
+		Data: &addressEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "2").Return(mockChannel(helpers.Result{
+		Data: &addressEntity.SubDistrict{Id: "2", Name: "Desa kantor", CityId: "3", CityName: "Kota kantor"},
+	}))
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateAddressMigratesProfileAddress() {
+	payload := mockSaveAddress()
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &[]userEntity.AddressBookEntry{}}))
+	suite.mockUserRepositoryQuery.On("CountAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Count: 0}))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.Label == "home" && a.IsDefault && a.Address == "Jalan jalan" && a.Subdistrict.Id == "1"
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockResolveLocation()
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.Label == "office" && !a.IsDefault && a.Subdistrict.CityName == "Kota kantor" && a.MobileNumber == "+6281281015121"
+	})).Return(mockChannel(helpers.Result{}))
+	// Act
+	result, err := suite.usecase.CreateAddress(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "office", result.Label)
+	assert.False(suite.T(), result.IsDefault)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneAddress", 2)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateAddressAsDefault() {
+	payload := mockSaveAddress()
+	payload.IsDefault = true
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &[]userEntity.AddressBookEntry{*mockDefaultAddress().Data.(*userEntity.AddressBookEntry)},
+	}))
+	suite.mockResolveLocation()
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, "12345").Return(mockChannel(mockDefaultAddress()))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-home" && !a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.Label == "office" && a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Address == "Jalan kantor" && u.Subdistrict.Id == "2" && u.Version == 4
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	// Act
+	result, err := suite.usecase.CreateAddress(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsDefault)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneAddress", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateAddressBookFull() {
+	entries := make([]userEntity.AddressBookEntry, 10)
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &entries}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.CreateAddress(suite.ctx, mockSaveAddress(), "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.UnprocessableEntity("Address book is limited to 10 addresses"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateAddressSubdistrictNotFound() {
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &[]userEntity.AddressBookEntry{*mockDefaultAddress().Data.(*userEntity.AddressBookEntry)},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(helpers.Result{
+		Data: &addressEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "2").Return(mockChannel(helpers.Result{Data: nil}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.CreateAddress(suite.ctx, mockSaveAddress(), "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.WithFields(errors.SubdistrictNotFound.New(), map[string]string{"subdistrictId": "not found"}), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateAddressDefaultSyncsProfile() {
+	payload := mockSaveAddress()
+	payload.AddressId = "addr-home"
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindOneAddress", mock.Anything, "12345", "addr-home").Return(mockChannel(mockDefaultAddress()))
+	suite.mockResolveLocation()
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-home" && a.IsDefault && a.Label == "office"
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Address == "Jalan kantor" && u.Subdistrict.CityName == "Kota kantor"
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	// Act
+	result, err := suite.usecase.UpdateAddress(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsDefault)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindDefaultAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateAddressNotFound() {
+	payload := mockSaveAddress()
+	payload.AddressId = "addr-unknown"
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindOneAddress", mock.Anything, "12345", "addr-unknown").Return(mockChannel(helpers.Result{Data: nil}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateAddress(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.NotFound("Address not found"), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateAddressPendingDeletion() {
+	payload := mockSaveAddress()
+	mockFindOneUser := helpers.Result{
+		Data:  &userEntity.User{UserId: "12345", Status: userEntity.StatusPendingDeletion},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateAddress(suite.ctx, payload, "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.ForbiddenError("Account is pending deletion"), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestCreateAddressDeletedUser() {
+	mockFindOneUser := helpers.Result{
+		Data:  &userEntity.User{UserId: "12345", Status: userEntity.StatusDeleted},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.CreateAddress(suite.ctx, mockSaveAddress(), "12345")
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), errors.NotFound("User not found"), err)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindAddressesByUserId", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestSetDefaultAddress() {
+	payload := userRequest.AddressBookEntry{UserId: "12345", AddressId: "addr-office"}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindOneAddress", mock.Anything, "12345", "addr-office").Return(mockChannel(helpers.Result{
+		Data: &userEntity.AddressBookEntry{AddressId: "addr-office", UserId: "12345", Address: "Jalan kantor"},
+	}))
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, "12345").Return(mockChannel(mockDefaultAddress()))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-home" && !a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-office" && a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Address == "Jalan kantor"
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	// Act
+	result, err := suite.usecase.SetDefaultAddress(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.IsDefault)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneAddress", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeleteDefaultAddressPromotesNext() {
+	payload := userRequest.AddressBookEntry{UserId: "12345", AddressId: "addr-home"}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindOneAddress", mock.Anything, "12345", "addr-home").Return(mockChannel(mockDefaultAddress()))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-home" && a.Deleted && !a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &[]userEntity.AddressBookEntry{{AddressId: "addr-office", UserId: "12345", Address: "Jalan kantor"}},
+	}))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.AddressId == "addr-office" && a.IsDefault
+	})).Return(mockChannel(helpers.Result{}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(u userEntity.User) bool {
+		return u.Address == "Jalan kantor"
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	// Act
+	err := suite.usecase.DeleteAddress(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.mockUserRepositoryCommand.AssertNumberOfCalls(suite.T(), "UpsertOneAddress", 2)
+}
+
+func (suite *CommandUsecaseTestSuite) TestDeleteAddressNotDefault() {
+	payload := userRequest.AddressBookEntry{UserId: "12345", AddressId: "addr-office"}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockUserRepositoryQuery.On("FindOneAddress", mock.Anything, "12345", "addr-office").Return(mockChannel(helpers.Result{
+		Data: &userEntity.AddressBookEntry{AddressId: "addr-office", UserId: "12345"},
+	}))
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	// Act
+	err := suite.usecase.DeleteAddress(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindAddressesByUserId", mock.Anything, mock.Anything)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpdateOneUserWithVersion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateUserHierarchyMismatch() {
+	payload := userRequest.UpdateUser{
+		FullName:      "FullName",
+		MobileNumber:  "+6281281015121",
+		ProvinceId:    "31",
+		CityId:        "CityId",
+		DistrictId:    "DistrictId",
+		SubdictrictId: "Id",
+		CountryId:     "1",
+		Role:          "user",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(helpers.Result{
+		Data: &addressEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "Id").Return(mockChannel(helpers.Result{
+		Data: &addressEntity.SubDistrict{Id: "Id", DistrictId: "DistrictId", CityId: "CityId", ProvinceId: "ProvinceId"},
+	}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateUser(suite.ctx, payload, "12345")
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address hierarchy is not valid", map[string]string{
+		"provinceId": "does not match the subdistrict, expected ProvinceId",
+	}), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneUser", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateUserStoresLocation() {
+	payload := userRequest.UpdateUser{
+		FullName:      "FullName",
+		MobileNumber:  "+6281281015121",
+		SubdictrictId: "Id",
+		CountryId:     "1",
+		Role:          "user",
+		Latitude:      "-6.2",
+		Longitude:     "106.8",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(helpers.Result{
+		Data: &addressEntity.Country{Id: 1, Code: "ID", Name: "Indonesia", Bounds: &addressEntity.Bounds{MinLatitude: -11, MinLongitude: 95, MaxLatitude: 6, MaxLongitude: 141}},
+	}))
+	suite.mockAddressRepositoryQuery.On("FindOneSubdistrict", mock.Anything, "Id").Return(mockChannel(helpers.Result{
+		Data: &addressEntity.SubDistrict{Id: "Id", DistrictId: "DistrictId", CityId: "CityId", ProvinceId: "ProvinceId"},
+	}))
+	suite.mockUserRepositoryCommand.On("UpdateOneUserWithVersion", mock.Anything, mock.MatchedBy(func(user userEntity.User) bool {
+		return user.Country.Location != nil && user.Country.Location.Type == "Point" &&
+			user.Country.Location.Coordinates[0] == 106.8 && user.Country.Location.Coordinates[1] == -6.2
+	}), mock.Anything).Return(mockChannel(helpers.Result{Count: 1}))
+	suite.mockUserRepositoryQuery.On("FindDefaultAddress", mock.Anything, mock.Anything).Return(mockChannel(helpers.Result{}))
+	suite.mockRedis.On("Del", mock.Anything, mock.Anything).Return(nil)
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateUser(suite.ctx, payload, "12345")
+	// Assert
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *CommandUsecaseTestSuite) TestUpdateUserLocationOutsideCountry() {
+	payload := userRequest.UpdateUser{
+		FullName:      "FullName",
+		MobileNumber:  "+6281281015121",
+		SubdictrictId: "Id",
+		CountryId:     "1",
+		Role:          "user",
+		Latitude:      "48.85",
+		Longitude:     "2.35",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockPatchableUser()))
+	suite.mockAddressRepositoryQuery.On("FindOneCountry", mock.Anything, 1).Return(mockChannel(helpers.Result{
+		Data: &addressEntity.Country{Id: 1, Code: "ID", Name: "Indonesia", Bounds: &addressEntity.Bounds{MinLatitude: -11, MinLongitude: 95, MaxLatitude: 6, MaxLongitude: 141}},
+	}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.UpdateUser(suite.ctx, payload, "12345")
+	// Assert
+	assert.Equal(suite.T(), errors.ValidationError("Address coordinates are outside the country", map[string]string{
+		"latitude":  "is outside Indonesia",
+		"longitude": "is outside Indonesia",
+	}), err)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneUser", mock.Anything, mock.Anything)
+}
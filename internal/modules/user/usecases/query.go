@@ -3,14 +3,18 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
+	"user-service/configs"
 	user "user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	userRequest "user-service/internal/modules/user/models/request"
 	userResponse "user-service/internal/modules/user/models/response"
 	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/helpers"
 	"user-service/internal/pkg/log"
 
+	uuid "github.com/google/uuid"
 	"go.elastic.co/apm"
 )
 
@@ -43,14 +47,342 @@ func (q queryUsecase) GetProfile(origCtx context.Context, payload userRequest.Ge
 	}
 	if respUser.Data == nil {
 		msg := "User Not Found"
-		q.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+		q.logger.Error(ctx, msg, payload)
 		return nil, errors.NotFound(msg)
 	}
 	userData, ok := respUser.Data.(*userEntity.User)
 	if !ok {
 		return nil, errors.InternalServerError("cannot parsing data")
 	}
-	response := userResponse.GetProfile{
+	response := profileResponse(*userData)
+	return &response, nil
+}
+
+func (q queryUsecase) GetDataExport(origCtx context.Context, payload userRequest.GetDataExport) (*userResponse.DataExport, error) {
+	domain := "userUsecase-GetDataExport"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	resp := <-q.userRepositoryQuery.FindOneDataExport(ctx, payload.ExportId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "Data export not found"
+		q.logger.Error(ctx, msg, payload)
+		return nil, errors.NotFound(msg)
+	}
+	dataExport, ok := resp.Data.(*userEntity.DataExport)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if dataExport.UserId != payload.UserId {
+		msg := "Data export not found"
+		q.logger.Error(ctx, msg, payload)
+		return nil, errors.NotFound(msg)
+	}
+
+	response := userResponse.DataExport{
+		ExportId:    dataExport.ExportId,
+		Status:      dataExport.Status,
+		Format:      dataExport.Format,
+		RequestedAt: dataExport.RequestedAt.Format(time.RFC3339),
+	}
+	if !dataExport.CompletedAt.IsZero() {
+		response.CompletedAt = dataExport.CompletedAt.Format(time.RFC3339)
+	}
+	if dataExport.Status == userEntity.DataExportStatusReady && time.Now().Before(dataExport.ExpiredAt) {
+		expires := time.Now().Add(dataExportLinkTTL())
+		if expires.After(dataExport.ExpiredAt) {
+			expires = dataExport.ExpiredAt
+		}
+		signature := helpers.GenerateSignature(fmt.Sprintf("%s:%d", dataExport.ExportId, expires.Unix()))
+		response.DownloadUrl = fmt.Sprintf("/api/users/v1/data-export/%s/download?expires=%d&signature=%s", dataExport.ExportId, expires.Unix(), signature)
+		response.ExpiredAt = expires.Format(time.RFC3339)
+	}
+
+	return &response, nil
+}
+
+func (q queryUsecase) DownloadDataExport(origCtx context.Context, payload userRequest.DownloadDataExport) (*userResponse.DataExportFile, error) {
+	domain := "userUsecase-DownloadDataExport"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	if time.Now().Unix() > payload.Expires {
+		msg := "Download link expired"
+		q.logger.Error(ctx, msg, payload.ExportId)
+		return nil, errors.ForbiddenError(msg)
+	}
+	if !helpers.IsValidSignature(fmt.Sprintf("%s:%d", payload.ExportId, payload.Expires), payload.Signature) {
+		msg := "Invalid download link"
+		q.logger.Error(ctx, msg, payload.ExportId)
+		return nil, errors.ForbiddenError(msg)
+	}
+
+	resp := <-q.userRepositoryQuery.FindOneDataExport(ctx, payload.ExportId)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Data == nil {
+		msg := "Data export not found"
+		q.logger.Error(ctx, msg, payload.ExportId)
+		return nil, errors.NotFound(msg)
+	}
+	dataExport, ok := resp.Data.(*userEntity.DataExport)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+	if dataExport.Status != userEntity.DataExportStatusReady || time.Now().After(dataExport.ExpiredAt) {
+		msg := "Data export not available"
+		q.logger.Error(ctx, msg, payload.ExportId)
+		return nil, errors.NotFound(msg)
+	}
+	q.logger.Info(ctx, "Data export downloaded", map[string]string{"exportId": dataExport.ExportId, "userId": dataExport.UserId})
+
+	return &userResponse.DataExportFile{
+		FileName: fmt.Sprintf("data-export-%s.zip", dataExport.ExportId),
+		Content:  dataExport.Archive,
+	}, nil
+}
+
+func dataExportLinkTTL() time.Duration {
+	ttlMinutes := 60
+	parseTTL, err := strconv.Atoi(configs.GetConfig().DataExport.LinkTTL)
+	if err == nil && parseTTL > 0 {
+		ttlMinutes = parseTTL
+	}
+	return time.Duration(ttlMinutes) * time.Minute
+}
+
+func (q queryUsecase) GetTermsVersion(origCtx context.Context) (*userResponse.TermsVersion, error) {
+	domain := "userUsecase-GetTermsVersion"
+	span, _ := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	return &userResponse.TermsVersion{
+		TermsVersion:         currentTermsVersion(),
+		PrivacyPolicyVersion: currentPrivacyPolicyVersion(),
+	}, nil
+}
+
+func (q queryUsecase) GetConsents(origCtx context.Context, payload userRequest.GetConsents) (*userResponse.Consents, error) {
+	domain := "userUsecase-GetConsents"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	respConsents := <-q.userRepositoryQuery.FindConsentsByUserId(ctx, payload.UserId)
+	if respConsents.Error != nil {
+		return nil, respConsents.Error
+	}
+	var consents []userEntity.Consent
+	if data, ok := respConsents.Data.(*[]userEntity.Consent); ok {
+		consents = *data
+	}
+
+	return consentsResponse(consents), nil
+}
+
+func (q queryUsecase) GetMarketingConsent(origCtx context.Context, payload userRequest.GetMarketingConsent) (*userResponse.MarketingConsent, error) {
+	domain := "userUsecase-GetMarketingConsent"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	purpose := userEntity.ConsentPurposeMarketingEmail
+	if payload.Channel == "sms" {
+		purpose = userEntity.ConsentPurposeMarketingSms
+	}
+
+	// No record means the user never opted in
+	response := userResponse.MarketingConsent{
+		UserId:  payload.UserId,
+		Channel: payload.Channel,
+	}
+
+	// An account pending deletion or anonymized is never contacted, as in the marketing audience
+	respUser := <-q.userRepositoryQuery.FindOneUserId(ctx, payload.UserId)
+	if respUser.Error != nil {
+		return nil, respUser.Error
+	}
+	if userData, ok := respUser.Data.(*userEntity.User); !ok || userData == nil || userData.Status != userEntity.StatusActive {
+		return &response, nil
+	}
+
+	respConsent := <-q.userRepositoryQuery.FindOneConsent(ctx, payload.UserId, purpose)
+	if respConsent.Error != nil {
+		return nil, respConsent.Error
+	}
+	if consent, ok := respConsent.Data.(*userEntity.Consent); ok && consent != nil {
+		response.Granted = consent.Granted
+		response.UpdatedAt = consent.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return &response, nil
+}
+
+func (q queryUsecase) FindMarketingAudience(origCtx context.Context, payload userRequest.FindMarketingAudience) (*userResponse.MarketingAudience, error) {
+	domain := "userUsecase-FindMarketingAudience"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+
+	filters := 0
+	for _, set := range []bool{payload.Latitude != nil, payload.CityId != "", payload.ProvinceId != "", payload.CountryId != ""} {
+		if set {
+			filters++
+		}
+	}
+	if filters != 1 {
+		return nil, errors.BadRequest("exactly one of radius, cityId, provinceId or countryId is required")
+	}
+
+	purpose := userEntity.ConsentPurposeMarketingEmail
+	if payload.Channel == "sms" {
+		purpose = userEntity.ConsentPurposeMarketingSms
+	}
+
+	resp := <-q.userRepositoryQuery.FindMarketingAudience(ctx, payload, purpose)
+	if resp.Error != nil {
+		q.logger.Error(ctx, "Error query marketing audience", resp.Error)
+		return nil, resp.Error
+	}
+	audiences, ok := resp.Data.(*[]userEntity.Audience)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data audience")
+	}
+
+	userIds := make([]string, 0)
+	var total int64
+	if len(*audiences) > 0 {
+		audience := (*audiences)[0]
+		for _, member := range audience.Users {
+			userIds = append(userIds, member.UserId)
+		}
+		if len(audience.Total) > 0 {
+			total = audience.Total[0].Count
+		}
+	}
+
+	metaData := helpers.GenerateMetaData(total, int64(len(userIds)), payload.Page, payload.Size)
+	if payload.Page == 0 {
+		nextCursor, _ := resp.MetaData.(string)
+		metaData = helpers.GenerateCursorMetaData(total, int64(len(userIds)), payload.Size, nextCursor)
+	}
+
+	return &userResponse.MarketingAudience{
+		CollectionData: userIds,
+		MetaData:       metaData,
+	}, nil
+}
+
+func consentsResponse(consents []userEntity.Consent) *userResponse.Consents {
+	response := userResponse.Consents{
+		TermsVersion:       currentTermsVersion(),
+		PrivacyVersion:     currentPrivacyPolicyVersion(),
+		AcceptanceRequired: isAcceptanceRequired(consents),
+		Consents:           []userResponse.Consent{},
+	}
+	for _, consent := range consents {
+		response.Consents = append(response.Consents, userResponse.Consent{
+			Purpose:   consent.Purpose,
+			Granted:   consent.Granted,
+			Version:   consent.Version,
+			Source:    consent.Source,
+			UpdatedAt: consent.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return &response
+}
+
+// isAcceptanceRequired reports whether the current terms or privacy policy are not yet accepted
+func isAcceptanceRequired(consents []userEntity.Consent) bool {
+	accepted := map[string]string{}
+	for _, consent := range consents {
+		if consent.Granted {
+			accepted[consent.Purpose] = consent.Version
+		}
+	}
+	return accepted[userEntity.ConsentPurposeTerms] != currentTermsVersion() ||
+		accepted[userEntity.ConsentPurposePrivacyPolicy] != currentPrivacyPolicyVersion()
+}
+
+func (q queryUsecase) GetPreferences(origCtx context.Context, payload userRequest.GetPreferences) (*userResponse.Preferences, error) {
+	domain := "userUsecase-GetPreferences"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+	respUser := <-q.userRepositoryQuery.FindOneUserId(ctx, payload.UserId)
+	if respUser.Error != nil {
+		return nil, respUser.Error
+	}
+	if respUser.Data == nil {
+		msg := "User Not Found"
+		q.logger.Error(ctx, msg, payload)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := respUser.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	response := preferencesResponse(userData.Preferences)
+	return &response, nil
+}
+
+// preferencesResponse fills unset preferences with the service defaults
+func preferencesResponse(preferences userEntity.Preferences) userResponse.Preferences {
+	response := userResponse.Preferences{
+		Locale:   preferences.Locale,
+		Timezone: preferences.Timezone,
+		NotificationChannels: userResponse.NotificationChannels{
+			Email: preferences.NotificationChannels.Email,
+			Sms:   preferences.NotificationChannels.Sms,
+			Push:  preferences.NotificationChannels.Push,
+		},
+		FavouriteGenres:  []string{},
+		FavouriteArtists: []string{},
+	}
+	if preferences.UpdatedAt.IsZero() {
+		response.NotificationChannels.Email = true
+	} else {
+		response.UpdatedAt = preferences.UpdatedAt.Format(time.RFC3339)
+	}
+	if response.Locale == "" {
+		response.Locale = "id-ID"
+	}
+	if response.Timezone == "" {
+		response.Timezone = "Asia/Jakarta"
+	}
+	if preferences.FavouriteGenres != nil {
+		response.FavouriteGenres = preferences.FavouriteGenres
+	}
+	if preferences.FavouriteArtists != nil {
+		response.FavouriteArtists = preferences.FavouriteArtists
+	}
+	return response
+}
+
+func profileResponse(userData userEntity.User) userResponse.GetProfile {
+	return userResponse.GetProfile{
 		UserId:        userData.UserId,
 		FullName:      userData.FullName,
 		Email:         userData.Email,
@@ -62,6 +394,150 @@ func (q queryUsecase) GetProfile(origCtx context.Context, payload userRequest.Ge
 		CountryCode:   userData.Country.Code,
 		CountryName:   userData.Country.Name,
 		ContinentName: userData.Country.ContinentName,
+		Latitude:      userData.Country.Latitude,
+		Longitude:     userData.Country.Longitude,
+		AddressDetail: userResponse.ProfileAddress{
+			Address: userData.Address,
+			RtRw:    userData.RtRw,
+			Subdistrict: userResponse.AddressLevel{
+				Id:   userData.Subdistrict.Id,
+				Name: userData.Subdistrict.Name,
+			},
+			District: userResponse.AddressLevel{
+				Id:   userData.Subdistrict.DistrictId,
+				Name: userData.Subdistrict.DistrictName,
+			},
+			City: userResponse.AddressLevel{
+				Id:   userData.Subdistrict.CityId,
+				Name: userData.Subdistrict.CityName,
+			},
+			Province: userResponse.AddressLevel{
+				Id:   userData.Subdistrict.ProvinceId,
+				Name: userData.Subdistrict.ProvinceName,
+			},
+			PostalCode: userData.Subdistrict.PostalCode,
+			Country: userResponse.AddressCountry{
+				Id:            userData.Country.Id,
+				Code:          userData.Country.Code,
+				Name:          userData.Country.Name,
+				ContinentName: userData.Country.ContinentName,
+			},
+			Latitude:  userData.Country.Latitude,
+			Longitude: userData.Country.Longitude,
+		},
+		Preferences:    preferencesResponse(userData.Preferences),
+		AvatarUrl:      userData.Avatar.Variants[avatarDefaultVariant],
+		AvatarVariants: userData.Avatar.Variants,
+		Version:        userData.Version,
+	}
+}
+
+func (q queryUsecase) GetAddresses(origCtx context.Context, payload userRequest.GetAddresses) ([]userResponse.Address, error) {
+	domain := "userUsecase-GetAddresses"
+	span, ctx := apm.StartSpanOptions(origCtx, domain, "function", apm.SpanOptions{
+		Start:  time.Now(),
+		Parent: apm.TraceContext{},
+	})
+	defer span.End()
+	respUser := <-q.userRepositoryQuery.FindOneUserId(ctx, payload.UserId)
+	if respUser.Error != nil {
+		return nil, respUser.Error
+	}
+	if respUser.Data == nil {
+		msg := "User Not Found"
+		q.logger.Error(ctx, msg, payload)
+		return nil, errors.NotFound(msg)
+	}
+	userData, ok := respUser.Data.(*userEntity.User)
+	if !ok {
+		return nil, errors.InternalServerError("cannot parsing data")
+	}
+
+	entries, err := loadAddressBook(ctx, q.userRepositoryQuery, q.userRepositoryCommand, *userData)
+	if err != nil {
+		return nil, err
+	}
+	response := []userResponse.Address{}
+	for _, entry := range entries {
+		response = append(response, addressResponse(entry))
+	}
+	return response, nil
+}
+
+// loadAddressBook returns the saved addresses of a user. Users registered before the
+// address book existed, with no entry stored at all, get their profile address migrated as the first, default entry.
+// The migrated entry id is derived from the user id, concurrent first reads upsert the same entry.
+func loadAddressBook(ctx context.Context, umq user.MongodbRepositoryQuery, umc user.MongodbRepositoryCommand,
+	userData userEntity.User) ([]userEntity.AddressBookEntry, error) {
+	respAddresses := <-umq.FindAddressesByUserId(ctx, userData.UserId)
+	if respAddresses.Error != nil {
+		return nil, respAddresses.Error
+	}
+	var entries []userEntity.AddressBookEntry
+	if data, ok := respAddresses.Data.(*[]userEntity.AddressBookEntry); ok && data != nil {
+		entries = *data
+	}
+	if len(entries) > 0 || userData.Country.Id == 0 {
+		return entries, nil
+	}
+	// Only a user who never had an address book is migrated, one who deleted every entry keeps it empty
+	respCount := <-umq.CountAddressesByUserId(ctx, userData.UserId)
+	if respCount.Error != nil {
+		return nil, respCount.Error
+	}
+	if respCount.Count > 0 {
+		return entries, nil
+	}
+
+	entry := userEntity.AddressBookEntry{
+		AddressId:     migratedAddressId(userData.UserId),
+		UserId:        userData.UserId,
+		Label:         "home",
+		RecipientName: userData.FullName,
+		MobileNumber:  userData.MobileNumber,
+		Address:       userData.Address,
+		Subdistrict:   userData.Subdistrict,
+		Country:       userData.Country,
+		RtRw:          userData.RtRw,
+		IsDefault:     true,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	respAddress := <-umc.UpsertOneAddress(ctx, entry)
+	if respAddress.Error != nil {
+		return nil, respAddress.Error
+	}
+	return []userEntity.AddressBookEntry{entry}, nil
+}
+
+func migratedAddressId(userId string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("users-address:"+userId)).String()
+}
+
+func addressResponse(entry userEntity.AddressBookEntry) userResponse.Address {
+	return userResponse.Address{
+		AddressId:       entry.AddressId,
+		Label:           entry.Label,
+		RecipientName:   entry.RecipientName,
+		MobileNumber:    entry.MobileNumber,
+		Address:         entry.Address,
+		RtRw:            entry.RtRw,
+		SubdistrictId:   entry.Subdistrict.Id,
+		SubdistrictName: entry.Subdistrict.Name,
+		DistrictId:      entry.Subdistrict.DistrictId,
+		DistrictName:    entry.Subdistrict.DistrictName,
+		CityId:          entry.Subdistrict.CityId,
+		CityName:        entry.Subdistrict.CityName,
+		ProvinceId:      entry.Subdistrict.ProvinceId,
+		ProvinceName:    entry.Subdistrict.ProvinceName,
+		PostalCode:      entry.Subdistrict.PostalCode,
+		CountryId:       entry.Country.Id,
+		CountryCode:     entry.Country.Code,
+		CountryName:     entry.Country.Name,
+		Latitude:        entry.Country.Latitude,
+		Longitude:       entry.Country.Longitude,
+		IsDefault:       entry.IsDefault,
+		CreatedAt:       entry.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       entry.UpdatedAt.Format(time.RFC3339),
 	}
-	return &response, nil
 }
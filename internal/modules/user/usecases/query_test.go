@@ -3,11 +3,15 @@ package usecases_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"user-service/configs"
 	"user-service/internal/modules/user"
 	userEntity "user-service/internal/modules/user/models/entity"
 	userRequest "user-service/internal/modules/user/models/request"
+	userResponse "user-service/internal/modules/user/models/response"
 	uc "user-service/internal/modules/user/usecases"
 	"user-service/internal/pkg/errors"
 	"user-service/internal/pkg/helpers"
@@ -33,6 +37,7 @@ func (suite *QueryUsecaseTestSuite) SetupTest() {
 	suite.mockUserRepositoryCommand = &mockcert.MongodbRepositoryCommand{}
 	suite.mockLogger = &mocklog.Logger{}
 	suite.ctx = context.Background()
+	configs.GetConfig().SecretSignature = "secret"
 	suite.usecase = uc.NewQueryUsecase(
 		suite.mockUserRepositoryQuery,
 		suite.mockUserRepositoryCommand,
@@ -142,3 +147,555 @@ func (suite *QueryUsecaseTestSuite) TestGetProfileParsingError() {
 	assert.Error(suite.T(), err, "cannot parsing data")
 	assert.Nil(suite.T(), result)
 }
+
+func (suite *QueryUsecaseTestSuite) TestGetDataExportReady() {
+	// Arrange
+	payload := userRequest.GetDataExport{
+		UserId:   "12345",
+		ExportId: "export-1",
+	}
+	mockDataExportResponse := helpers.Result{
+		Data: &userEntity.DataExport{
+			ExportId:    "export-1",
+			UserId:      "12345",
+			Format:      "json",
+			Status:      userEntity.DataExportStatusReady,
+			RequestedAt: time.Now(),
+			CompletedAt: time.Now(),
+			ExpiredAt:   time.Now().Add(24 * time.Hour),
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneDataExport", mock.Anything, payload.ExportId).Return(mockChannel(mockDataExportResponse))
+
+	// Act
+	result, err := suite.usecase.GetDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userEntity.DataExportStatusReady, result.Status)
+	assert.Contains(suite.T(), result.DownloadUrl, "/api/users/v1/data-export/export-1/download?expires=")
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetDataExportPending() {
+	// Arrange
+	payload := userRequest.GetDataExport{
+		UserId:   "12345",
+		ExportId: "export-1",
+	}
+	mockDataExportResponse := helpers.Result{
+		Data: &userEntity.DataExport{
+			ExportId:    "export-1",
+			UserId:      "12345",
+			Status:      userEntity.DataExportStatusPending,
+			RequestedAt: time.Now(),
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneDataExport", mock.Anything, payload.ExportId).Return(mockChannel(mockDataExportResponse))
+
+	// Act
+	result, err := suite.usecase.GetDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result.DownloadUrl)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetDataExportOtherUser() {
+	// Arrange
+	payload := userRequest.GetDataExport{
+		UserId:   "other-user",
+		ExportId: "export-1",
+	}
+	mockDataExportResponse := helpers.Result{
+		Data: &userEntity.DataExport{
+			ExportId: "export-1",
+			UserId:   "12345",
+			Status:   userEntity.DataExportStatusReady,
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneDataExport", mock.Anything, payload.ExportId).Return(mockChannel(mockDataExportResponse))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	result, err := suite.usecase.GetDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.Error(suite.T(), err, "Data export not found")
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *QueryUsecaseTestSuite) TestDownloadDataExportSuccess() {
+	// Arrange
+	expires := time.Now().Add(time.Hour).Unix()
+	payload := userRequest.DownloadDataExport{
+		ExportId:  "export-1",
+		Expires:   expires,
+		Signature: helpers.GenerateSignature(fmt.Sprintf("export-1:%d", expires)),
+	}
+	mockDataExportResponse := helpers.Result{
+		Data: &userEntity.DataExport{
+			ExportId:  "export-1",
+			UserId:    "12345",
+			Status:    userEntity.DataExportStatusReady,
+			Archive:   []byte("archive"),
+			ExpiredAt: time.Now().Add(24 * time.Hour),
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneDataExport", mock.Anything, payload.ExportId).Return(mockChannel(mockDataExportResponse))
+	suite.mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	result, err := suite.usecase.DownloadDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "data-export-export-1.zip", result.FileName)
+	assert.Equal(suite.T(), []byte("archive"), result.Content)
+}
+
+func (suite *QueryUsecaseTestSuite) TestDownloadDataExportInvalidSignature() {
+	// Arrange
+	payload := userRequest.DownloadDataExport{
+		ExportId:  "export-1",
+		Expires:   time.Now().Add(time.Hour).Unix(),
+		Signature: "invalid",
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	result, err := suite.usecase.DownloadDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.Error(suite.T(), err, "Invalid download link")
+	assert.Nil(suite.T(), result)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindOneDataExport", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestDownloadDataExportNoSignatureSecret() {
+	// Arrange
+	expires := time.Now().Add(time.Hour).Unix()
+	configs.GetConfig().SecretSignature = ""
+	payload := userRequest.DownloadDataExport{
+		ExportId:  "export-1",
+		Expires:   expires,
+		Signature: helpers.GenerateSignature(fmt.Sprintf("export-1:%d", expires)),
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	result, err := suite.usecase.DownloadDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.Error(suite.T(), err, "Invalid download link")
+	assert.Nil(suite.T(), result)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindOneDataExport", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestDownloadDataExportLinkExpired() {
+	// Arrange
+	expires := time.Now().Add(-time.Minute).Unix()
+	payload := userRequest.DownloadDataExport{
+		ExportId:  "export-1",
+		Expires:   expires,
+		Signature: helpers.GenerateSignature(fmt.Sprintf("export-1:%d", expires)),
+	}
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+
+	// Act
+	result, err := suite.usecase.DownloadDataExport(suite.ctx, payload)
+
+	// Assert
+	assert.Error(suite.T(), err, "Download link expired")
+	assert.Nil(suite.T(), result)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetTermsVersion() {
+	// Act
+	result, err := suite.usecase.GetTermsVersion(suite.ctx)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1.0", result.TermsVersion)
+	assert.Equal(suite.T(), "1.0", result.PrivacyPolicyVersion)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetConsentsAcceptanceRequired() {
+	payload := userRequest.GetConsents{
+		UserId: "12345",
+	}
+	mockFindConsents := helpers.Result{
+		Data: &[]userEntity.Consent{
+			{Purpose: userEntity.ConsentPurposeMarketingEmail, Granted: true, Version: "1.0", UpdatedAt: time.Now()},
+			{Purpose: userEntity.ConsentPurposeTerms, Granted: true, Version: "0.9", UpdatedAt: time.Now()},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(mockFindConsents))
+	// Act
+	result, err := suite.usecase.GetConsents(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.AcceptanceRequired)
+	assert.Len(suite.T(), result.Consents, 2)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetConsentsErr() {
+	payload := userRequest.GetConsents{
+		UserId: "12345",
+	}
+	mockFindConsents := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindConsentsByUserId", mock.Anything, "12345").Return(mockChannel(mockFindConsents))
+	// Act
+	_, err := suite.usecase.GetConsents(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetMarketingConsentGranted() {
+	payload := userRequest.GetMarketingConsent{
+		UserId:  "12345",
+		Channel: "sms",
+	}
+	mockFindConsent := helpers.Result{
+		Data: &userEntity.Consent{
+			UserId:    "12345",
+			Purpose:   userEntity.ConsentPurposeMarketingSms,
+			Granted:   true,
+			UpdatedAt: time.Now(),
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &userEntity.User{UserId: "12345", Status: userEntity.StatusActive},
+	}))
+	suite.mockUserRepositoryQuery.On("FindOneConsent", mock.Anything, "12345", userEntity.ConsentPurposeMarketingSms).Return(mockChannel(mockFindConsent))
+	// Act
+	result, err := suite.usecase.GetMarketingConsent(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Granted)
+	assert.Equal(suite.T(), "sms", result.Channel)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetMarketingConsentNotFound() {
+	payload := userRequest.GetMarketingConsent{
+		UserId:  "12345",
+		Channel: "email",
+	}
+	mockFindConsent := helpers.Result{
+		Data:  nil,
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &userEntity.User{UserId: "12345", Status: userEntity.StatusActive},
+	}))
+	suite.mockUserRepositoryQuery.On("FindOneConsent", mock.Anything, "12345", userEntity.ConsentPurposeMarketingEmail).Return(mockChannel(mockFindConsent))
+	// Act
+	result, err := suite.usecase.GetMarketingConsent(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Granted)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetMarketingConsentPendingDeletion() {
+	payload := userRequest.GetMarketingConsent{
+		UserId:  "12345",
+		Channel: "email",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &userEntity.User{UserId: "12345", Status: userEntity.StatusPendingDeletion},
+	}))
+	// Act
+	result, err := suite.usecase.GetMarketingConsent(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Granted)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindOneConsent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindMarketingAudience() {
+	payload := userRequest.FindMarketingAudience{
+		CityId:  "3171",
+		Channel: "sms",
+		Page:    1,
+		Size:    2,
+	}
+	mockFindAudience := helpers.Result{
+		Data: &[]userEntity.Audience{{
+			Users: []userEntity.AudienceMember{{UserId: "1"}, {UserId: "2"}},
+			Total: []userEntity.AudienceTotal{{Count: 5}},
+		}},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindMarketingAudience", mock.Anything, payload, userEntity.ConsentPurposeMarketingSms).Return(mockChannel(mockFindAudience))
+	// Act
+	result, err := suite.usecase.FindMarketingAudience(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"1", "2"}, result.CollectionData)
+	assert.Equal(suite.T(), int64(5), result.MetaData.TotalData)
+	assert.Equal(suite.T(), int64(3), result.MetaData.TotalPage)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindMarketingAudienceByCursor() {
+	payload := userRequest.FindMarketingAudience{
+		CityId:  "3171",
+		Channel: "email",
+		Size:    2,
+		Cursor:  "after-1",
+	}
+	mockFindAudience := helpers.Result{
+		Data: &[]userEntity.Audience{{
+			Users: []userEntity.AudienceMember{{UserId: "2"}, {UserId: "3"}},
+		}},
+		MetaData: "after-3",
+	}
+	suite.mockUserRepositoryQuery.On("FindMarketingAudience", mock.Anything, payload, userEntity.ConsentPurposeMarketingEmail).Return(mockChannel(mockFindAudience))
+	// Act
+	result, err := suite.usecase.FindMarketingAudience(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"2", "3"}, result.CollectionData)
+	assert.Equal(suite.T(), "after-3", result.MetaData.NextCursor)
+	assert.Equal(suite.T(), int64(0), result.MetaData.Page)
+	assert.Equal(suite.T(), int64(0), result.MetaData.TotalData)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindMarketingAudienceEmpty() {
+	payload := userRequest.FindMarketingAudience{
+		CountryId: "ID",
+		Channel:   "email",
+		Page:      1,
+		Size:      10,
+	}
+	mockFindAudience := helpers.Result{
+		Data:  &[]userEntity.Audience{{}},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindMarketingAudience", mock.Anything, payload, userEntity.ConsentPurposeMarketingEmail).Return(mockChannel(mockFindAudience))
+	// Act
+	result, err := suite.usecase.FindMarketingAudience(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result.CollectionData)
+	assert.Equal(suite.T(), int64(0), result.MetaData.TotalData)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindMarketingAudienceErrFilters() {
+	payload := userRequest.FindMarketingAudience{
+		CityId:     "3171",
+		ProvinceId: "31",
+		Channel:    "email",
+		Page:       1,
+		Size:       10,
+	}
+	// Act
+	_, err := suite.usecase.FindMarketingAudience(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+	suite.mockUserRepositoryQuery.AssertNotCalled(suite.T(), "FindMarketingAudience", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestFindMarketingAudienceErr() {
+	payload := userRequest.FindMarketingAudience{
+		ProvinceId: "31",
+		Channel:    "email",
+		Page:       1,
+		Size:       10,
+	}
+	mockFindAudience := helpers.Result{
+		Data:  nil,
+		Error: errors.InternalServerError("error"),
+	}
+	suite.mockUserRepositoryQuery.On("FindMarketingAudience", mock.Anything, payload, userEntity.ConsentPurposeMarketingEmail).Return(mockChannel(mockFindAudience))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.FindMarketingAudience(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetPreferencesDefaults() {
+	payload := userRequest.GetPreferences{
+		UserId: "12345",
+	}
+	mockFindOneUser := helpers.Result{
+		Data:  &userEntity.User{UserId: "12345"},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	// Act
+	result, err := suite.usecase.GetPreferences(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "id-ID", result.Locale)
+	assert.Equal(suite.T(), "Asia/Jakarta", result.Timezone)
+	assert.True(suite.T(), result.NotificationChannels.Email)
+	assert.Empty(suite.T(), result.FavouriteGenres)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetPreferencesStored() {
+	payload := userRequest.GetPreferences{
+		UserId: "12345",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId: "12345",
+			Preferences: userEntity.Preferences{
+				Locale:          "en-GB",
+				Timezone:        "Europe/London",
+				FavouriteGenres: []string{"Indie"},
+				UpdatedAt:       time.Now(),
+			},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockFindOneUser))
+	// Act
+	result, err := suite.usecase.GetPreferences(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "en-GB", result.Locale)
+	assert.False(suite.T(), result.NotificationChannels.Email)
+	assert.Equal(suite.T(), []string{"Indie"}, result.FavouriteGenres)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetPreferencesNotFound() {
+	payload := userRequest.GetPreferences{
+		UserId: "12345",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: nil, Error: nil}))
+	suite.mockLogger.On("Error", mock.Anything, mock.Anything, mock.Anything)
+	// Act
+	_, err := suite.usecase.GetPreferences(suite.ctx, payload)
+	// Assert
+	assert.Error(suite.T(), err)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetAddressesMigratesProfileAddress() {
+	payload := userRequest.GetAddresses{
+		UserId: "12345",
+	}
+	mockFindOneUser := helpers.Result{
+		Data: &userEntity.User{
+			UserId:      "12345",
+			FullName:    "alif",
+			Address:     "Jalan jalan",
+			Subdistrict: userEntity.Subdistrict{Id: "1", Name: "Desa kkn"},
+			Country:     userEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+		},
+		Error: nil,
+	}
+	findOneUser := func(ctx context.Context, userId string) <-chan helpers.Result {
+		return mockChannel(mockFindOneUser)
+	}
+	findAddresses := func(ctx context.Context, userId string) <-chan helpers.Result {
+		return mockChannel(helpers.Result{Data: nil})
+	}
+	countAddresses := func(ctx context.Context, userId string) <-chan helpers.Result {
+		return mockChannel(helpers.Result{Count: 0})
+	}
+	upsertOneAddress := func(ctx context.Context, address userEntity.AddressBookEntry) <-chan helpers.Result {
+		return mockChannel(helpers.Result{})
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(findOneUser)
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(findAddresses)
+	suite.mockUserRepositoryQuery.On("CountAddressesByUserId", mock.Anything, "12345").Return(countAddresses)
+	suite.mockUserRepositoryCommand.On("UpsertOneAddress", mock.Anything, mock.MatchedBy(func(a userEntity.AddressBookEntry) bool {
+		return a.UserId == "12345" && a.Label == "home" && a.IsDefault && a.RecipientName == "alif"
+	})).Return(upsertOneAddress)
+	// Act
+	result, err := suite.usecase.GetAddresses(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+	assert.Equal(suite.T(), "Jalan jalan", result[0].Address)
+	assert.Equal(suite.T(), "Desa kkn", result[0].SubdistrictName)
+	assert.True(suite.T(), result[0].IsDefault)
+	// Every migration of the same user upserts the same entry
+	otherResult, err := suite.usecase.GetAddresses(suite.ctx, payload)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), result[0].AddressId, otherResult[0].AddressId)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetAddressesAllDeletedNotMigratedAgain() {
+	payload := userRequest.GetAddresses{
+		UserId: "12345",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{
+		Data: &userEntity.User{
+			UserId:  "12345",
+			Address: "Jalan jalan",
+			Country: userEntity.Country{Id: 1, Code: "ID", Name: "Indonesia"},
+		},
+	}))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &[]userEntity.AddressBookEntry{}}))
+	// The migrated entry is stored but deleted by the user
+	suite.mockUserRepositoryQuery.On("CountAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Count: 1}))
+	// Act
+	result, err := suite.usecase.GetAddresses(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetAddressesWithoutProfileAddress() {
+	payload := userRequest.GetAddresses{
+		UserId: "12345",
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &userEntity.User{UserId: "12345"}}))
+	suite.mockUserRepositoryQuery.On("FindAddressesByUserId", mock.Anything, "12345").Return(mockChannel(helpers.Result{Data: &[]userEntity.AddressBookEntry{}}))
+	// Act
+	result, err := suite.usecase.GetAddresses(suite.ctx, payload)
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), result)
+	suite.mockUserRepositoryCommand.AssertNotCalled(suite.T(), "UpsertOneAddress", mock.Anything, mock.Anything)
+}
+
+func (suite *QueryUsecaseTestSuite) TestGetProfileAddressDetail() {
+	// Arrange
+	payload := userRequest.GetProfile{
+		UserId: "12345",
+	}
+	mockUserQueryResponse := helpers.Result{
+		Data: &userEntity.User{
+			UserId:  "12345",
+			Address: "Jalan jalan",
+			RtRw:    "001/002",
+			Subdistrict: userEntity.Subdistrict{
+				Id:           "3201010001",
+				Name:         "Cibinong",
+				DistrictId:   "3201010",
+				DistrictName: "Cibinong",
+				CityId:       "3201",
+				CityName:     "Kabupaten Bogor",
+				ProvinceId:   "32",
+				ProvinceName: "Jawa Barat",
+			},
+			Country: userEntity.Country{Id: 100, Code: "ID", Name: "Indonesia", Latitude: "-6.48", Longitude: "106.84"},
+		},
+		Error: nil,
+	}
+	suite.mockUserRepositoryQuery.On("FindOneUserId", mock.Anything, "12345").Return(mockChannel(mockUserQueryResponse))
+
+	// Act
+	result, err := suite.usecase.GetProfile(suite.ctx, payload)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "-6.48", result.Latitude)
+	assert.Equal(suite.T(), "106.84", result.Longitude)
+	assert.Equal(suite.T(), "001/002", result.AddressDetail.RtRw)
+	assert.Equal(suite.T(), userResponse.AddressLevel{Id: "3201010", Name: "Cibinong"}, result.AddressDetail.District)
+	assert.Equal(suite.T(), userResponse.AddressLevel{Id: "32", Name: "Jawa Barat"}, result.AddressDetail.Province)
+	assert.Equal(suite.T(), 100, result.AddressDetail.Country.Id)
+	assert.Equal(suite.T(), "106.84", result.AddressDetail.Longitude)
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 	userEntity "user-service/internal/modules/user/models/entity"
 	userRequest "user-service/internal/modules/user/models/request"
 	userResponse "user-service/internal/modules/user/models/response"
@@ -10,6 +11,14 @@ import (
 
 type UsecaseQuery interface {
 	GetProfile(origCtx context.Context, payload userRequest.GetProfile) (*userResponse.GetProfile, error)
+	GetDataExport(origCtx context.Context, payload userRequest.GetDataExport) (*userResponse.DataExport, error)
+	DownloadDataExport(origCtx context.Context, payload userRequest.DownloadDataExport) (*userResponse.DataExportFile, error)
+	GetTermsVersion(origCtx context.Context) (*userResponse.TermsVersion, error)
+	GetConsents(origCtx context.Context, payload userRequest.GetConsents) (*userResponse.Consents, error)
+	GetMarketingConsent(origCtx context.Context, payload userRequest.GetMarketingConsent) (*userResponse.MarketingConsent, error)
+	FindMarketingAudience(origCtx context.Context, payload userRequest.FindMarketingAudience) (*userResponse.MarketingAudience, error)
+	GetPreferences(origCtx context.Context, payload userRequest.GetPreferences) (*userResponse.Preferences, error)
+	GetAddresses(origCtx context.Context, payload userRequest.GetAddresses) ([]userResponse.Address, error)
 }
 
 type UsecaseCommand interface {
@@ -17,15 +26,45 @@ type UsecaseCommand interface {
 	RegisterUser(origCtx context.Context, payload userRequest.RegisterUser) (*userResponse.RegisterUser, error)
 	VerifyRegisterUser(origCtx context.Context, payload userRequest.VerifyRegisterUser) (*userResponse.VerifyRegister, error)
 	LoginUser(origCtx context.Context, payload userRequest.LoginUser) (*userResponse.LoginUserResp, error)
+	DeleteAccount(origCtx context.Context, payload userRequest.DeleteAccount, userId string) (*userResponse.DeleteAccount, error)
+	ProcessAccountDeletion(origCtx context.Context) (int, error)
+	RequestDataExport(origCtx context.Context, payload userRequest.RequestDataExport, userId string) (*userResponse.DataExport, error)
+	ProcessDataExport(origCtx context.Context) (int, error)
+	UpdateConsents(origCtx context.Context, payload userRequest.UpdateConsents, userId string) (*userResponse.Consents, error)
+	UpdatePreferences(origCtx context.Context, payload userRequest.UpdatePreferences, userId string) (*userResponse.Preferences, error)
+	PatchUser(origCtx context.Context, payload userRequest.PatchUser) (*userResponse.GetProfile, error)
+	UploadAvatar(origCtx context.Context, payload userRequest.UploadAvatar) (*userResponse.Avatar, error)
+	CreateAddress(origCtx context.Context, payload userRequest.SaveAddress, userId string) (*userResponse.Address, error)
+	UpdateAddress(origCtx context.Context, payload userRequest.SaveAddress, userId string) (*userResponse.Address, error)
+	DeleteAddress(origCtx context.Context, payload userRequest.AddressBookEntry) error
+	SetDefaultAddress(origCtx context.Context, payload userRequest.AddressBookEntry) (*userResponse.Address, error)
 }
 
 type MongodbRepositoryCommand interface {
 	UpsertOneUserTemp(ctx context.Context, user userEntity.User) <-chan wrapper.Result
 	UpsertOneUser(ctx context.Context, user userEntity.User) <-chan wrapper.Result
+	UpdateOneUserDueForDeletion(ctx context.Context, user userEntity.User, before time.Time) <-chan wrapper.Result
+	UpsertOneDataExport(ctx context.Context, dataExport userEntity.DataExport) <-chan wrapper.Result
+	UpsertOneConsent(ctx context.Context, consent userEntity.Consent) <-chan wrapper.Result
+	UpdateOneUserWithVersion(ctx context.Context, user userEntity.User, version int64) <-chan wrapper.Result
+	UpsertOneAddress(ctx context.Context, address userEntity.AddressBookEntry) <-chan wrapper.Result
+	DeleteDataExportsByUserId(ctx context.Context, userId string) <-chan wrapper.Result
+	EraseAddressesByUserId(ctx context.Context, userId string, erasedAt time.Time) <-chan wrapper.Result
 }
 
 type MongodbRepositoryQuery interface {
 	FindOneUserId(ctx context.Context, userId string) <-chan wrapper.Result
 	FindOneByEmail(ctx context.Context, email string) <-chan wrapper.Result
 	FindOneByEmailUserTemp(ctx context.Context, email string) <-chan wrapper.Result
+	FindUsersDueForDeletion(ctx context.Context, before time.Time, size int64) <-chan wrapper.Result
+	FindOneDataExport(ctx context.Context, exportId string) <-chan wrapper.Result
+	FindDataExportsByStatus(ctx context.Context, status string, size int64) <-chan wrapper.Result
+	FindDataExportsByUserId(ctx context.Context, userId string) <-chan wrapper.Result
+	FindConsentsByUserId(ctx context.Context, userId string) <-chan wrapper.Result
+	FindOneConsent(ctx context.Context, userId string, purpose string) <-chan wrapper.Result
+	FindMarketingAudience(ctx context.Context, payload userRequest.FindMarketingAudience, purpose string) <-chan wrapper.Result
+	FindAddressesByUserId(ctx context.Context, userId string) <-chan wrapper.Result
+	CountAddressesByUserId(ctx context.Context, userId string) <-chan wrapper.Result
+	FindOneAddress(ctx context.Context, userId string, addressId string) <-chan wrapper.Result
+	FindDefaultAddress(ctx context.Context, userId string) <-chan wrapper.Result
 }
@@ -0,0 +1,9 @@
+package blobstore
+
+import "context"
+
+// BlobStore stores binary objects and returns the public URL they are served from
+type BlobStore interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,102 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"user-service/internal/pkg/blobstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlobStoreTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func (suite *BlobStoreTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+}
+
+func TestBlobStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BlobStoreTestSuite))
+}
+
+func (suite *BlobStoreTestSuite) TestLocalStorePutDelete() {
+	dir := suite.T().TempDir()
+	store := blobstore.NewLocalStore(dir, "http://localhost:9090/media/")
+
+	url, err := store.Put(suite.ctx, "avatars/12345/small.jpg", []byte("content"), "image/jpeg")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "http://localhost:9090/media/avatars/12345/small.jpg", url)
+
+	content, err := os.ReadFile(filepath.Join(dir, "avatars", "12345", "small.jpg"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "content", string(content))
+
+	assert.NoError(suite.T(), store.Delete(suite.ctx, "avatars/12345/small.jpg"))
+	assert.NoError(suite.T(), store.Delete(suite.ctx, "avatars/12345/small.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "avatars", "12345", "small.jpg"))
+	assert.True(suite.T(), os.IsNotExist(err))
+}
+
+func (suite *BlobStoreTestSuite) TestLocalStoreRejectsTraversal() {
+	store := blobstore.NewLocalStore(suite.T().TempDir(), "/media")
+
+	_, err := store.Put(suite.ctx, "../outside.jpg", []byte("content"), "image/jpeg")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *BlobStoreTestSuite) TestS3StorePut() {
+	var method, path, contentType, authorization, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		authorization = r.Header.Get("Authorization")
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := blobstore.NewS3Store(blobstore.S3Config{
+		Endpoint:  server.URL,
+		Region:    "ap-southeast-1",
+		Bucket:    "media",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	url, err := store.Put(suite.ctx, "avatars/12345/small.jpg", []byte("content"), "image/jpeg")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), server.URL+"/media/avatars/12345/small.jpg", url)
+	assert.Equal(suite.T(), http.MethodPut, method)
+	assert.Equal(suite.T(), "/media/avatars/12345/small.jpg", path)
+	assert.Equal(suite.T(), "image/jpeg", contentType)
+	assert.Equal(suite.T(), "content", body)
+	assert.True(suite.T(), strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access/"))
+	assert.Contains(suite.T(), authorization, "/ap-southeast-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=")
+}
+
+func (suite *BlobStoreTestSuite) TestS3StoreError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer server.Close()
+
+	store := blobstore.NewS3Store(blobstore.S3Config{
+		Endpoint: server.URL,
+		Bucket:   "media",
+	})
+
+	err := store.Delete(suite.ctx, "avatars/12345/small.jpg")
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "AccessDenied")
+}
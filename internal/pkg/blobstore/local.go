@@ -0,0 +1,58 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStore struct {
+	basePath  string
+	publicUrl string
+}
+
+// NewLocalStore keeps objects on the local filesystem under basePath,
+// publicUrl is the prefix the directory is served from
+func NewLocalStore(basePath string, publicUrl string) BlobStore {
+	return localStore{
+		basePath:  basePath,
+		publicUrl: strings.TrimRight(publicUrl, "/"),
+	}
+}
+
+func (l localStore) Put(ctx context.Context, key string, content []byte, contentType string) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+	return l.publicUrl + "/" + key, nil
+}
+
+func (l localStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path rejects keys escaping the base directory
+func (l localStore) path(key string) (string, error) {
+	path := filepath.Join(l.basePath, filepath.FromSlash(key))
+	rel, err := filepath.Rel(l.basePath, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", errors.New("invalid blob key")
+	}
+	return path, nil
+}
@@ -0,0 +1,8 @@
+package constants
+
+// kafka topic
+const (
+	KafkaTopicOtpUserRegistration    = `concert-send-otp-user-registration`
+	KafkaTopicUserDeleted            = `user.deleted`
+	KafkaTopicUserPreferencesUpdated = `user.preferences.updated`
+)
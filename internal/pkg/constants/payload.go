@@ -1,8 +1,11 @@
 package constants
 
+// MetaData describes a page of a list. A cursor page has no page number and only carries the totals
+// when they were asked for, NextCursor is empty on the last cursor page.
 type MetaData struct {
-	Page      int64 `json:"page"`
-	Count     int64 `json:"count"`
-	TotalPage int64 `json:"totalPage"`
-	TotalData int64 `json:"totalData"`
+	Page       int64  `json:"page"`
+	Count      int64  `json:"count"`
+	TotalPage  int64  `json:"totalPage"`
+	TotalData  int64  `json:"totalData"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
@@ -9,4 +9,7 @@ const (
 	RedisKeyLoginAttempt        = `LOGIN-ATTEMPT`
 	RedisKeyOtpRegister         = `OTP-REGISTER`
 	RedisKeyOtpLogin            = `OTP-LOGIN`
+	RedisKeyDataExportLimit     = `DATA-EXPORT-LIMIT`
+	RedisKeyAddressCache        = `ADDRESS-CACHE`
+	RedisKeyAddressCacheVersion = `ADDRESS-CACHE-VERSION`
 )
@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+
+	"user-service/internal/pkg/errors"
+)
+
+// keyset is what an opaque cursor holds: the sort it was issued for and the sort value and _id of the
+// last document of the page
+type keyset struct {
+	Field string        `bson:"f"`
+	By    string        `bson:"b"`
+	Value bson.RawValue `bson:"v"`
+	Id    bson.RawValue `bson:"i"`
+}
+
+// CursorSort orders by the sort field then by _id, so documents sharing a sort value keep a stable order
+func CursorSort(sort Sort) bson.D {
+	return bson.D{{Key: sort.FieldName, Value: sort.buildSortBy()}, {Key: "_id", Value: sort.buildSortBy()}}
+}
+
+// CursorFilter narrows filter to the documents after cursor in the CursorSort order, an empty cursor
+// starts from the first document. A cursor issued for another sort is rejected.
+func CursorFilter(filter interface{}, sort Sort, cursor string) (interface{}, error) {
+	if cursor == "" {
+		return filter, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.BadRequest("invalid cursor")
+	}
+	var position keyset
+	if err := bson.Unmarshal(decoded, &position); err != nil {
+		return nil, errors.BadRequest("invalid cursor")
+	}
+	// A document or an array would be read as query operators such as {"$ne": null} in the filter below
+	if !scalarValue(position.Value) || !scalarValue(position.Id) {
+		return nil, errors.BadRequest("invalid cursor")
+	}
+	if position.Field != sort.FieldName || position.By != sort.By {
+		return nil, errors.BadRequest("cursor does not match the sort order")
+	}
+
+	after := "$gt"
+	if sort.buildSortBy() < 0 {
+		after = "$lt"
+	}
+	return bson.M{"$and": bson.A{
+		filter,
+		bson.M{"$or": bson.A{
+			bson.M{sort.FieldName: bson.M{after: position.Value}},
+			bson.M{sort.FieldName: position.Value, "_id": bson.M{after: position.Id}},
+		}},
+	}}, nil
+}
+
+// scalarValue tells whether a cursor value can only be compared, not a document, an array or missing
+func scalarValue(value bson.RawValue) bool {
+	switch value.Type {
+	case 0, bsontype.EmbeddedDocument, bsontype.Array:
+		return false
+	default:
+		return true
+	}
+}
+
+// EncodeCursor is the cursor of the page following the document with the given sort value and _id
+func EncodeCursor(sort Sort, value interface{}, id interface{}) (string, error) {
+	position := bson.M{"f": sort.FieldName, "b": sort.By, "v": value, "i": id}
+	encoded, err := bson.Marshal(position)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot encode cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// NextCursor is the cursor of the page following document, the last document of a page. A document
+// without the sort field has no position to continue from, an empty cursor would end the listing early.
+func NextCursor(sort Sort, document bson.Raw) (string, error) {
+	value, err := document.LookupErr(strings.Split(sort.FieldName, ".")...)
+	if err != nil {
+		return "", errors.InternalServerError(fmt.Sprintf("cannot build cursor, document has no %s", sort.FieldName))
+	}
+	return EncodeCursor(sort, value, document.Lookup("_id"))
+}
+
+// decodeDocuments unmarshals raw documents into result, a pointer to a slice
+func decodeDocuments(documents []bson.Raw, result interface{}) error {
+	encoded, err := bson.Marshal(bson.D{{Key: "documents", Value: documents}})
+	if err != nil {
+		return err
+	}
+	return bson.Raw(encoded).Lookup("documents").Unmarshal(result)
+}
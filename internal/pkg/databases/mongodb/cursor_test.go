@@ -0,0 +1,70 @@
+package mongodb_test
+
+import (
+	"testing"
+	"user-service/internal/pkg/databases/mongodb"
+	"user-service/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCursorFilterFirstPage(t *testing.T) {
+	filter := bson.M{"deactivated": bson.M{"$ne": true}}
+
+	result, err := mongodb.CursorFilter(filter, mongodb.Sort{FieldName: "name", By: mongodb.SortAscending}, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, filter, result)
+}
+
+func TestCursorFilterAfterLastDocument(t *testing.T) {
+	sort := mongodb.Sort{FieldName: "name", By: mongodb.SortDescending}
+	document, _ := bson.Marshal(bson.M{"_id": 7, "name": "Papua"})
+	cursor, err := mongodb.NextCursor(sort, document)
+	assert.NoError(t, err)
+
+	result, err := mongodb.CursorFilter(bson.M{"deactivated": bson.M{"$ne": true}}, sort, cursor)
+
+	assert.NoError(t, err)
+	encoded, _ := bson.MarshalExtJSON(result, false, false)
+	assert.JSONEq(t, `{"$and":[{"deactivated":{"$ne":true}},{"$or":[{"name":{"$lt":"Papua"}},{"name":"Papua","_id":{"$lt":7}}]}]}`, string(encoded))
+}
+
+func TestCursorFilterErrSortChanged(t *testing.T) {
+	cursor, _ := mongodb.EncodeCursor(mongodb.Sort{FieldName: "name", By: mongodb.SortAscending}, "Bali", 3)
+
+	_, err := mongodb.CursorFilter(bson.M{}, mongodb.Sort{FieldName: "displayOrder", By: mongodb.SortAscending}, cursor)
+
+	assert.Equal(t, errors.BadRequest("cursor does not match the sort order"), err)
+}
+
+func TestCursorFilterErrMalformed(t *testing.T) {
+	_, err := mongodb.CursorFilter(bson.M{}, mongodb.Sort{FieldName: "name", By: mongodb.SortAscending}, "not a cursor")
+
+	assert.Equal(t, errors.BadRequest("invalid cursor"), err)
+}
+
+func TestCursorFilterErrOperatorValue(t *testing.T) {
+	sort := mongodb.Sort{FieldName: "name", By: mongodb.SortAscending}
+	cursor, _ := mongodb.EncodeCursor(sort, bson.M{"$ne": nil}, 3)
+
+	_, err := mongodb.CursorFilter(bson.M{}, sort, cursor)
+
+	assert.Equal(t, errors.BadRequest("invalid cursor"), err)
+}
+
+func TestNextCursorErrMissingSortField(t *testing.T) {
+	document, _ := bson.Marshal(bson.M{"_id": 7, "name": "Indonesia"})
+
+	cursor, err := mongodb.NextCursor(mongodb.Sort{FieldName: "displayOrder", By: mongodb.SortAscending}, document)
+
+	assert.Error(t, err)
+	assert.Empty(t, cursor)
+}
+
+func TestCursorSort(t *testing.T) {
+	sort := mongodb.CursorSort(mongodb.Sort{FieldName: "id", By: mongodb.SortAscending})
+
+	assert.Equal(t, bson.D{{Key: "id", Value: 1}, {Key: "_id", Value: 1}}, sort)
+}
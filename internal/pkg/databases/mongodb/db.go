@@ -88,9 +88,9 @@ func (m MongoDBLogger) FindAllData(payload FindAllData, ctx context.Context) <-c
 
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(err, "Error Mongodb Connection"),
 			}
 		}
 
@@ -100,9 +100,9 @@ func (m MongoDBLogger) FindAllData(payload FindAllData, ctx context.Context) <-c
 
 		if err != nil {
 			msg := "cannot unmarshal result"
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(err, msg),
 			}
 		}
 
@@ -110,7 +110,7 @@ func (m MongoDBLogger) FindAllData(payload FindAllData, ctx context.Context) <-c
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 
 		// handle countdata
@@ -140,6 +140,121 @@ func (m MongoDBLogger) FindAllData(payload FindAllData, ctx context.Context) <-c
 	return output
 }
 
+// FindByCursor pages by keyset instead of skip, Cursor is empty for the first page and the sort field
+// must be set on every document. CountData is optional, counting scans every match of Filter.
+type FindByCursor struct {
+	Result         interface{}
+	CountData      *int64
+	CollectionName string
+	Filter         interface{}
+	Sort           Sort
+	Cursor         string
+	Size           int64
+}
+
+// FindByCursor costs the same on any page and neither repeats nor skips documents written between pages.
+// MetaData of the result is the cursor of the next page, empty on the last page.
+func (m MongoDBLogger) FindByCursor(payload FindByCursor, ctx context.Context) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+
+		start := time.Now()
+
+		// A size below one cannot hold the extra document and would slice the page out of range
+		if payload.Size <= 0 {
+			output <- wrapper.Result{
+				Error: errors.BadRequest("size must be at least 1"),
+			}
+			return
+		}
+
+		filter, err := CursorFilter(payload.Filter, payload.Sort, payload.Cursor)
+		if err != nil {
+			output <- wrapper.Result{
+				Error: err,
+			}
+			return
+		}
+
+		collection := m.mongoClient.Database(m.dbName).Collection(payload.CollectionName)
+
+		// One more document than asked tells whether there is a next page
+		findOption := options.Find().SetSort(CursorSort(payload.Sort)).SetLimit(payload.Size + 1)
+
+		cursor, err := collection.Find(ctx, filter, findOption)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb connection"),
+			}
+			return
+		}
+		defer cursor.Close(ctx)
+
+		documents := make([]bson.Raw, 0)
+		if err := cursor.All(ctx, &documents); err != nil {
+			msg := "cannot unmarshal result"
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.Wrap(err, msg),
+			}
+			return
+		}
+
+		next := ""
+		if int64(len(documents)) > payload.Size {
+			documents = documents[:payload.Size]
+			next, err = NextCursor(payload.Sort, documents[len(documents)-1])
+			if err != nil {
+				m.logger.Error(ctx, "cannot build next cursor", payload, log.Err(err))
+				output <- wrapper.Result{
+					Error: err,
+				}
+				return
+			}
+		}
+		if err := decodeDocuments(documents, payload.Result); err != nil {
+			msg := "cannot unmarshal result"
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.Wrap(err, msg),
+			}
+			return
+		}
+
+		finish := time.Now()
+		if finish.Sub(start).Seconds() > 10 {
+			j, _ := json.Marshal(payload.Filter)
+			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
+			m.logger.Error(ctx, msg, payload)
+		}
+
+		result := wrapper.Result{
+			Data:     payload.Result,
+			MetaData: next,
+		}
+		if payload.CountData != nil {
+			resp := <-m.CountData(CountData{
+				CollectionName: payload.CollectionName,
+				Result:         payload.CountData,
+				Filter:         payload.Filter,
+			}, ctx)
+			if resp.Error != nil {
+				output <- wrapper.Result{
+					Error: errors.InternalServerError("Error Mongodb Connection"),
+				}
+				return
+			}
+			result.Count = resp.Count
+		}
+		output <- result
+	}()
+	return output
+}
+
 type FindOne struct {
 	Result         interface{}
 	CollectionName string
@@ -158,24 +273,24 @@ func (m MongoDBLogger) FindOne(payload FindOne, ctx context.Context) <-chan wrap
 
 		if documentReturned.Err() != nil {
 			if documentReturned.Err() == mongo.ErrNoDocuments {
-				m.logger.Error(ctx, fmt.Sprintf("%v %v", "mongo-query-noDocuments", mongo.ErrNoDocuments.Error()), fmt.Sprintf("%+v", payload))
+				m.logger.Error(ctx, fmt.Sprintf("%v %v", "mongo-query-noDocuments", mongo.ErrNoDocuments.Error()), payload)
 				output <- wrapper.Result{
 					Data: nil,
 				}
 			}
 
 			msg := fmt.Sprintf("Error Mongodb Connection %s", documentReturned.Err())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(documentReturned.Err(), "Error Mongodb Connection"),
 			}
 		}
 
 		if err := documentReturned.Decode(payload.Result); err != nil {
 			msg := "cannot unmarshal result"
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(err, msg),
 			}
 		}
 		output <- wrapper.Result{
@@ -186,7 +301,7 @@ func (m MongoDBLogger) FindOne(payload FindOne, ctx context.Context) <-chan wrap
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 	}()
 
@@ -212,10 +327,11 @@ func (m MongoDBLogger) CountData(payload CountData, ctx context.Context) <-chan
 
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(err, "Error Mongodb Connection"),
 			}
+			return
 		}
 
 		if payload.Result != nil {
@@ -229,7 +345,7 @@ func (m MongoDBLogger) CountData(payload CountData, ctx context.Context) <-chan
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 	}()
 
@@ -257,7 +373,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 		pByte, err := bson.Marshal(payload.Document)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb"),
 			}
@@ -267,7 +383,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 		err = bson.Unmarshal(pByte, &update)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb"),
 			}
@@ -283,7 +399,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 
 			if err != nil {
 				msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-				m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+				m.logger.Error(ctx, msg, payload)
 				return nil, errors.InternalServerError("Error mongodb connection")
 			}
 			return nil, nil
@@ -292,7 +408,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 		session, err := m.mongoClient.StartSession()
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Session : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb session"),
 			}
@@ -302,7 +418,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 		_, err = session.WithTransaction(ctx, callback, txnOpts)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Transaction : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb transaction"),
 			}
@@ -313,7 +429,7 @@ func (m MongoDBLogger) UpsertOne(payload UpdateOne, ctx context.Context) <-chan
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 	}()
 
@@ -341,7 +457,7 @@ func (m MongoDBLogger) InsertOne(payload InsertOne, ctx context.Context) <-chan
 		_, err := collection.InsertOne(ctx, payload.Document)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb connection"),
 			}
@@ -352,7 +468,7 @@ func (m MongoDBLogger) InsertOne(payload InsertOne, ctx context.Context) <-chan
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Document)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 
 		output <- wrapper.Result{
@@ -375,7 +491,7 @@ func (m MongoDBLogger) UpdateOne(payload UpdateOne, ctx context.Context) <-chan
 		pByte, err := bson.Marshal(payload.Document)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb"),
 			}
@@ -385,21 +501,22 @@ func (m MongoDBLogger) UpdateOne(payload UpdateOne, ctx context.Context) <-chan
 		err = bson.Unmarshal(pByte, &update)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb"),
 			}
 		}
 
 		doc := bson.D{{Key: "$set", Value: update}}
-		_, err = collection.UpdateOne(ctx, payload.Filter, doc)
+		res, err := collection.UpdateOne(ctx, payload.Filter, doc)
 
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb connection"),
 			}
+			return
 		}
 
 		finish := time.Now()
@@ -407,11 +524,204 @@ func (m MongoDBLogger) UpdateOne(payload UpdateOne, ctx context.Context) <-chan
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 
 		output <- wrapper.Result{
-			Data: "Success update data",
+			Data:  "Success update data",
+			Count: res.MatchedCount,
+		}
+	}()
+
+	return output
+}
+
+// UpdateMany sets the document fields on every record matching the filter, Count is the number of matched records
+func (m MongoDBLogger) UpdateMany(payload UpdateOne, ctx context.Context) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		start := time.Now()
+
+		collection := m.mongoClient.Database(m.dbName).Collection(payload.CollectionName)
+
+		pByte, err := bson.Marshal(payload.Document)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb"),
+			}
+			return
+		}
+
+		var update bson.M
+		err = bson.Unmarshal(pByte, &update)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb"),
+			}
+			return
+		}
+
+		doc := bson.D{{Key: "$set", Value: update}}
+		res, err := collection.UpdateMany(ctx, payload.Filter, doc)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb connection"),
+			}
+			return
+		}
+
+		finish := time.Now()
+
+		if finish.Sub(start).Seconds() > 10 {
+			j, _ := json.Marshal(payload.Filter)
+			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
+			m.logger.Error(ctx, msg, payload)
+		}
+
+		output <- wrapper.Result{
+			Data:  "Success update data",
+			Count: res.MatchedCount,
+		}
+	}()
+
+	return output
+}
+
+// Upsert is one document of an UpsertMany together with the filter it replaces
+type Upsert struct {
+	Filter   interface{}
+	Document interface{}
+}
+
+type UpsertMany struct {
+	CollectionName string
+	Upserts        []Upsert
+}
+
+// UpsertMany sets every document on the record matching its filter in one transaction, either all of
+// them are saved or none. Count is the number of matched and inserted records.
+func (m MongoDBLogger) UpsertMany(payload UpsertMany, ctx context.Context) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		start := time.Now()
+
+		collection := m.mongoClient.Database(m.dbName).Collection(payload.CollectionName)
+		models := make([]mongo.WriteModel, 0, len(payload.Upserts))
+		for _, upsert := range payload.Upserts {
+			pByte, err := bson.Marshal(upsert.Document)
+			if err != nil {
+				msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
+				m.logger.Error(ctx, msg, payload)
+				output <- wrapper.Result{
+					Error: errors.InternalServerError("Error mongodb"),
+				}
+				return
+			}
+			var update bson.M
+			if err := bson.Unmarshal(pByte, &update); err != nil {
+				msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
+				m.logger.Error(ctx, msg, payload)
+				output <- wrapper.Result{
+					Error: errors.InternalServerError("Error mongodb"),
+				}
+				return
+			}
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(upsert.Filter).
+				SetUpdate(bson.D{{Key: "$set", Value: update}}).
+				SetUpsert(true))
+		}
+
+		session, err := m.mongoClient.StartSession()
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Session : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb session"),
+			}
+			return
+		}
+		defer session.EndSession(context.Background())
+
+		txnOpts := options.Transaction().SetWriteConcern(writeconcern.Majority()).SetReadConcern(readconcern.Snapshot())
+		res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+			return collection.BulkWrite(sessCtx, models)
+		}, txnOpts)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Transaction : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb transaction"),
+			}
+			return
+		}
+
+		finish := time.Now()
+
+		if finish.Sub(start).Seconds() > 10 {
+			msg := fmt.Sprintf("slow query: %v second, bulk upsert of %d documents", finish.Sub(start).Seconds(), len(models))
+			m.logger.Error(ctx, msg, payload.CollectionName)
+		}
+
+		bulk, _ := res.(*mongo.BulkWriteResult)
+		var count int64
+		if bulk != nil {
+			count = bulk.MatchedCount + bulk.UpsertedCount
+		}
+		output <- wrapper.Result{
+			Data:  "Success upsert data",
+			Count: count,
+		}
+	}()
+
+	return output
+}
+
+type DeleteMany struct {
+	CollectionName string
+	Filter         interface{}
+}
+
+// DeleteMany removes every record matching the filter, Count is the number of deleted records
+func (m MongoDBLogger) DeleteMany(payload DeleteMany, ctx context.Context) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+		start := time.Now()
+
+		collection := m.mongoClient.Database(m.dbName).Collection(payload.CollectionName)
+		res, err := collection.DeleteMany(ctx, payload.Filter)
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb connection"),
+			}
+			return
+		}
+
+		finish := time.Now()
+
+		if finish.Sub(start).Seconds() > 10 {
+			j, _ := json.Marshal(payload.Filter)
+			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
+			m.logger.Error(ctx, msg, payload)
+		}
+
+		output <- wrapper.Result{
+			Data:  "Success delete data",
+			Count: res.DeletedCount,
 		}
 	}()
 
@@ -436,19 +746,21 @@ func (m MongoDBLogger) Aggregate(payload Aggregate, ctx context.Context) <-chan
 
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb connection"),
 			}
+			return
 		}
 		defer cursor.Close(ctx)
 
 		if err := cursor.All(ctx, payload.Result); err != nil {
 			msg := "cannot unmarshal result"
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
-				Error: errors.InternalServerError(msg),
+				Error: errors.Wrap(err, msg),
 			}
+			return
 		}
 		output <- wrapper.Result{
 			Data: payload.Result,
@@ -459,9 +771,49 @@ func (m MongoDBLogger) Aggregate(payload Aggregate, ctx context.Context) <-chan
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
+		}
+	}()
+	return output
+}
+
+type CreateIndex struct {
+	CollectionName string
+	Keys           interface{}
+	Name           string
+	Unique         bool
+}
+
+// CreateIndex is idempotent, an index that already exists with the same keys and name is left as is
+func (m MongoDBLogger) CreateIndex(payload CreateIndex, ctx context.Context) <-chan wrapper.Result {
+	output := make(chan wrapper.Result)
+
+	go func() {
+		defer close(output)
+
+		collection := m.mongoClient.Database(m.dbName).Collection(payload.CollectionName)
+		indexOptions := options.Index().SetName(payload.Name)
+		if payload.Unique {
+			indexOptions.SetUnique(true)
+		}
+		name, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    payload.Keys,
+			Options: indexOptions,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("Error Mongodb Connection : %s", err.Error())
+			m.logger.Error(ctx, msg, payload)
+			output <- wrapper.Result{
+				Error: errors.InternalServerError("Error mongodb connection"),
+			}
+			return
+		}
+
+		output <- wrapper.Result{
+			Data: name,
 		}
 	}()
+
 	return output
 }
 
@@ -488,7 +840,7 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 		pByte, err := bson.Marshal(payload.Update)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb connection"),
 			}
@@ -498,7 +850,7 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 		err = bson.Unmarshal(pByte, &update)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb connection"),
 			}
@@ -511,13 +863,13 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 			res := collection.FindOneAndUpdate(ctx, payload.Filter, update, opts)
 			if res.Err() != nil {
 				msg := fmt.Sprintf("Error Mongodb: %s", err.Error())
-				m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+				m.logger.Error(ctx, msg, payload)
 				return nil, errors.InternalServerError("Error mongodb connection")
 			}
 
 			if err := res.Decode(payload.Result); err != nil {
 				msg := "cannot unmarshal result: " + err.Error()
-				m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+				m.logger.Error(ctx, msg, payload)
 				return nil, errors.InternalServerError("Error mongodb connection")
 			}
 			return payload.Result, nil
@@ -526,24 +878,21 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 		session, err := m.mongoClient.StartSession()
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Session : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb session"),
 			}
 		}
 		defer session.EndSession(context.Background())
 
-		result, err := session.WithTransaction(ctx, callback, txnOpts)
+		_, err = session.WithTransaction(ctx, callback, txnOpts)
 		if err != nil {
 			msg := fmt.Sprintf("Error Mongodb Transaction : %s", err.Error())
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 			output <- wrapper.Result{
 				Error: errors.InternalServerError("Error mongodb transaction"),
 			}
 		}
-		rs, _ := json.Marshal(result)
-		fmt.Println("-----Result FindOneAndUpdate------")
-		fmt.Println(string(rs))
 
 		output <- wrapper.Result{
 			Data: payload.Result,
@@ -554,7 +903,7 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 		if finish.Sub(start).Seconds() > 10 {
 			j, _ := json.Marshal(payload.Filter)
 			msg := fmt.Sprintf("slow query: %v second, query: %s", finish.Sub(start).Seconds(), string(j))
-			m.logger.Error(ctx, msg, fmt.Sprintf("%+v", payload))
+			m.logger.Error(ctx, msg, payload)
 		}
 	}()
 
@@ -564,12 +913,17 @@ func (m MongoDBLogger) FindOneAndUpdate(payload FindOneAndUpdate, rd options.Ret
 // Collections is mongodb's collection of function
 type Collections interface {
 	FindAllData(payload FindAllData, ctx context.Context) <-chan wrapper.Result
+	FindByCursor(payload FindByCursor, ctx context.Context) <-chan wrapper.Result
 	FindOne(payload FindOne, ctx context.Context) <-chan wrapper.Result
 	FindOneAndUpdate(payload FindOneAndUpdate, rd options.ReturnDocument, ctx context.Context) <-chan wrapper.Result
 	CountData(payload CountData, ctx context.Context) <-chan wrapper.Result
 	UpsertOne(payload UpdateOne, ctx context.Context) <-chan wrapper.Result
 	InsertOne(payload InsertOne, ctx context.Context) <-chan wrapper.Result
 	UpdateOne(payload UpdateOne, ctx context.Context) <-chan wrapper.Result
+	UpdateMany(payload UpdateOne, ctx context.Context) <-chan wrapper.Result
+	UpsertMany(payload UpsertMany, ctx context.Context) <-chan wrapper.Result
+	DeleteMany(payload DeleteMany, ctx context.Context) <-chan wrapper.Result
 	Aggregate(payload Aggregate, ctx context.Context) <-chan wrapper.Result
+	CreateIndex(payload CreateIndex, ctx context.Context) <-chan wrapper.Result
 	Close(ctx context.Context) error
 }
@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Definition is a typed error of the catalog. Code is the business code clients branch on, it is never
+// reused for another meaning, Key names the message in the translations.
+type Definition struct {
+	Code       int    `json:"code"`
+	ErrorCode  string `json:"errorCode"`
+	HttpStatus int    `json:"httpStatus"`
+	Message    string `json:"message"`
+	Key        string `json:"key"`
+}
+
+// New is the error of the definition with its default message
+func (d Definition) New() error {
+	err := newError(d.HttpStatus, d.Message)
+	err.code = d.Code
+	err.errorCode = d.ErrorCode
+	err.key = d.Key
+	return err
+}
+
+// Is reports whether err, or an error it wraps, is an error of the definition
+func (d Definition) Is(err error) bool {
+	errString, ok := From(err)
+	return ok && errString.code == d.Code && errString.ErrorCode() == d.ErrorCode
+}
+
+// WithFieldErrors is the error of the definition listing the failed rules of the request fields
+func (d Definition) WithFieldErrors(fieldErrors []FieldError) error {
+	err := InvalidFields(d.Message, fieldErrors).(*ErrorString)
+	err.code = d.Code
+	err.status = d.HttpStatus
+	err.severity = severityOf(d.HttpStatus)
+	err.errorCode = d.ErrorCode
+	err.key = d.Key
+	return err
+}
+
+var (
+	EmailInvalid             = Definition{Code: 4001, ErrorCode: "EMAIL_INVALID", HttpStatus: http.StatusBadRequest, Message: "Incorrect email format", Key: "email.invalid"}
+	EmailBlacklisted         = Definition{Code: 4002, ErrorCode: "EMAIL_BLACKLISTED", HttpStatus: http.StatusBadRequest, Message: "Email blacklist", Key: "email.blacklisted"}
+	OtpMismatch              = Definition{Code: 4003, ErrorCode: "OTP_MISMATCH", HttpStatus: http.StatusBadRequest, Message: "Otp not match", Key: "otp.mismatch"}
+	PasswordWeak             = Definition{Code: 4004, ErrorCode: "PASSWORD_WEAK", HttpStatus: http.StatusBadRequest, Message: "Password not criteria", Key: "password.weak"}
+	TermsNotCurrent          = Definition{Code: 4005, ErrorCode: "TERMS_NOT_CURRENT", HttpStatus: http.StatusBadRequest, Message: "Terms of service version is not current", Key: "terms.not_current"}
+	AvatarUnsupportedType    = Definition{Code: 4006, ErrorCode: "AVATAR_UNSUPPORTED_TYPE", HttpStatus: http.StatusUnsupportedMediaType, Message: "Avatar must be a JPEG, PNG or GIF image", Key: "avatar.unsupported_type"}
+	LoginTooManyAttempts     = Definition{Code: 4007, ErrorCode: "LOGIN_TOO_MANY_ATTEMPTS", HttpStatus: http.StatusBadRequest, Message: "You have too many attempts, please wait 10 minutes", Key: "login.too_many_attempts"}
+	TermsNotAccepted         = Definition{Code: 4008, ErrorCode: "TERMS_NOT_ACCEPTED", HttpStatus: http.StatusForbidden, Message: "Updated terms of service must be accepted", Key: "terms.not_accepted"}
+	PrivacyPolicyNotCurrent  = Definition{Code: 4009, ErrorCode: "PRIVACY_POLICY_NOT_CURRENT", HttpStatus: http.StatusBadRequest, Message: "Privacy policy version is not current", Key: "privacy_policy.not_current"}
+	AvatarTooLarge           = Definition{Code: 4010, ErrorCode: "AVATAR_TOO_LARGE", HttpStatus: http.StatusRequestEntityTooLarge, Message: "Avatar must be at most 5 MB", Key: "avatar.too_large"}
+	CountryNotFound          = Definition{Code: 4011, ErrorCode: "COUNTRY_NOT_FOUND", HttpStatus: http.StatusNotFound, Message: "Country not found", Key: "country.not_found"}
+	CountryDeactivated       = Definition{Code: 4012, ErrorCode: "COUNTRY_DEACTIVATED", HttpStatus: http.StatusNotFound, Message: "Country is deactivated", Key: "country.deactivated"}
+	SubdistrictNotFound      = Definition{Code: 4013, ErrorCode: "SUBDISTRICT_NOT_FOUND", HttpStatus: http.StatusNotFound, Message: "Subdistrict not found", Key: "subdistrict.not_found"}
+	SubdistrictDeactivated   = Definition{Code: 4014, ErrorCode: "SUBDISTRICT_DEACTIVATED", HttpStatus: http.StatusNotFound, Message: "Subdistrict is deactivated", Key: "subdistrict.deactivated"}
+	RequestValidationFailure = Definition{Code: http.StatusBadRequest, ErrorCode: ErrorCodeValidationFailed, HttpStatus: http.StatusBadRequest, Message: "Request is not valid", Key: "request.invalid"}
+)
+
+// catalog is every definition in code order, a new definition takes the next free code
+var catalog = []Definition{
+	RequestValidationFailure,
+	EmailInvalid,
+	EmailBlacklisted,
+	OtpMismatch,
+	PasswordWeak,
+	TermsNotCurrent,
+	AvatarUnsupportedType,
+	LoginTooManyAttempts,
+	TermsNotAccepted,
+	PrivacyPolicyNotCurrent,
+	AvatarTooLarge,
+	CountryNotFound,
+	CountryDeactivated,
+	SubdistrictNotFound,
+	SubdistrictDeactivated,
+}
+
+// DefaultLanguage is the language of the default messages
+const DefaultLanguage = "en"
+
+// translations holds the messages of every other supported language by translation key
+var translations = map[string]map[string]string{
+	"id": {
+		"email.invalid":              "Format email salah",
+		"email.blacklisted":          "Email tidak diizinkan",
+		"otp.mismatch":               "Kode OTP tidak cocok",
+		"password.weak":              "Password tidak memenuhi kriteria",
+		"terms.not_current":          "Versi syarat dan ketentuan sudah tidak berlaku",
+		"avatar.unsupported_type":    "Avatar harus berupa gambar JPEG, PNG atau GIF",
+		"login.too_many_attempts":    "Terlalu banyak percobaan, silakan tunggu 10 menit",
+		"terms.not_accepted":         "Syarat dan ketentuan terbaru harus disetujui",
+		"privacy_policy.not_current": "Versi kebijakan privasi sudah tidak berlaku",
+		"avatar.too_large":           "Ukuran avatar maksimal 5 MB",
+		"country.not_found":          "Negara tidak ditemukan",
+		"country.deactivated":        "Negara sudah dinonaktifkan",
+		"subdistrict.not_found":      "Kelurahan tidak ditemukan",
+		"subdistrict.deactivated":    "Kelurahan sudah dinonaktifkan",
+		"request.invalid":            "Permintaan tidak valid",
+	},
+}
+
+// CatalogEntry is a definition with its message in every supported language
+type CatalogEntry struct {
+	Definition
+	Messages map[string]string `json:"messages"`
+}
+
+// Catalog lists every definition in code order for the clients mapping codes to their own copy
+func Catalog() []CatalogEntry {
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, definition := range catalog {
+		messages := map[string]string{DefaultLanguage: definition.Message}
+		for language, messageByKey := range translations {
+			if message, ok := messageByKey[definition.Key]; ok {
+				messages[language] = message
+			}
+		}
+		entries = append(entries, CatalogEntry{Definition: definition, Messages: messages})
+	}
+	return entries
+}
+
+// Localize is the client message of err in the first of languages it is translated to, with the language
+// used. A regional tag such as id-id matches its base language. Errors outside the catalog keep their message.
+func Localize(err error, languages []string) (string, string) {
+	errString, ok := From(err)
+	if !ok {
+		return err.Error(), ""
+	}
+	if errString.key == "" {
+		return errString.message, ""
+	}
+	for _, language := range languages {
+		base := strings.SplitN(language, "-", 2)[0]
+		if base == DefaultLanguage {
+			return errString.message, DefaultLanguage
+		}
+		if message, ok := translations[base][errString.key]; ok {
+			return message, base
+		}
+	}
+	return errString.message, DefaultLanguage
+}
@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"net/http"
+	"testing"
+	"user-service/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogUnique(t *testing.T) {
+	codes := map[int]bool{}
+	errorCodes := map[string]bool{}
+	for _, entry := range errors.Catalog() {
+		// Assertions
+		assert.False(t, codes[entry.Code], "code %d is reused", entry.Code)
+		assert.False(t, errorCodes[entry.ErrorCode], "error code %s is reused", entry.ErrorCode)
+		assert.NotEmpty(t, entry.Messages["id"], "%s has no Indonesian message", entry.Key)
+		assert.Equal(t, entry.Message, entry.Messages["en"])
+		codes[entry.Code] = true
+		errorCodes[entry.ErrorCode] = true
+	}
+}
+
+func TestDefinitionNew(t *testing.T) {
+	// Call the function under test
+	err := errors.TermsNotAccepted.New()
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.Equal(t, "Updated terms of service must be accepted", err.Error())
+	assert.Equal(t, 4008, errString.Code())
+	assert.Equal(t, http.StatusForbidden, errString.HttpCode())
+	assert.Equal(t, "TERMS_NOT_ACCEPTED", errString.ErrorCode())
+}
+
+func TestDefinitionWithFieldErrors(t *testing.T) {
+	// Call the function under test
+	err := errors.RequestValidationFailure.WithFieldErrors([]errors.FieldError{{Field: "email", Rule: "required", Message: "is required"}})
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.Equal(t, "Request is not valid", err.Error())
+	assert.Equal(t, errors.ErrorCodeValidationFailed, errString.ErrorCode())
+	assert.Equal(t, map[string]string{"email": "is required"}, errString.Fields())
+}
+
+func TestLocalize(t *testing.T) {
+	err := errors.WithFields(errors.CountryNotFound.New(), map[string]string{"countryId": "not found"})
+
+	message, language := errors.Localize(err, []string{"fr", "id-id", "en"})
+	assert.Equal(t, "Negara tidak ditemukan", message)
+	assert.Equal(t, "id", language)
+
+	message, language = errors.Localize(err, []string{"en-gb", "id"})
+	assert.Equal(t, "Country not found", message)
+	assert.Equal(t, "en", language)
+
+	message, language = errors.Localize(err, nil)
+	assert.Equal(t, "Country not found", message)
+	assert.Equal(t, "en", language)
+}
+
+func TestLocalizeOutsideCatalog(t *testing.T) {
+	// Call the function under test
+	message, language := errors.Localize(errors.NotFound("User not found"), []string{"id"})
+
+	// Assertions
+	assert.Equal(t, "User not found", message)
+	assert.Empty(t, language)
+}
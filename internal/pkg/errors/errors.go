@@ -1,97 +1,310 @@
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
+// ErrorCodeValidationFailed is the error code of a request rejected for one or more invalid fields
+const ErrorCodeValidationFailed = "VALIDATION_FAILED"
+
+// Severity is the level an error is logged at once it reaches a response
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// FieldError is one rule a request field failed, Field is named as the client sent it
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ErrorString is an error with everything a response needs: the HTTP status, the business code clients
+// branch on, equal to the status for errors without one, and the severity it is logged at. The message is
+// safe to show to clients, the cause is only logged.
 type ErrorString struct {
-	code     int
-	message  string
-	httpCode int
+	code        int
+	status      int
+	message     string
+	severity    Severity
+	cause       error
+	fields      map[string]string
+	errorCode   string
+	fieldErrors []FieldError
+	key         string
+}
+
+func newError(status int, msg string) *ErrorString {
+	return &ErrorString{
+		code:     status,
+		status:   status,
+		message:  msg,
+		severity: severityOf(status),
+	}
 }
 
+// severityOf logs server failures as errors, refused credentials and throttling as warnings, and any other
+// client error as information
+func severityOf(status int) Severity {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return SeverityError
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		return SeverityWarn
+	default:
+		return SeverityInfo
+	}
+}
+
+// Code is the business code of the error, the HTTP status for errors without one
 func (e ErrorString) Code() int {
 	return e.code
 }
 
+// Error is the message followed by the cause chain, for logs
 func (e ErrorString) Error() string {
-	return e.message
+	if e.cause == nil {
+		return e.message
+	}
+	return e.message + ": " + e.cause.Error()
 }
 
+// Message is the message shown to clients, without the cause
 func (e ErrorString) Message() string {
 	return e.message
 }
 
+// Status is the HTTP status of the response carrying the error
+func (e ErrorString) Status() int {
+	return e.status
+}
+
+// HttpCode is Status, kept for the callers written before every error carried its status
 func (e ErrorString) HttpCode() int {
-	return e.httpCode
+	return e.status
+}
+
+// Severity is the level the error is logged at
+func (e ErrorString) Severity() Severity {
+	return e.severity
+}
+
+// Unwrap is the cause of the error, so Is and As walk past it
+func (e ErrorString) Unwrap() error {
+	return e.cause
+}
+
+// Is matches another error of the package with the same status and codes, whatever its message, so a
+// catalog error is recognised with Is(err, errors.EmailInvalid.New())
+func (e ErrorString) Is(target error) bool {
+	other, ok := target.(*ErrorString)
+	return ok && e.code == other.code && e.status == other.status && e.ErrorCode() == other.ErrorCode()
+}
+
+// Fields returns the problem of each invalid request field, keyed by field name
+func (e ErrorString) Fields() map[string]string {
+	return e.fields
+}
+
+// ErrorCode is the stable machine-readable code of the error, clients branch on it instead of the message.
+// Errors without their own code are named after their status, a custom code becomes ERROR_<code>.
+func (e ErrorString) ErrorCode() string {
+	if e.errorCode != "" {
+		return e.errorCode
+	}
+	if e.code == e.status {
+		if text := http.StatusText(e.status); text != "" {
+			return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+		}
+	}
+	return fmt.Sprintf("ERROR_%d", e.code)
+}
+
+// FieldErrors lists the failed rules of the request fields sorted by field, problems attached as a plain
+// field map are reported under the rule invalid
+func (e ErrorString) FieldErrors() []FieldError {
+	if e.fieldErrors != nil {
+		return e.fieldErrors
+	}
+	if len(e.fields) == 0 {
+		return nil
+	}
+	fieldErrors := make([]FieldError, 0, len(e.fields))
+	for field, message := range e.fields {
+		fieldErrors = append(fieldErrors, FieldError{Field: field, Rule: "invalid", Message: message})
+	}
+	sort.Slice(fieldErrors, func(i, j int) bool {
+		return fieldErrors[i].Field < fieldErrors[j].Field
+	})
+	return fieldErrors
+}
+
+// Is reports whether any error in the chain of err matches target, as the standard errors.Is
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in the chain of err that matches target, as the standard errors.As
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+// From finds the first error of the package in the chain of err, wrapped with %w or Wrap or not at all
+func From(err error) (*ErrorString, bool) {
+	var errString *ErrorString
+	if err == nil || !stderrors.As(err, &errString) {
+		return nil, false
+	}
+	return errString, true
+}
+
+// Wrap adds context to cause for the logs. A cause already carrying an error of the package keeps its
+// status, codes and client message, any other cause becomes an internal server error with msg.
+func Wrap(cause error, msg string) error {
+	if cause == nil {
+		return nil
+	}
+	if _, ok := From(cause); ok {
+		return fmt.Errorf("%s: %w", msg, cause)
+	}
+	err := newError(http.StatusInternalServerError, msg)
+	err.cause = cause
+	return err
+}
+
+// WithCause records the cause of err for the logs, other errors are returned as is
+func WithCause(err error, cause error) error {
+	errString, ok := err.(*ErrorString)
+	if !ok {
+		return err
+	}
+	withCause := *errString
+	withCause.cause = cause
+	return &withCause
+}
+
+// WithSeverity changes the level err is logged at, other errors are returned as is
+func WithSeverity(err error, severity Severity) error {
+	errString, ok := err.(*ErrorString)
+	if !ok {
+		return err
+	}
+	withSeverity := *errString
+	withSeverity.severity = severity
+	return &withSeverity
 }
 
 // BadRequest will throw if the given request-body or params is not valid
 func BadRequest(msg string) error {
-	return &ErrorString{
-		code:    http.StatusBadRequest,
-		message: msg,
-	}
+	return newError(http.StatusBadRequest, msg)
 }
 
 // NotFound will throw if the requested item is not exists
 func NotFound(msg string) error {
-	return &ErrorString{
-		code:    http.StatusNotFound,
-		message: msg,
-	}
+	return newError(http.StatusNotFound, msg)
 }
 
 // Conflict will throw if the current action already exists
 func Conflict(msg string) error {
-	return &ErrorString{
-		code:    http.StatusConflict,
-		message: msg,
-	}
+	return newError(http.StatusConflict, msg)
 }
 
 // InternalServerError will throw if any the Internal Server Error happen,
 // Database, Third Party etc.
 func InternalServerError(msg string) error {
-	return &ErrorString{
-		code:    http.StatusInternalServerError,
-		message: msg,
-	}
+	return newError(http.StatusInternalServerError, msg)
 }
 
 func UnauthorizedError(msg string) error {
-	return &ErrorString{
-		code:    http.StatusUnauthorized,
-		message: msg,
-	}
+	return newError(http.StatusUnauthorized, msg)
 }
 
 func ForbiddenError(msg string) error {
-	return &ErrorString{
-		code:    http.StatusForbidden,
-		message: msg,
-	}
+	return newError(http.StatusForbidden, msg)
 }
 
+// CustomError will throw a business error, code is the business code and codeHttp the HTTP status. A
+// missing or unknown status falls back to 500.
 func CustomError(msg string, code int, codeHttp int) error {
-	return &ErrorString{
-		code:     code,
-		message:  msg,
-		httpCode: codeHttp,
+	if http.StatusText(codeHttp) == "" {
+		codeHttp = http.StatusInternalServerError
 	}
+	err := newError(codeHttp, msg)
+	err.code = code
+	return err
 }
 
 // TooManyRequest will throw if request created very frequently
 func TooManyRequest(msg string) error {
-	return &ErrorString{
-		code:    http.StatusTooManyRequests,
-		message: msg,
-	}
+	return newError(http.StatusTooManyRequests, msg)
+}
+
+// PreconditionFailed will throw if the If-Match condition of a request does not hold
+func PreconditionFailed(msg string) error {
+	return newError(http.StatusPreconditionFailed, msg)
+}
+
+// PreconditionRequired will throw if a conditional request is sent without its precondition
+func PreconditionRequired(msg string) error {
+	return newError(http.StatusPreconditionRequired, msg)
 }
 
 func UnprocessableEntity(msg string) error {
-	return &ErrorString{
-		code:    http.StatusUnprocessableEntity,
-		message: msg,
+	return newError(http.StatusUnprocessableEntity, msg)
+}
+
+// ValidationError will throw if one or more request fields are not valid,
+// fields maps each invalid field to its problem
+func ValidationError(msg string, fields map[string]string) error {
+	err := newError(http.StatusBadRequest, msg)
+	err.fields = fields
+	err.errorCode = ErrorCodeValidationFailed
+	return err
+}
+
+// InvalidFields will throw if the request fails validation rules, fieldErrors keeps every failed rule
+// while Fields holds the first problem of each field for the legacy response
+func InvalidFields(msg string, fieldErrors []FieldError) error {
+	fields := make(map[string]string, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		if _, ok := fields[fieldError.Field]; !ok {
+			fields[fieldError.Field] = fieldError.Message
+		}
+	}
+	err := newError(http.StatusBadRequest, msg)
+	err.fields = fields
+	err.errorCode = ErrorCodeValidationFailed
+	err.fieldErrors = fieldErrors
+	return err
+}
+
+// WithFields attaches field-level problems to err, other errors are returned as is
+func WithFields(err error, fields map[string]string) error {
+	errString, ok := err.(*ErrorString)
+	if !ok {
+		return err
+	}
+	withFields := *errString
+	withFields.fields = fields
+	withFields.fieldErrors = nil
+	return &withFields
+}
+
+// WithErrorCode sets the machine-readable code of err, other errors are returned as is
+func WithErrorCode(err error, errorCode string) error {
+	errString, ok := err.(*ErrorString)
+	if !ok {
+		return err
 	}
+	withErrorCode := *errString
+	withErrorCode.errorCode = errorCode
+	return &withErrorCode
 }
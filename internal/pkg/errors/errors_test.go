@@ -1,6 +1,7 @@
 package errors_test
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 	"user-service/internal/pkg/errors"
@@ -115,3 +116,204 @@ func TestTooManyRequestError(t *testing.T) {
 	assert.Equal(t, "Too many request error message", err.Error())
 	assert.Equal(t, "Too many request error message", errString.Message())
 }
+
+func TestPreconditionFailedError(t *testing.T) {
+	// Call the function under test
+	err := errors.PreconditionFailed("Precondition failed error message")
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, errString.Code())
+	assert.Equal(t, "Precondition failed error message", err.Error())
+	assert.Equal(t, "Precondition failed error message", errString.Message())
+}
+
+func TestPreconditionRequiredError(t *testing.T) {
+	// Call the function under test
+	err := errors.PreconditionRequired("Precondition required error message")
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, errString.Code())
+	assert.Equal(t, "Precondition required error message", err.Error())
+	assert.Equal(t, "Precondition required error message", errString.Message())
+}
+
+func TestValidationError(t *testing.T) {
+	// Call the function under test
+	err := errors.ValidationError("Address hierarchy is not valid", map[string]string{"cityId": "does not match the subdistrict"})
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.NotNil(t, err)
+	assert.Equal(t, "Address hierarchy is not valid", err.Error())
+	assert.Equal(t, http.StatusBadRequest, errString.Code())
+	assert.Equal(t, map[string]string{"cityId": "does not match the subdistrict"}, errString.Fields())
+}
+
+func TestWithFields(t *testing.T) {
+	// Call the function under test
+	original := errors.CustomError("Subdistrict not found", 4002, http.StatusNotFound)
+	err := errors.WithFields(original, map[string]string{"subdistrictId": "not found"})
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.Equal(t, 4002, errString.Code())
+	assert.Equal(t, http.StatusNotFound, errString.HttpCode())
+	assert.Equal(t, map[string]string{"subdistrictId": "not found"}, errString.Fields())
+	assert.Nil(t, original.(*errors.ErrorString).Fields())
+}
+
+func TestWithFieldsForeignError(t *testing.T) {
+	// Call the function under test
+	original := http.ErrNoCookie
+	err := errors.WithFields(original, map[string]string{"cookie": "missing"})
+
+	// Assertions
+	assert.Equal(t, original, err)
+}
+
+func TestErrorCode(t *testing.T) {
+	// Assertions
+	assert.Equal(t, "NOT_FOUND", errors.NotFound("Not found").(*errors.ErrorString).ErrorCode())
+	assert.Equal(t, "TOO_MANY_REQUESTS", errors.TooManyRequest("Slow down").(*errors.ErrorString).ErrorCode())
+	assert.Equal(t, "ERROR_4001", errors.CustomError("Email exists", 4001, http.StatusBadRequest).(*errors.ErrorString).ErrorCode())
+	assert.Equal(t, errors.ErrorCodeValidationFailed, errors.ValidationError("Not valid", nil).(*errors.ErrorString).ErrorCode())
+}
+
+func TestWithErrorCode(t *testing.T) {
+	// Call the function under test
+	original := errors.BadRequest("Cursor expired")
+	err := errors.WithErrorCode(original, "CURSOR_EXPIRED")
+
+	// Assertions
+	assert.Equal(t, "CURSOR_EXPIRED", err.(*errors.ErrorString).ErrorCode())
+	assert.Equal(t, "BAD_REQUEST", original.(*errors.ErrorString).ErrorCode())
+	assert.Equal(t, http.ErrNoCookie, errors.WithErrorCode(http.ErrNoCookie, "CURSOR_EXPIRED"))
+}
+
+func TestInvalidFields(t *testing.T) {
+	// Call the function under test
+	err := errors.InvalidFields("Request is not valid", []errors.FieldError{
+		{Field: "email", Rule: "required", Message: "is required"},
+		{Field: "email", Rule: "email", Message: "must be a valid email"},
+	})
+
+	errString, _ := err.(*errors.ErrorString)
+	// Assertions
+	assert.Equal(t, http.StatusBadRequest, errString.Code())
+	assert.Equal(t, errors.ErrorCodeValidationFailed, errString.ErrorCode())
+	assert.Len(t, errString.FieldErrors(), 2)
+	assert.Equal(t, map[string]string{"email": "is required"}, errString.Fields())
+}
+
+func TestFieldErrorsFromFields(t *testing.T) {
+	// Call the function under test
+	err := errors.ValidationError("Address hierarchy is not valid", map[string]string{"postalCode": "does not match", "cityId": "not found"})
+
+	// Assertions
+	assert.Equal(t, []errors.FieldError{
+		{Field: "cityId", Rule: "invalid", Message: "not found"},
+		{Field: "postalCode", Rule: "invalid", Message: "does not match"},
+	}, err.(*errors.ErrorString).FieldErrors())
+}
+
+func TestConstructorMatrix(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		message   string
+		status    int
+		code      int
+		errorCode string
+		severity  errors.Severity
+	}{
+		{"BadRequest", errors.BadRequest("message"), "message", http.StatusBadRequest, http.StatusBadRequest, "BAD_REQUEST", errors.SeverityInfo},
+		{"NotFound", errors.NotFound("message"), "message", http.StatusNotFound, http.StatusNotFound, "NOT_FOUND", errors.SeverityInfo},
+		{"Conflict", errors.Conflict("message"), "message", http.StatusConflict, http.StatusConflict, "CONFLICT", errors.SeverityInfo},
+		{"InternalServerError", errors.InternalServerError("message"), "message", http.StatusInternalServerError, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", errors.SeverityError},
+		{"UnauthorizedError", errors.UnauthorizedError("message"), "message", http.StatusUnauthorized, http.StatusUnauthorized, "UNAUTHORIZED", errors.SeverityWarn},
+		{"ForbiddenError", errors.ForbiddenError("message"), "message", http.StatusForbidden, http.StatusForbidden, "FORBIDDEN", errors.SeverityWarn},
+		{"CustomError", errors.CustomError("message", 4001, http.StatusBadRequest), "message", http.StatusBadRequest, 4001, "ERROR_4001", errors.SeverityInfo},
+		{"CustomErrorWithoutStatus", errors.CustomError("message", 4001, 0), "message", http.StatusInternalServerError, 4001, "ERROR_4001", errors.SeverityError},
+		{"TooManyRequest", errors.TooManyRequest("message"), "message", http.StatusTooManyRequests, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", errors.SeverityWarn},
+		{"PreconditionFailed", errors.PreconditionFailed("message"), "message", http.StatusPreconditionFailed, http.StatusPreconditionFailed, "PRECONDITION_FAILED", errors.SeverityInfo},
+		{"PreconditionRequired", errors.PreconditionRequired("message"), "message", http.StatusPreconditionRequired, http.StatusPreconditionRequired, "PRECONDITION_REQUIRED", errors.SeverityInfo},
+		{"UnprocessableEntity", errors.UnprocessableEntity("message"), "message", http.StatusUnprocessableEntity, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", errors.SeverityInfo},
+		{"ValidationError", errors.ValidationError("message", nil), "message", http.StatusBadRequest, http.StatusBadRequest, errors.ErrorCodeValidationFailed, errors.SeverityInfo},
+		{"InvalidFields", errors.InvalidFields("message", nil), "message", http.StatusBadRequest, http.StatusBadRequest, errors.ErrorCodeValidationFailed, errors.SeverityInfo},
+		{"Definition", errors.AvatarTooLarge.New(), "Avatar must be at most 5 MB", http.StatusRequestEntityTooLarge, 4010, "AVATAR_TOO_LARGE", errors.SeverityInfo},
+		{"Wrap", errors.Wrap(http.ErrNoCookie, "message"), "message", http.StatusInternalServerError, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", errors.SeverityError},
+		{"WrappedWithFmt", fmt.Errorf("context: %w", errors.NotFound("message")), "message", http.StatusNotFound, http.StatusNotFound, "NOT_FOUND", errors.SeverityInfo},
+		{"WithSeverity", errors.WithSeverity(errors.Conflict("message"), errors.SeverityWarn), "message", http.StatusConflict, http.StatusConflict, "CONFLICT", errors.SeverityWarn},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errString, ok := errors.From(testCase.err)
+
+			// Assertions
+			assert.True(t, ok)
+			assert.Equal(t, testCase.message, errString.Message())
+			assert.Equal(t, testCase.status, errString.Status())
+			assert.Equal(t, testCase.status, errString.HttpCode())
+			assert.Equal(t, testCase.code, errString.Code())
+			assert.Equal(t, testCase.errorCode, errString.ErrorCode())
+			assert.Equal(t, testCase.severity, errString.Severity())
+		})
+	}
+}
+
+func TestWrapKeepsStatus(t *testing.T) {
+	// Call the function under test
+	cause := errors.EmailInvalid.New()
+	err := errors.Wrap(cause, "register user")
+
+	errString, ok := errors.From(err)
+	// Assertions
+	assert.True(t, ok)
+	assert.Equal(t, "register user: Incorrect email format", err.Error())
+	assert.Equal(t, "Incorrect email format", errString.Message())
+	assert.True(t, errors.Is(err, errors.EmailInvalid.New()))
+	assert.True(t, errors.EmailInvalid.Is(err))
+	assert.False(t, errors.EmailBlacklisted.Is(err))
+	assert.Nil(t, errors.Wrap(nil, "register user"))
+}
+
+func TestWrapForeignCause(t *testing.T) {
+	// Call the function under test
+	err := errors.Wrap(http.ErrNoCookie, "read session")
+
+	// Assertions
+	assert.Equal(t, "read session: http: named cookie not present", err.Error())
+	assert.Equal(t, "read session", err.(*errors.ErrorString).Message())
+	assert.True(t, errors.Is(err, http.ErrNoCookie))
+	assert.Equal(t, http.ErrNoCookie, errors.WithCause(http.ErrNoCookie, err))
+}
+
+func TestWithCause(t *testing.T) {
+	// Call the function under test
+	original := errors.NotFound("User not found")
+	err := errors.WithCause(original, http.ErrNoCookie)
+
+	// Assertions
+	assert.True(t, errors.Is(err, http.ErrNoCookie))
+	assert.True(t, errors.Is(err, errors.NotFound("Address not found")))
+	assert.False(t, errors.Is(err, errors.Conflict("User not found")))
+	assert.Nil(t, original.(*errors.ErrorString).Unwrap())
+
+	var errString *errors.ErrorString
+	assert.True(t, errors.As(fmt.Errorf("find user: %w", err), &errString))
+	assert.Equal(t, "User not found", errString.Message())
+}
+
+func TestFromForeignError(t *testing.T) {
+	// Call the function under test
+	_, ok := errors.From(http.ErrNoCookie)
+	_, okNil := errors.From(nil)
+
+	// Assertions
+	assert.False(t, ok)
+	assert.False(t, okNil)
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorCatalogCacheControl lets frontends keep the catalog for an hour, it only changes with a release
+const errorCatalogCacheControl = "public, max-age=3600"
+
+// ErrorCatalog serves every business error with its code, status and messages in each supported language
+func ErrorCatalog(log log.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, errorCatalogCacheControl)
+		return RespSuccess(c, log, errors.Catalog(), "Get error catalog success")
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
 	"math/rand"
 	"os"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"user-service/configs"
 	"user-service/internal/pkg/constants"
 )
@@ -121,6 +125,22 @@ func GeneratePassword(password string) string {
 	return signatureSig
 }
 
+// GenerateSignature signs value with the signature secret, used for expiring download links
+func GenerateSignature(value string) string {
+	secret := configs.GetConfig().SecretSignature
+	msgHashSig := hmac.New(sha256.New, []byte(secret))
+	msgHashSig.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(msgHashSig.Sum(nil))
+}
+
+// IsValidSignature never accepts a link while no signature secret is configured
+func IsValidSignature(value string, signature string) bool {
+	if configs.GetConfig().SecretSignature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(GenerateSignature(value)), []byte(signature))
+}
+
 func GenerateRandomOtp() string {
 	// Generate random otp
 	letterRunes := []rune("0123456789")
@@ -223,3 +243,135 @@ func GenerateMetaData(totalData, count int64, page, limit int64) constants.MetaD
 
 	return metaData
 }
+
+// GenerateResultMetaData describes the page held by result, a page without a page number was read by cursor
+func GenerateResultMetaData(result Result, count int64, page, limit int64) constants.MetaData {
+	if page > 0 {
+		return GenerateMetaData(result.Count, count, page, limit)
+	}
+	nextCursor, _ := result.MetaData.(string)
+	return GenerateCursorMetaData(result.Count, count, limit, nextCursor)
+}
+
+// GenerateCursorMetaData describes a cursor page, totalData is zero when the count was not asked for
+func GenerateCursorMetaData(totalData, count int64, limit int64, nextCursor string) constants.MetaData {
+	return constants.MetaData{
+		Count:      count,
+		TotalPage:  int64(math.Ceil(float64(totalData) / float64(limit))),
+		TotalData:  totalData,
+		NextCursor: nextCursor,
+	}
+}
+
+// MergePatch applies a JSON merge patch (RFC 7396) to a JSON document
+func MergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target, patchValue interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatchValue(target, patchValue))
+}
+
+func mergePatchValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatchValue(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// FormatETag renders a document version as a strong entity tag
+func FormatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// MatchETag reports whether an If-Match header value matches the entity tag
+func MatchETag(ifMatch string, etag string) bool {
+	for _, value := range strings.Split(ifMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectFields keeps only the given JSON fields of a struct, nested fields are addressed
+// with dots (addressDetail.city). Fields that the struct does not declare are returned as unknown.
+func SelectFields(data interface{}, fields []string) (map[string]interface{}, []string, error) {
+	unknown := []string{}
+	for _, field := range fields {
+		if !hasJsonField(reflect.TypeOf(data), strings.Split(field, ".")) {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, unknown, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, nil, err
+	}
+	selected := map[string]interface{}{}
+	for _, field := range fields {
+		copyJsonField(document, selected, strings.Split(field, "."))
+	}
+	return selected, nil, nil
+}
+
+func hasJsonField(t reflect.Type, path []string) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || name != path[0] {
+			continue
+		}
+		return len(path) == 1 || hasJsonField(t.Field(i).Type, path[1:])
+	}
+	return false
+}
+
+func copyJsonField(source map[string]interface{}, target map[string]interface{}, path []string) {
+	value, ok := source[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		target[path[0]] = value
+		return
+	}
+	nestedSource, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	nestedTarget, ok := target[path[0]].(map[string]interface{})
+	if !ok {
+		nestedTarget = map[string]interface{}{}
+		target[path[0]] = nestedTarget
+	}
+	copyJsonField(nestedSource, nestedTarget, path[1:])
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxLanguages bounds the languages taken from one request
+const maxLanguages = 5
+
+var languageTag = regexp.MustCompile(`^[a-z]{2,8}(-[a-z0-9]{1,8})*$`)
+
+// Languages are the languages of the caller, most preferred first: the lang query parameter then the
+// Accept-Language header by quality. Tags are lowercased, wildcards and malformed tags are dropped.
+func Languages(c *fiber.Ctx) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	candidates := make([]weighted, 0)
+	if lang := c.Query("lang"); lang != "" {
+		candidates = append(candidates, weighted{tag: lang, quality: 2})
+	}
+	for _, part := range strings.Split(c.Get(fiber.HeaderAcceptLanguage), ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			candidates = append(candidates, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	var result []string
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(candidate.tag), "_", "-"))
+		if seen[tag] || !languageTag.MatchString(tag) {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+		if len(result) == maxLanguages {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LogLevel is the minimum level the service currently logs at
+type LogLevel struct {
+	Level string `json:"level" validate:"required"`
+}
+
+// GetLogLevel serves the current minimum log level
+func GetLogLevel(log log.Logger, leveler log.Leveler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return RespSuccess(c, log, LogLevel{Level: leveler.Level()}, "Get log level success")
+	}
+}
+
+// SetLogLevel changes the minimum log level of the running service until the next restart
+func SetLogLevel(logger log.Logger, leveler log.Leveler) fiber.Handler {
+	validator := NewValidator()
+	return func(c *fiber.Ctx) error {
+		req := new(LogLevel)
+		if err := c.BodyParser(req); err != nil {
+			return RespError(c, logger, errors.BadRequest("bad request"))
+		}
+		if err := validator.Struct(req); err != nil {
+			return RespError(c, logger, ValidationError(err))
+		}
+		previous := leveler.Level()
+		if err := leveler.SetLevel(req.Level); err != nil {
+			return RespError(c, logger, errors.WithFields(errors.BadRequest(err.Error()), map[string]string{
+				"level": "must be one of debug, info, warn, error, dpanic, panic, fatal",
+			}))
+		}
+		logger.Warn(c.Context(), "Log level changed", nil, log.String("from", previous), log.String("to", leveler.Level()))
+		return RespSuccess(c, logger, LogLevel{Level: leveler.Level()}, "Set log level success")
+	}
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"user-service/internal/pkg/errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// fieldTags are the struct tags naming a request field, in the order they are looked up
+var fieldTags = []string{"json", "query", "params", "form"}
+
+// NewValidator names failing fields the way the client sent them, by their json, query, params or form
+// tag, so validation errors point at the request and not at the Go struct
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range fieldTags {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return "-"
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return ""
+	})
+	return v
+}
+
+// ValidationError turns the error of Validate.Struct into a validation error listing every failed rule,
+// any other error is a plain bad request
+func ValidationError(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.BadRequest(err.Error())
+	}
+	fieldErrors := make([]errors.FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fieldErrors = append(fieldErrors, errors.FieldError{
+			Field:   fieldPath(fieldError),
+			Rule:    fieldError.Tag(),
+			Message: ruleMessage(fieldError),
+		})
+	}
+	return errors.RequestValidationFailure.WithFieldErrors(fieldErrors)
+}
+
+// fieldPath is the namespace of the field without the request struct, nested fields keep their parents
+func fieldPath(fieldError validator.FieldError) string {
+	namespace := fieldError.Namespace()
+	if index := strings.Index(namespace, "."); index >= 0 {
+		return namespace[index+1:]
+	}
+	return namespace
+}
+
+func ruleMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+	switch fieldError.Tag() {
+	case "required", "required_if", "required_with":
+		return "is required"
+	case "excluded_with":
+		return fmt.Sprintf("must not be set together with %s", param)
+	case "min", "gte":
+		return "must be at least " + sizeOf(fieldError.Kind(), param)
+	case "max", "lte":
+		return "must be at most " + sizeOf(fieldError.Kind(), param)
+	case "gt":
+		return "must be greater than " + sizeOf(fieldError.Kind(), param)
+	case "len":
+		return "must be exactly " + sizeOf(fieldError.Kind(), param)
+	case "gtefield":
+		return fmt.Sprintf("must not be before %s", param)
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(param, " ", ", ")
+	case "numeric":
+		return "must be numeric"
+	case "alpha":
+		return "must contain letters only"
+	case "alphanum":
+		return "must contain letters and digits only"
+	case "latitude":
+		return "must be a latitude between -90 and 90"
+	case "longitude":
+		return "must be a longitude between -180 and 180"
+	case "bcp47_language_tag":
+		return "must be a language tag"
+	case "timezone":
+		return "must be an IANA time zone"
+	case "email":
+		return "must be a valid email"
+	default:
+		return "is not valid"
+	}
+}
+
+// sizeOf reads the param of a size rule as characters, items or a plain value depending on the field kind
+func sizeOf(kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		return param + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return param + " items"
+	default:
+		return param
+	}
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -38,6 +39,7 @@ type Meta struct {
 	ContentLength int64     `json:"content_length"`
 	Date          time.Time `json:"date"`
 	Ip            string    `json:"ip"`
+	Error         string    `json:"error,omitempty"`
 }
 
 type MetaResponse struct {
@@ -45,14 +47,73 @@ type MetaResponse struct {
 	Message string `json:"message"`
 }
 
-func getErrorStatusCode(err error) int {
-	errString, ok := err.(*errors.ErrorString)
-	if ok {
-		return errString.Code()
+// ProblemContentType is the RFC 7807 media type, clients opt in to problem details by accepting it
+const ProblemContentType = "application/problem+json"
+
+// problem is an RFC 7807 problem details body, Code and Errors are extension members
+type problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail"`
+	Instance string              `json:"instance"`
+	Code     string              `json:"code"`
+	Errors   []errors.FieldError `json:"errors,omitempty"`
+	Data     interface{}         `json:"data,omitempty"`
+}
+
+// wantsProblem tells a client accepting problem details from an old client expecting the meta envelope
+func wantsProblem(c *fiber.Ctx) bool {
+	return c.Accepts(fiber.MIMEApplicationJSON, ProblemContentType) == ProblemContentType
+}
+
+// respProblem writes err as problem details
+func respProblem(c *fiber.Ctx, err *errors.ErrorString, message string, data interface{}) error {
+	status := err.Status()
+	body := problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   message,
+		Instance: c.OriginalURL(),
+		Code:     err.ErrorCode(),
+		Errors:   err.FieldErrors(),
+		Data:     data,
+	}
+	encoded, marshalErr := json.Marshal(body)
+	if marshalErr != nil {
+		return marshalErr
 	}
+	c.Set(fiber.HeaderContentType, ProblemContentType)
+	return c.Status(status).Send(encoded)
+}
 
-	// default http status code
-	return http.StatusInternalServerError
+// localizedMessage is the message of err in the language of the caller, catalog errors are translated
+func localizedMessage(c *fiber.Ctx, err error) string {
+	message, language := errors.Localize(err, Languages(c))
+	if language != "" {
+		c.Set(fiber.HeaderContentLanguage, language)
+		c.Vary(fiber.HeaderAcceptLanguage)
+	}
+	return message
+}
+
+// describeError is the first error of the errors package in the chain of err. Any other error is an
+// internal server error, its message stays in the logs.
+func describeError(err error) *errors.ErrorString {
+	if errString, ok := errors.From(err); ok {
+		return errString
+	}
+	described, _ := errors.From(errors.Wrap(err, http.StatusText(http.StatusInternalServerError)))
+	return described
+}
+
+// errorData carries the field-level problems of an error in the response body
+func errorData(err *errors.ErrorString) interface{} {
+	if len(err.Fields()) == 0 {
+		return nil
+	}
+	return fiber.Map{"errors": err.Fields()}
 }
 
 func RespSuccess(c *fiber.Ctx, log log.Logger, data interface{}, message string) error {
@@ -82,29 +143,7 @@ func RespSuccess(c *fiber.Ctx, log log.Logger, data interface{}, message string)
 }
 
 func RespError(c *fiber.Ctx, log log.Logger, err error) error {
-	ip := c.Get("X-Forwarded-For")
-	if ip == "" {
-		// If X-Forwarded-For is not present, use the default IP
-		ip = c.IP()
-	}
-	meta := Meta{
-		Date:          time.Now(),
-		Url:           c.Path(),
-		Method:        c.Method(),
-		Code:          fmt.Sprintf("%v", getErrorStatusCode(err)),
-		Ip:            ip,
-		ContentLength: int64(c.Request().Header.ContentLength()),
-	}
-
-	log.Info(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
-
-	return c.Status(getErrorStatusCode(err)).JSON(response{
-		Meta: MetaResponse{
-			Code:    getErrorStatusCode(err),
-			Message: err.Error(),
-		},
-		Data: nil,
-	})
+	return respError(c, log, err, nil, true)
 }
 
 func RespPagination(c *fiber.Ctx, log log.Logger, data interface{}, metadata constants.MetaData, message string) error {
@@ -135,32 +174,18 @@ func RespPagination(c *fiber.Ctx, log log.Logger, data interface{}, metadata con
 }
 
 func RespErrorWithData(c *fiber.Ctx, log log.Logger, data interface{}, err error) error {
-	ip := c.Get("X-Forwarded-For")
-	if ip == "" {
-		// If X-Forwarded-For is not present, use the default IP
-		ip = c.IP()
-	}
-	meta := Meta{
-		Date:          time.Now(),
-		Url:           c.Path(),
-		Method:        c.Method(),
-		Code:          fmt.Sprintf("%v", getErrorStatusCode(err)),
-		Ip:            ip,
-		ContentLength: int64(c.Request().Header.ContentLength()),
-	}
-
-	log.Info(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
-
-	return c.Status(getErrorStatusCode(err)).JSON(response{
-		Meta: MetaResponse{
-			Code:    getErrorStatusCode(err),
-			Message: err.Error(),
-		},
-		Data: data,
-	})
+	return respError(c, log, err, data, false)
 }
 
+// RespCustomError is RespError, kept for the handlers written before every error carried its own status
 func RespCustomError(c *fiber.Ctx, log log.Logger, err error) error {
+	return respError(c, log, err, nil, true)
+}
+
+// respError writes err with the status, business code and message of the first error of the errors
+// package in its chain. The audit log line carries the whole chain at the severity of the error.
+func respError(c *fiber.Ctx, log log.Logger, err error, data interface{}, withFields bool) error {
+	described := describeError(err)
 	ip := c.Get("X-Forwarded-For")
 	if ip == "" {
 		// If X-Forwarded-For is not present, use the default IP
@@ -170,27 +195,33 @@ func RespCustomError(c *fiber.Ctx, log log.Logger, err error) error {
 		Date:          time.Now(),
 		Url:           c.Path(),
 		Method:        c.Method(),
-		Code:          fmt.Sprintf("%v", getErrorStatusCode(err)),
+		Code:          fmt.Sprintf("%v", described.Status()),
 		Ip:            ip,
 		ContentLength: int64(c.Request().Header.ContentLength()),
+		Error:         err.Error(),
 	}
 
-	log.Info(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
+	switch described.Severity() {
+	case errors.SeverityError:
+		log.Error(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
+	case errors.SeverityWarn:
+		log.Warn(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
+	default:
+		log.Info(c.Context(), "audit-log", fmt.Sprintf("%+v", meta))
+	}
 
-	errString, ok := err.(*errors.ErrorString)
-	metaErrorCode := 500
-	if ok {
-		if errString.HttpCode() != 0 {
-			metaErrorCode = errString.HttpCode()
-		} else {
-			metaErrorCode = errString.Code()
-		}
+	if withFields {
+		data = errorData(described)
 	}
-	return c.Status(metaErrorCode).JSON(response{
+	message := localizedMessage(c, described)
+	if wantsProblem(c) {
+		return respProblem(c, described, message, data)
+	}
+	return c.Status(described.Status()).JSON(response{
 		Meta: MetaResponse{
-			Code:    getErrorStatusCode(err),
-			Message: err.Error(),
+			Code:    described.Code(),
+			Message: message,
 		},
-		Data: nil,
+		Data: data,
 	})
 }
@@ -0,0 +1,232 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// MaxPixels guards against decompression bombs, a decoded image takes four bytes per pixel and the
+// variants are rendered from full size copies, so it is kept to what an avatar needs
+const MaxPixels = 4096 * 4096
+
+// maxConcurrentDecodes bounds the memory of uploads arriving together, the next one waits for a slot
+const maxConcurrentDecodes = 2
+
+var decodeSlots = make(chan struct{}, maxConcurrentDecodes)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions too large")
+)
+
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Variant is a square rendition of an image
+type Variant struct {
+	Name string
+	Size int
+}
+
+type Image struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// DetectContentType sniffs the content type from the magic bytes, ignoring any client supplied type
+func DetectContentType(content []byte) (string, bool) {
+	contentType := http.DetectContentType(content)
+	return contentType, allowedContentTypes[contentType]
+}
+
+// GenerateVariants decodes the image, applies the EXIF orientation and renders every variant
+// as a center cropped JPEG. Re-encoding drops all metadata of the original, EXIF included.
+func GenerateVariants(content []byte, variants []Variant) ([]Image, error) {
+	contentType, ok := DetectContentType(content)
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+	decodeSlots <- struct{}{}
+	defer func() { <-decodeSlots }()
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Flatten onto white so transparent PNG/GIF pixels do not turn black in JPEG
+	bounds := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	if contentType == "image/jpeg" {
+		flat = applyOrientation(flat, jpegOrientation(content))
+	}
+	square := cropSquare(flat)
+
+	images := make([]Image, 0, len(variants))
+	for _, variant := range variants {
+		buf := new(bytes.Buffer)
+		if err := jpeg.Encode(buf, resize(square, variant.Size), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		images = append(images, Image{
+			Name:        variant.Name,
+			ContentType: "image/jpeg",
+			Content:     buf.Bytes(),
+		})
+	}
+	return images, nil
+}
+
+func cropSquare(src *image.RGBA) *image.RGBA {
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	side := width
+	if height < side {
+		side = height
+	}
+	offset := image.Point{X: (width - side) / 2, Y: (height - side) / 2}
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, offset, draw.Src)
+	return dst
+}
+
+// resize scales a square image by averaging the source pixels covered by each target pixel
+func resize(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// applyOrientation rotates/flips the image according to the EXIF orientation tag (1-8)
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF APP1 segment, 1 when absent
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+	offset := 2
+	for offset+4 <= len(content) {
+		if content[offset] != 0xFF {
+			return 1
+		}
+		marker := content[offset+1]
+		length := int(binary.BigEndian.Uint16(content[offset+2 : offset+4]))
+		if marker == 0xDA || length < 2 || offset+2+length > len(content) {
+			return 1
+		}
+		segment := content[offset+4 : offset+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 1
+}
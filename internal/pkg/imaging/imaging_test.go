@@ -0,0 +1,112 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"user-service/internal/pkg/imaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImagingTestSuite struct {
+	suite.Suite
+	variants []imaging.Variant
+}
+
+func (suite *ImagingTestSuite) SetupTest() {
+	suite.variants = []imaging.Variant{
+		{Name: "large", Size: 128},
+		{Name: "small", Size: 16},
+	}
+}
+
+func TestImagingTestSuite(t *testing.T) {
+	suite.Run(t, new(ImagingTestSuite))
+}
+
+func encodePng(width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 10, B: 10, A: 255})
+		}
+	}
+	buf := new(bytes.Buffer)
+	_ = png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+// encodeJpegWithOrientation builds a JPEG carrying a big endian EXIF orientation tag
+func encodeJpegWithOrientation(width, height int, orientation byte) []byte {
+	buf := new(bytes.Buffer)
+	_ = jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil)
+	content := buf.Bytes()
+
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, 0, 0, 0, 0}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+
+	result := append([]byte{}, content[:2]...)
+	result = append(result, app1...)
+	return append(result, content[2:]...)
+}
+
+func (suite *ImagingTestSuite) TestDetectContentType() {
+	contentType, ok := imaging.DetectContentType(encodePng(4, 4))
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), "image/png", contentType)
+
+	_, ok = imaging.DetectContentType([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.False(suite.T(), ok)
+}
+
+func (suite *ImagingTestSuite) TestGenerateVariants() {
+	images, err := imaging.GenerateVariants(encodePng(300, 200), suite.variants)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), images, 2)
+	for i, variant := range suite.variants {
+		assert.Equal(suite.T(), variant.Name, images[i].Name)
+		assert.Equal(suite.T(), "image/jpeg", images[i].ContentType)
+		config, format, err := image.DecodeConfig(bytes.NewReader(images[i].Content))
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), "jpeg", format)
+		assert.Equal(suite.T(), variant.Size, config.Width)
+		assert.Equal(suite.T(), variant.Size, config.Height)
+	}
+}
+
+func (suite *ImagingTestSuite) TestGenerateVariantsStripsExif() {
+	content := encodeJpegWithOrientation(40, 20, 6)
+	assert.True(suite.T(), bytes.Contains(content, []byte("Exif")))
+
+	images, err := imaging.GenerateVariants(content, suite.variants)
+
+	assert.NoError(suite.T(), err)
+	for _, img := range images {
+		assert.False(suite.T(), bytes.Contains(img.Content, []byte("Exif")))
+	}
+}
+
+func (suite *ImagingTestSuite) TestGenerateVariantsUnsupported() {
+	_, err := imaging.GenerateVariants([]byte("GIF89a\x01\x00\x01\x00 truncated"), suite.variants)
+	assert.Equal(suite.T(), imaging.ErrUnsupportedFormat, err)
+
+	_, err = imaging.GenerateVariants([]byte("%PDF-1.4"), suite.variants)
+	assert.Equal(suite.T(), imaging.ErrUnsupportedFormat, err)
+}
+
+func (suite *ImagingTestSuite) TestGenerateVariantsTooLarge() {
+	_, err := imaging.GenerateVariants([]byte("GIF89a\xff\xff\xff\xff\x00\x00\x00"), suite.variants)
+	assert.Equal(suite.T(), imaging.ErrImageTooLarge, err)
+
+	// 5000x5000 is far more than an avatar needs
+	_, err = imaging.GenerateVariants([]byte("GIF89a\x88\x13\x88\x13\x00\x00\x00"), suite.variants)
+	assert.Equal(suite.T(), imaging.ErrImageTooLarge, err)
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Field is a typed key of a log line. Fields are redacted by their key like the meta, so a secret
+// passed as String("password", ...) is never written.
+type Field zap.Field
+
+func String(key, value string) Field {
+	switch keyRules[normalizeKey(key)] {
+	case ruleSecret:
+		return Field(zap.String(key, Redacted))
+	case ruleMask:
+		return Field(zap.String(key, Mask(value)))
+	default:
+		return Field(zap.String(key, redactString(value)))
+	}
+}
+
+func Int(key string, value int) Field {
+	if keyRules[normalizeKey(key)] == ruleSecret {
+		return Field(zap.String(key, Redacted))
+	}
+	return Field(zap.Int(key, value))
+}
+
+func Int64(key string, value int64) Field {
+	if keyRules[normalizeKey(key)] == ruleSecret {
+		return Field(zap.String(key, Redacted))
+	}
+	return Field(zap.Int64(key, value))
+}
+
+func Bool(key string, value bool) Field {
+	return Field(zap.Bool(key, value))
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field(zap.Duration(key, value))
+}
+
+func Time(key string, value time.Time) Field {
+	return Field(zap.Time(key, value))
+}
+
+// zapFields unwraps fields for the zap logger
+func zapFields(fields []Field) []zap.Field {
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		zapFields = append(zapFields, zap.Field(field))
+	}
+	return zapFields
+}
+
+// Err logs the message of err and its cause chain under error
+func Err(err error) Field {
+	if err == nil {
+		return Field(zap.Skip())
+	}
+	return Field(zap.String("error", redactString(err.Error())))
+}
+
+// Any logs value by reflection, structs and maps are redacted field by field
+func Any(key string, value interface{}) Field {
+	if value == nil {
+		return Field(zap.Any(key, nil))
+	}
+	return Field(zap.Any(key, redactField(reflect.ValueOf(value), keyRules[normalizeKey(key)], 0)))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"go.elastic.co/apm"
@@ -12,22 +13,56 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
 var logger LoggerConf
 
 type LoggerConf struct {
 	// Logger instance
 	dep *zap.Logger
+	// level is shared by every child logger so a runtime change reaches all of them
+	level *zap.AtomicLevel
 }
 
+// Logger writes a message with its meta, the meta is passed through Redact so callers log their payloads as is.
+// Fields add typed keys next to the meta, With returns a child logger adding its fields to every line.
 type Logger interface {
-	Info(ctx context.Context, msg string, meta interface{})
-	Error(ctx context.Context, msg string, meta interface{})
+	Debug(ctx context.Context, msg string, meta interface{}, fields ...Field)
+	Info(ctx context.Context, msg string, meta interface{}, fields ...Field)
+	Warn(ctx context.Context, msg string, meta interface{}, fields ...Field)
+	Error(ctx context.Context, msg string, meta interface{}, fields ...Field)
+	With(fields ...Field) Logger
+}
+
+// Leveler reads and changes the minimum level of the logger while the service runs
+type Leveler interface {
+	Level() string
+	SetLevel(level string) error
+}
+
+// Options configures the logger, Level is any zap level name and Format is json or console
+type Options struct {
+	Level  string
+	Format string
+}
+
+// ParseLevel reads a level name such as debug, info, warn or error
+func ParseLevel(level string) (zapcore.Level, error) {
+	var parsed zapcore.Level
+	if err := parsed.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(level)))); err != nil {
+		return parsed, fmt.Errorf("unknown log level %q", level)
+	}
+	return parsed, nil
 }
 
-func SetupLogger(serviceName string) *LoggerConf {
+// SetupLogger writes to stdout in the configured format and to the daily file as JSON, both at the
+// configured level. An unknown level falls back to info and an unknown format to JSON.
+func SetupLogger(serviceName string, options Options) *LoggerConf {
 	lg := zap.NewProductionConfig()
 	lg.DisableStacktrace = false
-	lg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	consoleEncoder := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
 		MessageKey:   "msg",
 		LevelKey:     "level",
@@ -48,8 +83,14 @@ func SetupLogger(serviceName string) *LoggerConf {
 		enc.AppendInt64(int64(d) / int64(time.Millisecond))
 	}
 	lg.EncoderConfig.EncodeName = zapcore.FullNameEncoder
+	// The color codes of the console level only belong on a terminal, not in JSON
+	lg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 
 	fileEncoder := zapcore.NewJSONEncoder(lg.EncoderConfig)
+	stdoutEncoder := fileEncoder
+	if options.Format == FormatConsole {
+		stdoutEncoder = consoleEncoder
+	}
 	fileName := fmt.Sprintf("./logs/%s.log", time.Now().Format("2006-01-02"))
 	writer := zapcore.AddSync(&lumberjack.Logger{
 		Filename:   fileName,
@@ -57,21 +98,36 @@ func SetupLogger(serviceName string) *LoggerConf {
 		MaxBackups: 3,
 		MaxAge:     14, // days
 	})
+	parsed, err := ParseLevel(options.Level)
+	if err != nil {
+		parsed = zapcore.InfoLevel
+	}
+	level := zap.NewAtomicLevelAt(parsed)
 	core := zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), zap.DebugLevel),
-		zapcore.NewCore(fileEncoder, writer, zap.DebugLevel),
+		zapcore.NewCore(stdoutEncoder, zapcore.AddSync(os.Stdout), level),
+		zapcore.NewCore(fileEncoder, writer, level),
 	)
 
 	zapLogger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 	zapLogger = zapLogger.With(ZapString("serviceName", serviceName))
 	return &LoggerConf{
-		dep: zapLogger,
+		dep:   zapLogger,
+		level: &level,
+	}
+}
+
+// NewLogger wraps a zap logger whose cores are enabled by level, for tests and tools writing elsewhere
+func NewLogger(dep *zap.Logger, level *zap.AtomicLevel) *LoggerConf {
+	return &LoggerConf{
+		dep:   dep,
+		level: level,
 	}
 }
 
 func Init(l *LoggerConf) {
 	logger = LoggerConf{
-		dep: l.dep,
+		dep:   l.dep,
+		level: l.level,
 	}
 }
 
@@ -79,14 +135,59 @@ func GetLogger() Logger {
 	return &logger
 }
 
-func (l *LoggerConf) Info(ctx context.Context, msg string, meta interface{}) {
-	metaField := zap.Any("meta", meta)
-	l.withTraceInfo(ctx).dep.Info(msg, metaField)
+// GetLeveler is the level switch of the logger set up with Init
+func GetLeveler() Leveler {
+	return &logger
+}
+
+func (l *LoggerConf) Debug(ctx context.Context, msg string, meta interface{}, fields ...Field) {
+	l.withTraceInfo(ctx).dep.Debug(msg, withMeta(meta, fields)...)
+}
+
+func (l *LoggerConf) Info(ctx context.Context, msg string, meta interface{}, fields ...Field) {
+	l.withTraceInfo(ctx).dep.Info(msg, withMeta(meta, fields)...)
+}
+
+func (l *LoggerConf) Warn(ctx context.Context, msg string, meta interface{}, fields ...Field) {
+	l.withTraceInfo(ctx).dep.Warn(msg, withMeta(meta, fields)...)
+}
+
+func (l *LoggerConf) Error(ctx context.Context, msg string, meta interface{}, fields ...Field) {
+	l.withTraceInfo(ctx).dep.Error(msg, withMeta(meta, fields)...)
+}
+
+// With is a child logger adding fields to every line, it shares the level of its parent
+func (l *LoggerConf) With(fields ...Field) Logger {
+	return l.Clone(l.dep.With(zapFields(fields)...))
 }
 
-func (l *LoggerConf) Error(ctx context.Context, msg string, meta interface{}) {
-	metaField := zap.Any("meta", meta)
-	l.withTraceInfo(ctx).dep.Error(msg, metaField)
+// Level is the name of the minimum level currently written
+func (l *LoggerConf) Level() string {
+	if l.level == nil {
+		return zapcore.DebugLevel.String()
+	}
+	return l.level.Level().String()
+}
+
+// SetLevel changes the minimum level of the logger and of every child logger
+func (l *LoggerConf) SetLevel(level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if l.level == nil {
+		return fmt.Errorf("log level of this logger cannot be changed")
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
+// withMeta puts the redacted meta before the fields, a missing meta is left out of the line
+func withMeta(meta interface{}, fields []Field) []zap.Field {
+	if meta == nil {
+		return zapFields(fields)
+	}
+	return append([]zap.Field{zap.Any("meta", Redact(meta))}, zapFields(fields)...)
 }
 
 func (l *LoggerConf) withTraceInfo(ctx context.Context) *LoggerConf {
@@ -113,6 +214,7 @@ func ZapString(key, value string) zap.Field {
 // Clone will create new Logger instance with specified zap.Logger.
 func (l *LoggerConf) Clone(logger *zap.Logger) *LoggerConf {
 	return &LoggerConf{
-		dep: logger,
+		dep:   logger,
+		level: l.level,
 	}
 }
@@ -0,0 +1,99 @@
+package log_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"user-service/internal/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger(level zapcore.Level) (*log.LoggerConf, *observer.ObservedLogs) {
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	core, logs := observer.New(atomicLevel)
+	return log.NewLogger(zap.New(core), &atomicLevel), logs
+}
+
+func TestLoggerLevels(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.InfoLevel)
+
+	// Call the function under test
+	logger.Debug(context.Background(), "debug", nil)
+	logger.Info(context.Background(), "info", nil)
+	logger.Warn(context.Background(), "warn", nil)
+	logger.Error(context.Background(), "error", nil)
+
+	// Assertions
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "warn", entries[1].Message)
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.InfoLevel)
+	child := logger.With(log.String("module", "user"))
+
+	// Call the function under test
+	err := logger.SetLevel("DEBUG")
+	child.Debug(context.Background(), "debug", nil)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", logger.Level())
+	assert.Equal(t, 1, logs.FilterMessage("debug").Len())
+}
+
+func TestLoggerSetLevelErr(t *testing.T) {
+	logger, _ := newObservedLogger(zapcore.InfoLevel)
+
+	// Call the function under test
+	err := logger.SetLevel("verbose")
+
+	// Assertions
+	assert.EqualError(t, err, `unknown log level "verbose"`)
+	assert.Equal(t, "info", logger.Level())
+}
+
+func TestLoggerFields(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.DebugLevel)
+	child := logger.With(log.String("module", "user"), log.String("email", "budi@example.com"))
+
+	// Call the function under test
+	child.Info(context.Background(), "login", map[string]string{"password": "Rahasia123!"},
+		log.Int("attempt", 2), log.String("otp", "123456"), log.Err(fmt.Errorf("token=abc expired")))
+
+	// Assertions
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "user", fields["module"])
+	assert.Equal(t, "b***@example.com", fields["email"])
+	assert.Equal(t, map[string]interface{}{"password": log.Redacted}, fields["meta"])
+	assert.Equal(t, int64(2), fields["attempt"])
+	assert.Equal(t, log.Redacted, fields["otp"])
+	assert.Equal(t, "token=[REDACTED] expired", fields["error"])
+}
+
+func TestLoggerNilMeta(t *testing.T) {
+	logger, logs := newObservedLogger(zapcore.DebugLevel)
+
+	// Call the function under test
+	logger.Info(context.Background(), "started", nil, log.Bool("ready", true))
+
+	// Assertions
+	fields := logs.All()[0].ContextMap()
+	assert.NotContains(t, fields, "meta")
+	assert.Equal(t, true, fields["ready"])
+}
+
+func TestParseLevel(t *testing.T) {
+	// Call the function under test
+	level, err := log.ParseLevel(" Warn ")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.WarnLevel, level)
+}
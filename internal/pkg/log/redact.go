@@ -0,0 +1,275 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Redacted replaces the value of a secret in the logs
+const Redacted = "[REDACTED]"
+
+// redactDepth stops the walk of a self-referencing meta
+const redactDepth = 16
+
+const (
+	ruleNone = iota
+	ruleSecret
+	ruleMask
+	ruleOmit
+)
+
+// keyRules redacts fields by name when their struct has no log tag, names are compared lowercase without
+// separators so otp_number, otpNumber and OtpNumber are the same key
+var keyRules = map[string]int{
+	"password":        ruleSecret,
+	"newpassword":     ruleSecret,
+	"oldpassword":     ruleSecret,
+	"confirmpassword": ruleSecret,
+	"otp":             ruleSecret,
+	"otpnumber":       ruleSecret,
+	"pin":             ruleSecret,
+	"token":           ruleSecret,
+	"authtoken":       ruleSecret,
+	"accesstoken":     ruleSecret,
+	"refreshtoken":    ruleSecret,
+	"secret":          ruleSecret,
+	"authorization":   ruleSecret,
+	"nik":             ruleSecret,
+	"kknumber":        ruleSecret,
+	"address":         ruleSecret,
+	"email":           ruleMask,
+	"mobilenumber":    ruleMask,
+	"phone":           ruleMask,
+	"phonenumber":     ruleMask,
+}
+
+// keyPrefix and pairValue find key:value, key=value and "key":"value" pairs in a pre-formatted meta such
+// as %+v output, the value is only read after a known key so any other pair cannot swallow the next one
+var (
+	keyPrefix = regexp.MustCompile(`"?([A-Za-z][A-Za-z0-9_\-]*)"?\s*[:=]\s*`)
+	pairValue = regexp.MustCompile(`^("[^"]*"|[^\s,;&}\]]*)`)
+	// nextField starts the next field of a %+v struct, the value before it may hold spaces
+	nextField = regexp.MustCompile(`^\s+[A-Z][A-Za-z0-9_]*:`)
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Redact copies meta with its secrets replaced and its personal data masked before it is logged. Struct
+// fields follow their log tag: secret redacts the value, mask keeps its last characters and - drops the
+// field. Untagged fields and map keys are looked up in the known key names, and strings are scrubbed of
+// key:value pairs naming one. Structs become maps keyed by their json name.
+func Redact(meta interface{}) interface{} {
+	if meta == nil {
+		return nil
+	}
+	if err, ok := meta.(error); ok {
+		return redactString(err.Error())
+	}
+	return redact(reflect.ValueOf(meta), 0)
+}
+
+func redact(value reflect.Value, depth int) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	if depth > redactDepth {
+		return Redacted
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		if err, ok := value.Interface().(error); ok {
+			return redactString(err.Error())
+		}
+		return redact(value.Elem(), depth+1)
+	case reflect.String:
+		return redactString(value.String())
+	case reflect.Struct:
+		if value.Type() == timeType || value.Type().Implements(jsonMarshalerType) || value.Type().Implements(textMarshalerType) {
+			return value.Interface()
+		}
+		fields := map[string]interface{}{}
+		redactStruct(value, fields, depth)
+		return fields
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		entries := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			entries[key] = redactField(iter.Value(), keyRules[normalizeKey(key)], depth)
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return redactString(string(value.Bytes()))
+		}
+		items := make([]interface{}, value.Len())
+		for i := range items {
+			items[i] = redact(value.Index(i), depth+1)
+		}
+		return items
+	default:
+		if !value.CanInterface() {
+			return nil
+		}
+		return value.Interface()
+	}
+}
+
+// redactStruct adds the exported fields of value to fields, embedded structs without a json name are flattened
+func redactStruct(value reflect.Value, fields map[string]interface{}, depth int) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			redactStruct(value.Field(i), fields, depth+1)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		rule := tagRule(field.Tag.Get("log"))
+		if rule == ruleNone {
+			rule = keyRules[normalizeKey(name)]
+		}
+		if rule == ruleOmit {
+			continue
+		}
+		fields[name] = redactField(value.Field(i), rule, depth)
+	}
+}
+
+func redactField(value reflect.Value, rule int, depth int) interface{} {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch rule {
+	case ruleSecret:
+		if value.Kind() == reflect.String && value.Len() == 0 {
+			return ""
+		}
+		return Redacted
+	case ruleMask:
+		// Masking only hides text, a flag such as the email marketing preference is kept
+		if value.Kind() == reflect.String {
+			return Mask(value.String())
+		}
+	}
+	return redact(value, depth+1)
+}
+
+func tagRule(tag string) int {
+	switch strings.SplitN(tag, ",", 2)[0] {
+	case "secret":
+		return ruleSecret
+	case "mask":
+		return ruleMask
+	case "-":
+		return ruleOmit
+	default:
+		return ruleNone
+	}
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+}
+
+// redactString scrubs the values of the known key names out of a pre-formatted string
+func redactString(s string) string {
+	var out strings.Builder
+	cursor := 0
+	for _, match := range keyPrefix.FindAllStringSubmatchIndex(s, -1) {
+		if match[0] < cursor {
+			continue
+		}
+		rule := keyRules[normalizeKey(s[match[2]:match[3]])]
+		if rule == ruleNone {
+			continue
+		}
+		value, quote := pairValue.FindString(s[match[1]:]), ""
+		if s[match[0]] != '"' && inStruct(s[:match[0]]) {
+			value = structValue(s[match[1]:])
+		}
+		if strings.HasPrefix(value, `"`) {
+			value, quote = strings.Trim(value, `"`), `"`
+		}
+		out.WriteString(s[cursor:match[1]])
+		if rule == ruleMask {
+			out.WriteString(quote + Mask(value) + quote)
+		} else {
+			out.WriteString(quote + Redacted + quote)
+		}
+		cursor = match[1] + len(value) + 2*len(quote)
+	}
+	out.WriteString(s[cursor:])
+	return out.String()
+}
+
+// inStruct tells whether a key starts inside the braces of a %+v struct
+func inStruct(before string) bool {
+	return strings.Count(before, "{") > strings.Count(before, "}")
+}
+
+// structValue reads an unquoted %+v field value up to the next field or the closing brace, a nested
+// struct or slice is read whole
+func structValue(s string) string {
+	depth := 0
+	for i, r := range s {
+		switch {
+		case r == '{' || r == '[':
+			depth++
+		case r == '}' || r == ']':
+			if depth == 0 {
+				return s[:i]
+			}
+			depth--
+		case depth == 0 && nextField.MatchString(s[i:]):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// Mask hides all but the last four characters of s, an email keeps the first letter of its local part
+// and its domain
+func Mask(s string) string {
+	if at := strings.LastIndex(s, "@"); at > 0 {
+		first, _ := utf8.DecodeRuneInString(s)
+		return string(first) + "***" + s[at:]
+	}
+	length := utf8.RuneCountInString(s)
+	if length <= 4 {
+		return strings.Repeat("*", length)
+	}
+	runes := []rune(s)
+	return strings.Repeat("*", length-4) + string(runes[length-4:])
+}
@@ -0,0 +1,118 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+	"user-service/internal/pkg/errors"
+	"user-service/internal/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type credentials struct {
+	Email    string `json:"email" log:"mask"`
+	Password string `json:"password" log:"secret"`
+	Session  string `json:"session" log:"-"`
+	Note     string `json:"note"`
+}
+
+type registration struct {
+	credentials
+	MobileNumber string    `json:"mobileNumber"`
+	OtpNumber    string    `json:"otp_number"`
+	Marketing    bool      `json:"email"`
+	Tags         []string  `json:"tags"`
+	CreatedAt    time.Time `json:"createdAt"`
+	internal     string
+}
+
+func TestRedactStructTags(t *testing.T) {
+	// Call the function under test
+	result := log.Redact(&credentials{Email: "budi@example.com", Password: "Rahasia123!", Session: "abc", Note: "first login"})
+
+	// Assertions
+	assert.Equal(t, map[string]interface{}{
+		"email":    "b***@example.com",
+		"password": log.Redacted,
+		"note":     "first login",
+	}, result)
+}
+
+func TestRedactKeyNames(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Call the function under test
+	result := log.Redact(registration{
+		credentials:  credentials{Password: "Rahasia123!"},
+		MobileNumber: "081234567890",
+		OtpNumber:    "123456",
+		Marketing:    true,
+		Tags:         []string{"token=abc"},
+		CreatedAt:    createdAt,
+		internal:     "hidden",
+	})
+
+	// Assertions
+	assert.Equal(t, map[string]interface{}{
+		"email":        true,
+		"password":     log.Redacted,
+		"note":         "",
+		"mobileNumber": "********7890",
+		"otp_number":   log.Redacted,
+		"tags":         []interface{}{"token=" + log.Redacted},
+		"createdAt":    createdAt,
+	}, result)
+}
+
+func TestRedactMap(t *testing.T) {
+	// Call the function under test
+	result := log.Redact(map[string]interface{}{"Authorization": "Bearer abc", "userId": "u-1", "pin": 1234})
+
+	// Assertions
+	assert.Equal(t, map[string]interface{}{"Authorization": log.Redacted, "userId": "u-1", "pin": log.Redacted}, result)
+}
+
+func TestRedactString(t *testing.T) {
+	cases := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{"struct", "{Email:budi@example.com Password:Rahasia123! Role:user}", "{Email:b***@example.com Password:[REDACTED] Role:user}"},
+		{"struct with spaces", "{FullName:Budi Santoso Address:Jl. Sudirman No 5 Role:user}", "{FullName:Budi Santoso Address:[REDACTED] Role:user}"},
+		{"nested struct", "{Address:{Street:Jl. Sudirman No 5 City:Jakarta} Role:user}", "{Address:[REDACTED] Role:user}"},
+		{"json", `{"otpNumber":"123456","email":"budi@example.com"}`, `{"otpNumber":"[REDACTED]","email":"b***@example.com"}`},
+		{"query", "refresh_token=abc&page=2", "refresh_token=[REDACTED]&page=2"},
+		{"plain", "user u-1 not found", "user u-1 not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Assertions
+			assert.Equal(t, c.want, log.Redact(c.meta))
+		})
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	// Call the function under test
+	result := log.Redact(errors.Wrap(errors.BadRequest("password=Rahasia123! rejected"), "login"))
+
+	// Assertions
+	assert.Equal(t, "login: password=[REDACTED] rejected", result)
+}
+
+func TestRedactNil(t *testing.T) {
+	var payload *credentials
+
+	// Assertions
+	assert.Nil(t, log.Redact(nil))
+	assert.Nil(t, log.Redact(payload))
+}
+
+func TestMask(t *testing.T) {
+	// Assertions
+	assert.Equal(t, "a***@example.com", log.Mask("ani@example.com"))
+	assert.Equal(t, "******5678", log.Mask("1234345678"))
+	assert.Equal(t, "***", log.Mask("123"))
+	assert.Equal(t, "", log.Mask(""))
+}
@@ -16,6 +16,66 @@ type MongodbRepositoryQuery struct {
 	mock.Mock
 }
 
+// CountActiveChildren provides a mock function with given fields: ctx, level, id
+func (_m *MongodbRepositoryQuery) CountActiveChildren(ctx context.Context, level string, id string) <-chan helpers.Result {
+	ret := _m.Called(ctx, level, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountActiveChildren")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, level, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindAllRegions provides a mock function with given fields: ctx, level, cursor, size
+func (_m *MongodbRepositoryQuery) FindAllRegions(ctx context.Context, level string, cursor string, size int64) <-chan helpers.Result {
+	ret := _m.Called(ctx, level, cursor, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllRegions")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, level, cursor, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindAllSubdistricts provides a mock function with given fields: ctx, cursor, size
+func (_m *MongodbRepositoryQuery) FindAllSubdistricts(ctx context.Context, cursor string, size int64) <-chan helpers.Result {
+	ret := _m.Called(ctx, cursor, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllSubdistricts")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, cursor, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindCitiesByParam provides a mock function with given fields: ctx, payload
 func (_m *MongodbRepositoryQuery) FindCitiesByParam(ctx context.Context, payload request.City) <-chan helpers.Result {
 	ret := _m.Called(ctx, payload)
@@ -96,6 +156,46 @@ func (_m *MongodbRepositoryQuery) FindDistrictByParam(ctx context.Context, paylo
 	return r0
 }
 
+// FindNearestSubdistrict provides a mock function with given fields: ctx, latitude, longitude, maxDistance
+func (_m *MongodbRepositoryQuery) FindNearestSubdistrict(ctx context.Context, latitude float64, longitude float64, maxDistance float64) <-chan helpers.Result {
+	ret := _m.Called(ctx, latitude, longitude, maxDistance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindNearestSubdistrict")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, latitude, longitude, maxDistance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindOneCity provides a mock function with given fields: ctx, id
+func (_m *MongodbRepositoryQuery) FindOneCity(ctx context.Context, id string) <-chan helpers.Result {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneCity")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindOneCountry provides a mock function with given fields: ctx, id
 func (_m *MongodbRepositoryQuery) FindOneCountry(ctx context.Context, id int) <-chan helpers.Result {
 	ret := _m.Called(ctx, id)
@@ -116,6 +216,66 @@ func (_m *MongodbRepositoryQuery) FindOneCountry(ctx context.Context, id int) <-
 	return r0
 }
 
+// FindOneCountryByCode provides a mock function with given fields: ctx, code
+func (_m *MongodbRepositoryQuery) FindOneCountryByCode(ctx context.Context, code string) <-chan helpers.Result {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneCountryByCode")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindOneDistrict provides a mock function with given fields: ctx, id
+func (_m *MongodbRepositoryQuery) FindOneDistrict(ctx context.Context, id string) <-chan helpers.Result {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneDistrict")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindOneProvince provides a mock function with given fields: ctx, id
+func (_m *MongodbRepositoryQuery) FindOneProvince(ctx context.Context, id string) <-chan helpers.Result {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneProvince")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindOneSubdistrict provides a mock function with given fields: ctx, id
 func (_m *MongodbRepositoryQuery) FindOneSubdistrict(ctx context.Context, id string) <-chan helpers.Result {
 	ret := _m.Called(ctx, id)
@@ -176,6 +336,26 @@ func (_m *MongodbRepositoryQuery) FindSubDistrictByParam(ctx context.Context, pa
 	return r0
 }
 
+// FindSubdistrictsByPostalCode provides a mock function with given fields: ctx, postalCode
+func (_m *MongodbRepositoryQuery) FindSubdistrictsByPostalCode(ctx context.Context, postalCode string) <-chan helpers.Result {
+	ret := _m.Called(ctx, postalCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSubdistrictsByPostalCode")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, postalCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // NewMongodbRepositoryQuery creates a new instance of MongodbRepositoryQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMongodbRepositoryQuery(t interface {
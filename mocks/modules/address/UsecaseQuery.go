@@ -16,6 +16,102 @@ type UsecaseQuery struct {
 	mock.Mock
 }
 
+// BuildDataset provides a mock function with given fields: origCtx
+func (_m *UsecaseQuery) BuildDataset(origCtx context.Context) error {
+	ret := _m.Called(origCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BuildDataset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(origCtx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BuildSearchIndex provides a mock function with given fields: origCtx
+func (_m *UsecaseQuery) BuildSearchIndex(origCtx context.Context) error {
+	ret := _m.Called(origCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BuildSearchIndex")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(origCtx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportDataset provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) ExportDataset(origCtx context.Context, payload request.Dataset) (*response.Dataset, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportDataset")
+	}
+
+	var r0 *response.Dataset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.Dataset) (*response.Dataset, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.Dataset) *response.Dataset); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Dataset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.Dataset) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByPostalCode provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) FindByPostalCode(origCtx context.Context, payload request.PostalCode) ([]response.SubDistrict, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByPostalCode")
+	}
+
+	var r0 []response.SubDistrict
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.PostalCode) ([]response.SubDistrict, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.PostalCode) []response.SubDistrict); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]response.SubDistrict)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.PostalCode) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindCities provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseQuery) FindCities(origCtx context.Context, payload request.City) (*response.CityResp, error) {
 	ret := _m.Called(origCtx, payload)
@@ -46,9 +142,9 @@ func (_m *UsecaseQuery) FindCities(origCtx context.Context, payload request.City
 	return r0, r1
 }
 
-// FindContinent provides a mock function with given fields: origCtx
-func (_m *UsecaseQuery) FindContinent(origCtx context.Context) (*response.ContinentResp, error) {
-	ret := _m.Called(origCtx)
+// FindContinent provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) FindContinent(origCtx context.Context, payload request.Continent) (*response.ContinentResp, error) {
+	ret := _m.Called(origCtx, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindContinent")
@@ -56,19 +152,19 @@ func (_m *UsecaseQuery) FindContinent(origCtx context.Context) (*response.Contin
 
 	var r0 *response.ContinentResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*response.ContinentResp, error)); ok {
-		return rf(origCtx)
+	if rf, ok := ret.Get(0).(func(context.Context, request.Continent) (*response.ContinentResp, error)); ok {
+		return rf(origCtx, payload)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *response.ContinentResp); ok {
-		r0 = rf(origCtx)
+	if rf, ok := ret.Get(0).(func(context.Context, request.Continent) *response.ContinentResp); ok {
+		r0 = rf(origCtx, payload)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*response.ContinentResp)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(origCtx)
+	if rf, ok := ret.Get(1).(func(context.Context, request.Continent) error); ok {
+		r1 = rf(origCtx, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,6 +202,36 @@ func (_m *UsecaseQuery) FindCountries(origCtx context.Context, payload request.C
 	return r0, r1
 }
 
+// FindCountryByCode provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) FindCountryByCode(origCtx context.Context, payload request.CountryCode) (*response.Country, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCountryByCode")
+	}
+
+	var r0 *response.Country
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.CountryCode) (*response.Country, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.CountryCode) *response.Country); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Country)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.CountryCode) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindDistricts provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseQuery) FindDistricts(origCtx context.Context, payload request.District) (*response.DistrictResp, error) {
 	ret := _m.Called(origCtx, payload)
@@ -196,6 +322,96 @@ func (_m *UsecaseQuery) FindSubDistricts(origCtx context.Context, payload reques
 	return r0, r1
 }
 
+// ReverseGeocode provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) ReverseGeocode(origCtx context.Context, payload request.ReverseGeocode) (*response.ReverseGeocode, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseGeocode")
+	}
+
+	var r0 *response.ReverseGeocode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.ReverseGeocode) (*response.ReverseGeocode, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.ReverseGeocode) *response.ReverseGeocode); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.ReverseGeocode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.ReverseGeocode) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchAddresses provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) SearchAddresses(origCtx context.Context, payload request.Search) ([]response.SearchResult, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchAddresses")
+	}
+
+	var r0 []response.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.Search) ([]response.SearchResult, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.Search) []response.SearchResult); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]response.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.Search) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateHierarchy provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) ValidateHierarchy(origCtx context.Context, payload request.Hierarchy) (*response.Hierarchy, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateHierarchy")
+	}
+
+	var r0 *response.Hierarchy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.Hierarchy) (*response.Hierarchy, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.Hierarchy) *response.Hierarchy); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Hierarchy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.Hierarchy) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUsecaseQuery creates a new instance of UsecaseQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUsecaseQuery(t interface {
@@ -8,6 +8,8 @@ import (
 	helpers "user-service/internal/pkg/helpers"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MongodbRepositoryCommand is an autogenerated mock type for the MongodbRepositoryCommand type
@@ -15,6 +17,146 @@ type MongodbRepositoryCommand struct {
 	mock.Mock
 }
 
+// DeleteDataExportsByUserId provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryCommand) DeleteDataExportsByUserId(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDataExportsByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// EraseAddressesByUserId provides a mock function with given fields: ctx, userId, erasedAt
+func (_m *MongodbRepositoryCommand) EraseAddressesByUserId(ctx context.Context, userId string, erasedAt time.Time) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId, erasedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseAddressesByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId, erasedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOneUserDueForDeletion provides a mock function with given fields: ctx, _a1, before
+func (_m *MongodbRepositoryCommand) UpdateOneUserDueForDeletion(ctx context.Context, _a1 entity.User, before time.Time) <-chan helpers.Result {
+	ret := _m.Called(ctx, _a1, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOneUserDueForDeletion")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, time.Time) <-chan helpers.Result); ok {
+		r0 = rf(ctx, _a1, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOneUserWithVersion provides a mock function with given fields: ctx, _a1, version
+func (_m *MongodbRepositoryCommand) UpdateOneUserWithVersion(ctx context.Context, _a1 entity.User, version int64) <-chan helpers.Result {
+	ret := _m.Called(ctx, _a1, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOneUserWithVersion")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, entity.User, int64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, _a1, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// UpsertOneAddress provides a mock function with given fields: ctx, address
+func (_m *MongodbRepositoryCommand) UpsertOneAddress(ctx context.Context, address entity.AddressBookEntry) <-chan helpers.Result {
+	ret := _m.Called(ctx, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertOneAddress")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, entity.AddressBookEntry) <-chan helpers.Result); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// UpsertOneConsent provides a mock function with given fields: ctx, consent
+func (_m *MongodbRepositoryCommand) UpsertOneConsent(ctx context.Context, consent entity.Consent) <-chan helpers.Result {
+	ret := _m.Called(ctx, consent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertOneConsent")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Consent) <-chan helpers.Result); ok {
+		r0 = rf(ctx, consent)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// UpsertOneDataExport provides a mock function with given fields: ctx, dataExport
+func (_m *MongodbRepositoryCommand) UpsertOneDataExport(ctx context.Context, dataExport entity.DataExport) <-chan helpers.Result {
+	ret := _m.Called(ctx, dataExport)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertOneDataExport")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, entity.DataExport) <-chan helpers.Result); ok {
+		r0 = rf(ctx, dataExport)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // UpsertOneUser provides a mock function with given fields: ctx, _a1
 func (_m *MongodbRepositoryCommand) UpsertOneUser(ctx context.Context, _a1 entity.User) <-chan helpers.Result {
 	ret := _m.Called(ctx, _a1)
@@ -7,6 +7,10 @@ import (
 	helpers "user-service/internal/pkg/helpers"
 
 	mock "github.com/stretchr/testify/mock"
+
+	request "user-service/internal/modules/user/models/request"
+
+	time "time"
 )
 
 // MongodbRepositoryQuery is an autogenerated mock type for the MongodbRepositoryQuery type
@@ -14,6 +18,166 @@ type MongodbRepositoryQuery struct {
 	mock.Mock
 }
 
+// CountAddressesByUserId provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryQuery) CountAddressesByUserId(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountAddressesByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindAddressesByUserId provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryQuery) FindAddressesByUserId(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAddressesByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindConsentsByUserId provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryQuery) FindConsentsByUserId(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindConsentsByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindDataExportsByStatus provides a mock function with given fields: ctx, status, size
+func (_m *MongodbRepositoryQuery) FindDataExportsByStatus(ctx context.Context, status string, size int64) <-chan helpers.Result {
+	ret := _m.Called(ctx, status, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDataExportsByStatus")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, status, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindDataExportsByUserId provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryQuery) FindDataExportsByUserId(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDataExportsByUserId")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindDefaultAddress provides a mock function with given fields: ctx, userId
+func (_m *MongodbRepositoryQuery) FindDefaultAddress(ctx context.Context, userId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDefaultAddress")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindMarketingAudience provides a mock function with given fields: ctx, payload, purpose
+func (_m *MongodbRepositoryQuery) FindMarketingAudience(ctx context.Context, payload request.FindMarketingAudience, purpose string) <-chan helpers.Result {
+	ret := _m.Called(ctx, payload, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMarketingAudience")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, request.FindMarketingAudience, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, payload, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindOneAddress provides a mock function with given fields: ctx, userId, addressId
+func (_m *MongodbRepositoryQuery) FindOneAddress(ctx context.Context, userId string, addressId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId, addressId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneAddress")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId, addressId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindOneByEmail provides a mock function with given fields: ctx, email
 func (_m *MongodbRepositoryQuery) FindOneByEmail(ctx context.Context, email string) <-chan helpers.Result {
 	ret := _m.Called(ctx, email)
@@ -54,6 +218,46 @@ func (_m *MongodbRepositoryQuery) FindOneByEmailUserTemp(ctx context.Context, em
 	return r0
 }
 
+// FindOneConsent provides a mock function with given fields: ctx, userId, purpose
+func (_m *MongodbRepositoryQuery) FindOneConsent(ctx context.Context, userId string, purpose string) <-chan helpers.Result {
+	ret := _m.Called(ctx, userId, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneConsent")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, userId, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// FindOneDataExport provides a mock function with given fields: ctx, exportId
+func (_m *MongodbRepositoryQuery) FindOneDataExport(ctx context.Context, exportId string) <-chan helpers.Result {
+	ret := _m.Called(ctx, exportId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOneDataExport")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan helpers.Result); ok {
+		r0 = rf(ctx, exportId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindOneUserId provides a mock function with given fields: ctx, userId
 func (_m *MongodbRepositoryQuery) FindOneUserId(ctx context.Context, userId string) <-chan helpers.Result {
 	ret := _m.Called(ctx, userId)
@@ -74,6 +278,26 @@ func (_m *MongodbRepositoryQuery) FindOneUserId(ctx context.Context, userId stri
 	return r0
 }
 
+// FindUsersDueForDeletion provides a mock function with given fields: ctx, before, size
+func (_m *MongodbRepositoryQuery) FindUsersDueForDeletion(ctx context.Context, before time.Time, size int64) <-chan helpers.Result {
+	ret := _m.Called(ctx, before, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsersDueForDeletion")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) <-chan helpers.Result); ok {
+		r0 = rf(ctx, before, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // NewMongodbRepositoryQuery creates a new instance of MongodbRepositoryQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMongodbRepositoryQuery(t interface {
@@ -16,6 +16,84 @@ type UsecaseCommand struct {
 	mock.Mock
 }
 
+// CreateAddress provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) CreateAddress(origCtx context.Context, payload request.SaveAddress, userId string) (*response.Address, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAddress")
+	}
+
+	var r0 *response.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.SaveAddress, string) (*response.Address, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.SaveAddress, string) *response.Address); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.SaveAddress, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccount provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) DeleteAccount(origCtx context.Context, payload request.DeleteAccount, userId string) (*response.DeleteAccount, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 *response.DeleteAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.DeleteAccount, string) (*response.DeleteAccount, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.DeleteAccount, string) *response.DeleteAccount); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DeleteAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.DeleteAccount, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAddress provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseCommand) DeleteAddress(origCtx context.Context, payload request.AddressBookEntry) error {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.AddressBookEntry) error); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LoginUser provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseCommand) LoginUser(origCtx context.Context, payload request.LoginUser) (*response.LoginUserResp, error) {
 	ret := _m.Called(origCtx, payload)
@@ -46,6 +124,92 @@ func (_m *UsecaseCommand) LoginUser(origCtx context.Context, payload request.Log
 	return r0, r1
 }
 
+// PatchUser provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseCommand) PatchUser(origCtx context.Context, payload request.PatchUser) (*response.GetProfile, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchUser")
+	}
+
+	var r0 *response.GetProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.PatchUser) (*response.GetProfile, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.PatchUser) *response.GetProfile); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.GetProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.PatchUser) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessAccountDeletion provides a mock function with given fields: origCtx
+func (_m *UsecaseCommand) ProcessAccountDeletion(origCtx context.Context) (int, error) {
+	ret := _m.Called(origCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessAccountDeletion")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(origCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(origCtx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(origCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessDataExport provides a mock function with given fields: origCtx
+func (_m *UsecaseCommand) ProcessDataExport(origCtx context.Context) (int, error) {
+	ret := _m.Called(origCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessDataExport")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(origCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(origCtx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(origCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RegisterUser provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseCommand) RegisterUser(origCtx context.Context, payload request.RegisterUser) (*response.RegisterUser, error) {
 	ret := _m.Called(origCtx, payload)
@@ -76,6 +240,156 @@ func (_m *UsecaseCommand) RegisterUser(origCtx context.Context, payload request.
 	return r0, r1
 }
 
+// RequestDataExport provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) RequestDataExport(origCtx context.Context, payload request.RequestDataExport, userId string) (*response.DataExport, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestDataExport")
+	}
+
+	var r0 *response.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.RequestDataExport, string) (*response.DataExport, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.RequestDataExport, string) *response.DataExport); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.RequestDataExport, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDefaultAddress provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseCommand) SetDefaultAddress(origCtx context.Context, payload request.AddressBookEntry) (*response.Address, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetDefaultAddress")
+	}
+
+	var r0 *response.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.AddressBookEntry) (*response.Address, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.AddressBookEntry) *response.Address); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.AddressBookEntry) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAddress provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) UpdateAddress(origCtx context.Context, payload request.SaveAddress, userId string) (*response.Address, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAddress")
+	}
+
+	var r0 *response.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.SaveAddress, string) (*response.Address, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.SaveAddress, string) *response.Address); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.SaveAddress, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateConsents provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) UpdateConsents(origCtx context.Context, payload request.UpdateConsents, userId string) (*response.Consents, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConsents")
+	}
+
+	var r0 *response.Consents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.UpdateConsents, string) (*response.Consents, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.UpdateConsents, string) *response.Consents); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Consents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.UpdateConsents, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePreferences provides a mock function with given fields: origCtx, payload, userId
+func (_m *UsecaseCommand) UpdatePreferences(origCtx context.Context, payload request.UpdatePreferences, userId string) (*response.Preferences, error) {
+	ret := _m.Called(origCtx, payload, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePreferences")
+	}
+
+	var r0 *response.Preferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.UpdatePreferences, string) (*response.Preferences, error)); ok {
+		return rf(origCtx, payload, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.UpdatePreferences, string) *response.Preferences); ok {
+		r0 = rf(origCtx, payload, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Preferences)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.UpdatePreferences, string) error); ok {
+		r1 = rf(origCtx, payload, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: origCtx, payload, userId
 func (_m *UsecaseCommand) UpdateUser(origCtx context.Context, payload request.UpdateUser, userId string) (string, error) {
 	ret := _m.Called(origCtx, payload, userId)
@@ -104,6 +418,36 @@ func (_m *UsecaseCommand) UpdateUser(origCtx context.Context, payload request.Up
 	return r0, r1
 }
 
+// UploadAvatar provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseCommand) UploadAvatar(origCtx context.Context, payload request.UploadAvatar) (*response.Avatar, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadAvatar")
+	}
+
+	var r0 *response.Avatar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.UploadAvatar) (*response.Avatar, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.UploadAvatar) *response.Avatar); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Avatar)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.UploadAvatar) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyRegisterUser provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseCommand) VerifyRegisterUser(origCtx context.Context, payload request.VerifyRegisterUser) (*response.VerifyRegister, error) {
 	ret := _m.Called(origCtx, payload)
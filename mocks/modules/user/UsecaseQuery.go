@@ -16,6 +16,216 @@ type UsecaseQuery struct {
 	mock.Mock
 }
 
+// DownloadDataExport provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) DownloadDataExport(origCtx context.Context, payload request.DownloadDataExport) (*response.DataExportFile, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadDataExport")
+	}
+
+	var r0 *response.DataExportFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.DownloadDataExport) (*response.DataExportFile, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.DownloadDataExport) *response.DataExportFile); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DataExportFile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.DownloadDataExport) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMarketingAudience provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) FindMarketingAudience(origCtx context.Context, payload request.FindMarketingAudience) (*response.MarketingAudience, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMarketingAudience")
+	}
+
+	var r0 *response.MarketingAudience
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.FindMarketingAudience) (*response.MarketingAudience, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.FindMarketingAudience) *response.MarketingAudience); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.MarketingAudience)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.FindMarketingAudience) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAddresses provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) GetAddresses(origCtx context.Context, payload request.GetAddresses) ([]response.Address, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAddresses")
+	}
+
+	var r0 []response.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetAddresses) ([]response.Address, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetAddresses) []response.Address); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]response.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.GetAddresses) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConsents provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) GetConsents(origCtx context.Context, payload request.GetConsents) (*response.Consents, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConsents")
+	}
+
+	var r0 *response.Consents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetConsents) (*response.Consents, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetConsents) *response.Consents); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Consents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.GetConsents) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) GetDataExport(origCtx context.Context, payload request.GetDataExport) (*response.DataExport, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataExport")
+	}
+
+	var r0 *response.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetDataExport) (*response.DataExport, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetDataExport) *response.DataExport); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.GetDataExport) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMarketingConsent provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) GetMarketingConsent(origCtx context.Context, payload request.GetMarketingConsent) (*response.MarketingConsent, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMarketingConsent")
+	}
+
+	var r0 *response.MarketingConsent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetMarketingConsent) (*response.MarketingConsent, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetMarketingConsent) *response.MarketingConsent); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.MarketingConsent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.GetMarketingConsent) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreferences provides a mock function with given fields: origCtx, payload
+func (_m *UsecaseQuery) GetPreferences(origCtx context.Context, payload request.GetPreferences) (*response.Preferences, error) {
+	ret := _m.Called(origCtx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreferences")
+	}
+
+	var r0 *response.Preferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetPreferences) (*response.Preferences, error)); ok {
+		return rf(origCtx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, request.GetPreferences) *response.Preferences); ok {
+		r0 = rf(origCtx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.Preferences)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, request.GetPreferences) error); ok {
+		r1 = rf(origCtx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProfile provides a mock function with given fields: origCtx, payload
 func (_m *UsecaseQuery) GetProfile(origCtx context.Context, payload request.GetProfile) (*response.GetProfile, error) {
 	ret := _m.Called(origCtx, payload)
@@ -46,6 +256,36 @@ func (_m *UsecaseQuery) GetProfile(origCtx context.Context, payload request.GetP
 	return r0, r1
 }
 
+// GetTermsVersion provides a mock function with given fields: origCtx
+func (_m *UsecaseQuery) GetTermsVersion(origCtx context.Context) (*response.TermsVersion, error) {
+	ret := _m.Called(origCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTermsVersion")
+	}
+
+	var r0 *response.TermsVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*response.TermsVersion, error)); ok {
+		return rf(origCtx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *response.TermsVersion); ok {
+		r0 = rf(origCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.TermsVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(origCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUsecaseQuery creates a new instance of UsecaseQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUsecaseQuery(t interface {
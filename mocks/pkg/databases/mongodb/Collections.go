@@ -76,6 +76,46 @@ func (_m *Collections) CountData(payload mongodb.CountData, ctx context.Context)
 	return r0
 }
 
+// CreateIndex provides a mock function with given fields: payload, ctx
+func (_m *Collections) CreateIndex(payload mongodb.CreateIndex, ctx context.Context) <-chan helpers.Result {
+	ret := _m.Called(payload, ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIndex")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(mongodb.CreateIndex, context.Context) <-chan helpers.Result); ok {
+		r0 = rf(payload, ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
+// DeleteMany provides a mock function with given fields: payload, ctx
+func (_m *Collections) DeleteMany(payload mongodb.DeleteMany, ctx context.Context) <-chan helpers.Result {
+	ret := _m.Called(payload, ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMany")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(mongodb.DeleteMany, context.Context) <-chan helpers.Result); ok {
+		r0 = rf(payload, ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindAllData provides a mock function with given fields: payload, ctx
 func (_m *Collections) FindAllData(payload mongodb.FindAllData, ctx context.Context) <-chan helpers.Result {
 	ret := _m.Called(payload, ctx)
@@ -96,6 +136,26 @@ func (_m *Collections) FindAllData(payload mongodb.FindAllData, ctx context.Cont
 	return r0
 }
 
+// FindByCursor provides a mock function with given fields: payload, ctx
+func (_m *Collections) FindByCursor(payload mongodb.FindByCursor, ctx context.Context) <-chan helpers.Result {
+	ret := _m.Called(payload, ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCursor")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(mongodb.FindByCursor, context.Context) <-chan helpers.Result); ok {
+		r0 = rf(payload, ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // FindOne provides a mock function with given fields: payload, ctx
 func (_m *Collections) FindOne(payload mongodb.FindOne, ctx context.Context) <-chan helpers.Result {
 	ret := _m.Called(payload, ctx)
@@ -156,6 +216,26 @@ func (_m *Collections) InsertOne(payload mongodb.InsertOne, ctx context.Context)
 	return r0
 }
 
+// UpdateMany provides a mock function with given fields: payload, ctx
+func (_m *Collections) UpdateMany(payload mongodb.UpdateOne, ctx context.Context) <-chan helpers.Result {
+	ret := _m.Called(payload, ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(mongodb.UpdateOne, context.Context) <-chan helpers.Result); ok {
+		r0 = rf(payload, ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // UpdateOne provides a mock function with given fields: payload, ctx
 func (_m *Collections) UpdateOne(payload mongodb.UpdateOne, ctx context.Context) <-chan helpers.Result {
 	ret := _m.Called(payload, ctx)
@@ -176,6 +256,26 @@ func (_m *Collections) UpdateOne(payload mongodb.UpdateOne, ctx context.Context)
 	return r0
 }
 
+// UpsertMany provides a mock function with given fields: payload, ctx
+func (_m *Collections) UpsertMany(payload mongodb.UpsertMany, ctx context.Context) <-chan helpers.Result {
+	ret := _m.Called(payload, ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertMany")
+	}
+
+	var r0 <-chan helpers.Result
+	if rf, ok := ret.Get(0).(func(mongodb.UpsertMany, context.Context) <-chan helpers.Result); ok {
+		r0 = rf(payload, ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan helpers.Result)
+		}
+	}
+
+	return r0
+}
+
 // UpsertOne provides a mock function with given fields: payload, ctx
 func (_m *Collections) UpsertOne(payload mongodb.UpdateOne, ctx context.Context) <-chan helpers.Result {
 	ret := _m.Called(payload, ctx)